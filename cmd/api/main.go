@@ -1,15 +1,29 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"github.com/modernband/booking/internal/database"
 	"github.com/modernband/booking/internal/handlers"
+	"github.com/modernband/booking/internal/invoice"
+	"github.com/modernband/booking/internal/jobs"
+	"github.com/modernband/booking/internal/notify"
+	"github.com/modernband/booking/internal/reminder"
+	"github.com/modernband/booking/internal/sms"
+	"github.com/modernband/booking/internal/storage/backend"
 )
 
+// shutdownTimeout bounds waiting for in-flight requests when the server stops
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load .env file if it exists
 	_ = godotenv.Load()
@@ -20,17 +34,88 @@ func main() {
 		port = "8081"
 	}
 
-	// Initialize database connection
-	_ = database.GetDB() // Initialize MongoDB connection
-	defer database.Close()
+	// Initialize the storage backend
+	store, closeStore, err := backend.Open()
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+
+	// Background work stops when the process is interrupted or terminated
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var background sync.WaitGroup
+
+	// Deliver notifications from the outbox in the background
+	sender := sms.LogSender{}
+	providers, err := notify.ProvidersFromEnv(sender)
+	if err != nil {
+		log.Fatalf("Failed to configure notifications: %v", err)
+	}
+	notifier := notify.New(store.Notifications, invoice.LetterheadFromEnv().Name, providers)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		notifier.Run(ctx, 30*time.Second)
+	}()
+
+	// Run archiving, OTP expiry, reminders and trash purging as scheduled background jobs
+	reminderDays, err := reminder.DaysFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure reminders: %v", err)
+	}
+	archiveAfterDays, err := jobs.ArchiveAfterDaysFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure archiving: %v", err)
+	}
+	trashRetentionDays, err := jobs.TrashRetentionDaysFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure the trash: %v", err)
+	}
+	reminders := reminder.New(store, notifier, reminderDays)
+	runner := jobs.NewRunner(store.Jobs, jobs.Builtin(store, reminders, archiveAfterDays, trashRetentionDays))
+	if err := runner.Start(ctx); err != nil {
+		log.Fatalf("Failed to start background jobs: %v", err)
+	}
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		runner.Run(ctx, 30*time.Second)
+	}()
 
 	// Set up the router
 	router := gin.Default()
-	handlers.SetupRoutes(router)
+	handlers.SetupRoutes(router, handlers.NewHandler(store, sender, notifier, runner))
 
 	// Start the server
-	log.Printf("Server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	server := &http.Server{Addr: ":" + port, Handler: router}
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	failed := false
+	select {
+	case err := <-serverErr:
+		log.Printf("Failed to start server: %v", err)
+		failed = true
+	case <-ctx.Done():
+		log.Printf("Shutting down")
+	}
+	stop()
+
+	// Finish in-flight requests, then let running jobs record their end and
+	// release their locks before the store is closed
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server: %v", err)
+	}
+	background.Wait()
+	runner.Stop()
+	closeStore()
+
+	if failed {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,77 @@
+// Command import loads historical bookings or employees from a CSV file into the
+// configured storage backend.
+//
+//	import [-dry-run] [-by name] [-batch size] bookings|employees file.csv
+//
+// It prints the import report as JSON and exits with status 2 if any row was
+// rejected, or 1 if the import could not run.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/modernband/booking/internal/importer"
+	"github.com/modernband/booking/internal/storage"
+	"github.com/modernband/booking/internal/storage/backend"
+)
+
+func main() {
+	// Load .env file if it exists
+	_ = godotenv.Load()
+
+	var opts importer.Options
+	flag.BoolVar(&opts.DryRun, "dry-run", false, "validate every row without importing")
+	flag.StringVar(&opts.By, "by", "import", "name recorded as the author of imported bookings")
+	flag.IntVar(&opts.BatchSize, "batch", importer.DefaultBatchSize, "rows inserted at a time")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: import [flags] bookings|employees file.csv")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var run func(context.Context, *storage.Store, io.Reader, importer.Options) (*importer.Report, error)
+	switch kind := flag.Arg(0); kind {
+	case "bookings":
+		run = importer.ImportBookings
+	case "employees":
+		run = importer.ImportEmployees
+	default:
+		log.Fatalf("Unknown kind %q, expected bookings or employees", kind)
+	}
+
+	file, err := os.Open(flag.Arg(1))
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", flag.Arg(1), err)
+	}
+	defer file.Close()
+
+	store, closeStore, err := backend.Open()
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+
+	report, err := run(context.Background(), store, file, opts)
+	closeStore()
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	}
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+	if len(report.Errors) > 0 {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,102 @@
+package auth
+
+// Role identifies what a user is allowed to do
+type Role string
+
+// Permission names a single guarded action
+type Permission string
+
+const (
+	RoleOwner      Role = "owner"
+	RoleManager    Role = "manager"
+	RoleAccountant Role = "accountant"
+	RoleCrew       Role = "crew"
+)
+
+const (
+	PermBookingsRead    Permission = "bookings:read"
+	PermBookingsWrite   Permission = "bookings:write"
+	PermBookingsDelete  Permission = "bookings:delete"
+	PermEmployeesRead   Permission = "employees:read"
+	PermEmployeesWrite  Permission = "employees:write"
+	PermEmployeesDelete Permission = "employees:delete"
+	PermPaymentsWrite   Permission = "payments:write"
+	PermPaymentsDelete  Permission = "payments:delete"
+	PermAdminsManage    Permission = "admins:manage"
+	PermPricingRead     Permission = "pricing:read"
+	PermPricingManage   Permission = "pricing:manage"
+	PermCapacityManage  Permission = "capacity:manage"
+	PermWagesManage     Permission = "wages:manage"
+	PermInventoryManage Permission = "inventory:manage"
+)
+
+// permissions is the role/permission matrix. Crew have no global permissions;
+// they may only read their own employee record.
+var permissions = map[Role]map[Permission]bool{
+	RoleOwner: {
+		PermBookingsRead:    true,
+		PermBookingsWrite:   true,
+		PermBookingsDelete:  true,
+		PermEmployeesRead:   true,
+		PermEmployeesWrite:  true,
+		PermEmployeesDelete: true,
+		PermPaymentsWrite:   true,
+		PermPaymentsDelete:  true,
+		PermAdminsManage:    true,
+		PermPricingRead:     true,
+		PermPricingManage:   true,
+		PermCapacityManage:  true,
+		PermWagesManage:     true,
+		PermInventoryManage: true,
+	},
+	RoleManager: {
+		PermBookingsRead:    true,
+		PermBookingsWrite:   true,
+		PermBookingsDelete:  true,
+		PermEmployeesRead:   true,
+		PermEmployeesWrite:  true,
+		PermPaymentsWrite:   true,
+		PermPricingRead:     true,
+		PermPricingManage:   true,
+		PermCapacityManage:  true,
+		PermWagesManage:     true,
+		PermInventoryManage: true,
+	},
+	RoleAccountant: {
+		PermBookingsRead:   true,
+		PermEmployeesRead:  true,
+		PermPaymentsWrite:  true,
+		PermPaymentsDelete: true,
+		PermPricingRead:    true,
+	},
+	RoleCrew: {},
+}
+
+// Can reports whether the role grants the given permission
+func (r Role) Can(p Permission) bool {
+	return permissions[r][p]
+}
+
+// IsValid reports whether the role is one of the known roles
+func (r Role) IsValid() bool {
+	_, ok := permissions[r]
+	return ok
+}
+
+// legacyOwnerRole is the stored role of admin users created before roles existed
+const legacyOwnerRole = ""
+
+// AdminRole resolves the stored role of an admin user. Admin records created
+// before roles existed have no role and keep full access as owners.
+func AdminRole(stored string) Role {
+	if stored == legacyOwnerRole {
+		return RoleOwner
+	}
+	return Role(stored)
+}
+
+// OwnerRoles lists the stored admin roles that AdminRole resolves to RoleOwner,
+// for backends that look up owners in a query
+func OwnerRoles() []string {
+	return []string{string(RoleOwner), legacyOwnerRole}
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// defaultTokenTTL is used when TokenTTL is not set in the environment
+	defaultTokenTTL = 24 * time.Hour
+	issuer          = "modernband-booking"
+)
+
+var (
+	secret     []byte
+	tokenTTL   time.Duration
+	secretOnce sync.Once
+
+	// ErrInvalidToken is returned when a token cannot be parsed or verified
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// Claims represents the data carried inside an access token
+type Claims struct {
+	Username string `json:"username"`
+	Role     Role   `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// loadConfig reads the signing secret and token lifetime from the environment
+func loadConfig() {
+	secretOnce.Do(func() {
+		if s := os.Getenv("JWTSecret"); s != "" {
+			secret = []byte(s)
+		} else {
+			// Fall back to a random secret so tokens are never signed with a known key.
+			// Tokens will not survive a restart in this mode.
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				log.Fatalf("Failed to generate JWT secret: %v", err)
+			}
+			log.Println("Warning: JWTSecret environment variable not set, using a random secret")
+		}
+
+		tokenTTL = defaultTokenTTL
+		if ttl := os.Getenv("TokenTTL"); ttl != "" {
+			d, err := time.ParseDuration(ttl)
+			if err != nil || d <= 0 {
+				log.Printf("Warning: invalid TokenTTL %q, using default: %s", ttl, defaultTokenTTL)
+			} else {
+				tokenTTL = d
+			}
+		}
+	})
+}
+
+// GenerateToken issues a signed access token for the given user
+func GenerateToken(userID, username string, role Role) (string, time.Time, error) {
+	loadConfig()
+
+	now := time.Now()
+	expiresAt := now.Add(tokenTTL)
+	claims := Claims{
+		Username: username,
+		Role:     role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Issuer:    issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error signing token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// ParseToken verifies a signed access token and returns its claims
+func ParseToken(tokenString string) (*Claims, error) {
+	loadConfig()
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !token.Valid || !claims.Role.IsValid() {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
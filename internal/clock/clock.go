@@ -0,0 +1,8 @@
+// Package clock holds the time zone the band works in.
+package clock
+
+import "time"
+
+// IndianTime is the zone the band's days are reckoned in: financial years and
+// invoice dates, job schedules and reminder times
+var IndianTime = time.FixedZone("IST", 5*60*60+30*60)
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/auth"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// adminRepository implements storage.AdminRepository on MongoDB
+type adminRepository struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+	locks  *mongo.Collection // Shared with bookings; one lock document serializes changes to owners
+}
+
+// ownerRoles matches the role of owners, including admin users stored before
+// roles existed, whose role may be missing altogether
+var ownerRoles = func() bson.M {
+	roles := bson.A{nil}
+	for _, role := range auth.OwnerRoles() {
+		roles = append(roles, role)
+	}
+	return bson.M{"$in": roles}
+}()
+
+// withAdminsLocked runs fn in a transaction after writing the admin users' lock
+// document, so concurrent changes checked by fn conflict
+func (r *adminRepository) withAdminsLocked(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		_, err := r.locks.UpdateOne(sessCtx,
+			bson.M{"_id": "admins"},
+			bson.M{"$set": bson.M{"updated_at": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// keepOwner returns ErrLastOwner if the admin user is an owner and no other
+// owner exists, so they may not stop being one. It must run inside withAdminsLocked.
+func (r *adminRepository) keepOwner(sessCtx mongo.SessionContext, id primitive.ObjectID) error {
+	owner, err := r.coll.CountDocuments(sessCtx, bson.M{"_id": id, "role": ownerRoles})
+	if err != nil {
+		return err
+	}
+	if owner == 0 {
+		return nil
+	}
+	others, err := r.coll.CountDocuments(sessCtx, bson.M{"_id": bson.M{"$ne": id}, "role": ownerRoles})
+	if err != nil {
+		return err
+	}
+	if others == 0 {
+		return storage.ErrLastOwner
+	}
+	return nil
+}
+
+func (r *adminRepository) Create(ctx context.Context, admin *models.AdminUser) error {
+	if admin.ID.IsZero() {
+		admin.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, admin)
+	if mongo.IsDuplicateKeyError(err) {
+		return storage.ErrDuplicate
+	}
+	return err
+}
+
+func (r *adminRepository) CreateFirst(ctx context.Context, admin *models.AdminUser) error {
+	if admin.ID.IsZero() {
+		admin.ID = primitive.NewObjectID()
+	}
+	err := r.withAdminsLocked(ctx, func(sessCtx mongo.SessionContext) error {
+		count, err := r.coll.CountDocuments(sessCtx, bson.M{})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return storage.ErrConflict
+		}
+		_, err = r.coll.InsertOne(sessCtx, admin)
+		return err
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return storage.ErrDuplicate
+	}
+	return err
+}
+
+func (r *adminRepository) Count(ctx context.Context) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{})
+}
+
+func (r *adminRepository) Exists(ctx context.Context, username string) (bool, error) {
+	count, err := r.coll.CountDocuments(ctx, bson.M{"username": username})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *adminRepository) FindByUsername(ctx context.Context, username string) (*models.AdminUser, error) {
+	var admin models.AdminUser
+	err := r.coll.FindOne(ctx, bson.M{"username": username}).Decode(&admin)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &admin, nil
+}
+
+func (r *adminRepository) List(ctx context.Context) ([]models.AdminUser, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	admins := []models.AdminUser{}
+	if err = cursor.All(ctx, &admins); err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+func (r *adminRepository) Update(ctx context.Context, admin *models.AdminUser) error {
+	return r.withAdminsLocked(ctx, func(sessCtx mongo.SessionContext) error {
+		if !admin.IsOwner() {
+			if err := r.keepOwner(sessCtx, admin.ID); err != nil {
+				return err
+			}
+		}
+		result, err := r.coll.UpdateOne(sessCtx,
+			bson.M{"_id": admin.ID},
+			bson.M{"$set": bson.M{
+				"name":          admin.Name,
+				"mobile_number": admin.MobileNumber,
+				"email":         admin.Email,
+				"password":      admin.Password,
+				"role":          admin.Role,
+				"updated_at":    admin.UpdatedAt,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return storage.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *adminRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return r.withAdminsLocked(ctx, func(sessCtx mongo.SessionContext) error {
+		if err := r.keepOwner(sessCtx, id); err != nil {
+			return err
+		}
+		result, err := r.coll.DeleteOne(sessCtx, bson.M{"_id": id})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return storage.ErrNotFound
+		}
+		return nil
+	})
+}
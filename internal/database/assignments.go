@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// assignmentRepository implements storage.AssignmentRepository on MongoDB
+type assignmentRepository struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+	locks  *mongo.Collection // Shared with bookings; crew locks are keyed by employee and day
+}
+
+func (r *assignmentRepository) Create(ctx context.Context, assignment *models.CrewAssignment) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		sameDay, err := lockCrewDay(sessCtx, r.locks, r.coll, assignment)
+		if err != nil {
+			return nil, err
+		}
+		for i := range sameDay {
+			if sameDay[i].BookingID == assignment.BookingID {
+				return nil, storage.ErrDuplicate
+			}
+			if sameDay[i].Overlaps(assignment) {
+				return nil, storage.ErrConflict
+			}
+		}
+
+		result, err := r.coll.InsertOne(sessCtx, assignment)
+		if err != nil {
+			return nil, err
+		}
+		assignment.ID = result.InsertedID.(primitive.ObjectID)
+		return nil, nil
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return storage.ErrDuplicate
+	}
+	return err
+}
+
+// lockCrewDay writes the employee's lock document for the day of the assignment,
+// making concurrent assignments of the same employee conflict, and returns their
+// assignments outside the trash on that day
+func lockCrewDay(sessCtx mongo.SessionContext, locks, crew *mongo.Collection, assignment *models.CrewAssignment) ([]models.CrewAssignment, error) {
+	day := startOfDay(assignment.EventDate)
+	_, err := locks.UpdateOne(sessCtx,
+		bson.M{"_id": "crew:" + assignment.EmployeeID.Hex() + ":" + day.Format("2006-01-02")},
+		bson.M{"$set": bson.M{"updated_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := crew.Find(sessCtx, bson.M{
+		"employee_id": assignment.EmployeeID,
+		"event_date":  bson.M{"$gte": day, "$lt": day.AddDate(0, 0, 1)},
+		"deleted_at":  notDeleted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sameDay := []models.CrewAssignment{}
+	if err := cursor.All(sessCtx, &sameDay); err != nil {
+		return nil, err
+	}
+	return sameDay, nil
+}
+
+func (r *assignmentRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.CrewAssignment, error) {
+	return r.find(ctx, bson.M{"booking_id": bookingID, "deleted_at": notDeleted}, bson.D{{Key: "assigned_at", Value: 1}})
+}
+
+func (r *assignmentRepository) ListByEmployee(ctx context.Context, employeeID primitive.ObjectID, from time.Time) ([]models.CrewAssignment, error) {
+	filter := bson.M{"employee_id": employeeID, "deleted_at": notDeleted}
+	if !from.IsZero() {
+		filter["event_date"] = bson.M{"$gte": from}
+	}
+	return r.find(ctx, filter, bson.D{{Key: "event_date", Value: 1}, {Key: "time_slot", Value: 1}})
+}
+
+// find returns the assignments matching filter in the given order
+func (r *assignmentRepository) find(ctx context.Context, filter bson.M, sort bson.D) ([]models.CrewAssignment, error) {
+	cursor, err := r.coll.Find(ctx, filter, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	assignments := []models.CrewAssignment{}
+	if err = cursor.All(ctx, &assignments); err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+func (r *assignmentRepository) Delete(ctx context.Context, bookingID string, id primitive.ObjectID) error {
+	result, err := r.coll.DeleteOne(ctx, bson.M{"_id": id, "booking_id": bookingID, "deleted_at": notDeleted})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *assignmentRepository) DeleteByBooking(ctx context.Context, bookingID string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"booking_id": bookingID})
+	return err
+}
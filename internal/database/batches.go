@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// batches decodes the documents of cursor into slices of at most size and calls
+// fn with each as it fills, closing the cursor when done
+func batches[T any](ctx context.Context, cursor *mongo.Cursor, size int, fn func([]T) error) error {
+	defer cursor.Close(ctx)
+
+	batch := make([]T, 0, size)
+	for cursor.Next(ctx) {
+		var doc T
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		if batch = append(batch, doc); len(batch) == size {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]T, 0, size)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// insertMany inserts docs into coll in a transaction, so that a duplicate key
+// leaves none of them inserted, and passes each its new ID
+func insertMany[T any](ctx context.Context, client *mongo.Client, coll *mongo.Collection, docs []T, setID func(*T, primitive.ObjectID)) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		values := make([]interface{}, len(docs))
+		for i := range docs {
+			values[i] = &docs[i]
+		}
+		return coll.InsertMany(sessCtx, values)
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return storage.ErrDuplicate
+		}
+		return err
+	}
+	for i, id := range result.(*mongo.InsertManyResult).InsertedIDs {
+		setID(&docs[i], id.(primitive.ObjectID))
+	}
+	return nil
+}
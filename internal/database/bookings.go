@@ -0,0 +1,702 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// bookingRepository implements storage.BookingRepository on MongoDB
+type bookingRepository struct {
+	client    *mongo.Client
+	coll      *mongo.Collection
+	locks     *mongo.Collection // One document per event day, written to serialize capacity checks
+	revisions *mongo.Collection
+	crew      *mongo.Collection
+	earnings  *mongo.Collection
+	employees *mongo.Collection // Read when restoring a booking's crew
+	otps      *mongo.Collection // Phone verifications are used up by new bookings
+}
+
+func (r *bookingRepository) Create(ctx context.Context, booking *models.Booking) error {
+	result, err := r.coll.InsertOne(ctx, booking)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return storage.ErrDuplicate
+		}
+		return err
+	}
+	booking.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *bookingRepository) CreateMany(ctx context.Context, bookings []models.Booking) error {
+	return insertMany(ctx, r.client, r.coll, bookings, func(b *models.Booking, id primitive.ObjectID) { b.ID = id })
+}
+
+func (r *bookingRepository) CreateWithinCapacity(ctx context.Context, booking *models.Booking, limits storage.CapacityLimits, verifiedSince time.Time) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := r.checkCapacity(sessCtx, booking, limits); err != nil {
+			return nil, err
+		}
+
+		// Use up the phone's verification only together with the insert
+		used, err := r.otps.DeleteOne(sessCtx, bson.M{"phone": booking.Phone, "verified_at": bson.M{"$gt": verifiedSince}})
+		if err != nil {
+			return nil, err
+		}
+		if used.DeletedCount == 0 {
+			return nil, storage.ErrNotVerified
+		}
+
+		result, err := r.coll.InsertOne(sessCtx, booking)
+		if err != nil {
+			return nil, err
+		}
+		booking.ID = result.InsertedID.(primitive.ObjectID)
+		return nil, nil
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return storage.ErrDuplicate
+	}
+	return err
+}
+
+// checkCapacity returns ErrCapacityExceeded if the other bookings on the booking's
+// day and time slot already reach limits. It must run inside a transaction.
+func (r *bookingRepository) checkCapacity(sessCtx mongo.SessionContext, booking *models.Booking, limits storage.CapacityLimits) error {
+	day := startOfDay(booking.EventDate)
+
+	// Writing the day's lock document makes concurrent bookings for the same day
+	// conflict, so one of them is retried and sees the other's write
+	_, err := r.locks.UpdateOne(sessCtx,
+		bson.M{"_id": day.Format("2006-01-02")},
+		bson.M{"$set": bson.M{"updated_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	dayFilter := bson.M{
+		"event_date": bson.M{"$gte": day, "$lt": day.AddDate(0, 0, 1)},
+		"status":     bson.M{"$ne": models.BookingStatusCancelled},
+		"booking_id": bson.M{"$ne": booking.BookingID},
+		"deleted_at": notDeleted,
+	}
+
+	if limits.PerDay > 0 {
+		count, err := r.coll.CountDocuments(sessCtx, dayFilter)
+		if err != nil {
+			return err
+		}
+		if count >= int64(limits.PerDay) {
+			return storage.ErrCapacityExceeded
+		}
+	}
+
+	if limits.PerSlot > 0 && booking.TimeSlot != "" {
+		dayFilter["time_slot"] = booking.TimeSlot
+		count, err := r.coll.CountDocuments(sessCtx, dayFilter)
+		if err != nil {
+			return err
+		}
+		if count >= int64(limits.PerSlot) {
+			return storage.ErrCapacityExceeded
+		}
+		delete(dayFilter, "time_slot")
+	}
+
+	if len(limits.Stock) > 0 {
+		reservations, err := r.reservedEquipment(sessCtx, dayFilter)
+		if err != nil {
+			return err
+		}
+		reserved := map[string]int{}
+		for _, res := range reservations {
+			reserved[res.Item] += res.Quantity
+		}
+		if limits.StockExceeded(reserved, booking.Equipment()) {
+			return storage.ErrInsufficientStock
+		}
+	}
+	return nil
+}
+
+func (r *bookingRepository) ReservedEquipment(ctx context.Context, from, to time.Time) ([]models.EquipmentReservation, error) {
+	return r.reservedEquipment(ctx, bson.M{
+		"event_date": bson.M{"$gte": startOfDay(from), "$lt": startOfDay(to).AddDate(0, 0, 1)},
+		"status":     bson.M{"$ne": models.BookingStatusCancelled},
+		"deleted_at": notDeleted,
+	})
+}
+
+// reservedEquipment totals the equipment requested by the bookings matching filter
+// per event day and item, ordered by day and item
+func (r *bookingRepository) reservedEquipment(ctx context.Context, filter bson.M) ([]models.EquipmentReservation, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$event_date"}},
+			"lights":      bson.M{"$sum": nonNegative("$number_of_lights")},
+			"dhols":       bson.M{"$sum": nonNegative("$number_of_dhols")},
+			"ghoda_baggi": bson.M{"$sum": nonNegative("$ghoda_baggi")},
+			"ghodi":       bson.M{"$sum": bson.M{"$cond": bson.A{"$ghodi_for_baraat", 1, 0}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var days []struct {
+		Day        string `bson:"_id"`
+		Lights     int    `bson:"lights"`
+		Dhols      int    `bson:"dhols"`
+		GhodaBaggi int    `bson:"ghoda_baggi"`
+		Ghodi      int    `bson:"ghodi"`
+	}
+	if err = cursor.All(ctx, &days); err != nil {
+		return nil, err
+	}
+
+	reservations := []models.EquipmentReservation{}
+	for _, d := range days {
+		// Items in code order, skipping those no booking requested
+		for _, res := range []models.EquipmentReservation{
+			{Day: d.Day, Item: models.ItemDhols, Quantity: d.Dhols},
+			{Day: d.Day, Item: models.ItemGhodaBaggi, Quantity: d.GhodaBaggi},
+			{Day: d.Day, Item: models.ItemGhodi, Quantity: d.Ghodi},
+			{Day: d.Day, Item: models.ItemLights, Quantity: d.Lights},
+		} {
+			if res.Quantity > 0 {
+				reservations = append(reservations, res)
+			}
+		}
+	}
+	return reservations, nil
+}
+
+// nonNegative returns an expression for a count field that reads negative values
+// as 0, so a bad stored count cannot free equipment other bookings are using
+func nonNegative(field string) bson.M {
+	return bson.M{"$max": bson.A{field, 0}}
+}
+
+func (r *bookingRepository) CountBySlot(ctx context.Context, from, to time.Time) ([]models.SlotCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"event_date": bson.M{"$gte": startOfDay(from), "$lt": startOfDay(to).AddDate(0, 0, 1)},
+			"status":     bson.M{"$ne": models.BookingStatusCancelled},
+			"deleted_at": notDeleted,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"day":  bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$event_date"}},
+				"slot": bson.M{"$ifNull": bson.A{"$time_slot", ""}},
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "day": "$_id.day", "slot": "$_id.slot", "count": 1}}},
+		{{Key: "$sort", Value: bson.D{{Key: "day", Value: 1}, {Key: "slot", Value: 1}}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := []models.SlotCount{}
+	if err = cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *bookingRepository) Exists(ctx context.Context, bookingID string) (bool, error) {
+	count, err := r.coll.CountDocuments(ctx, bson.M{"booking_id": bookingID})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *bookingRepository) FindByBookingID(ctx context.Context, bookingID string) (*models.Booking, error) {
+	var booking models.Booking
+	err := r.coll.FindOne(ctx, bson.M{"booking_id": bookingID, "deleted_at": notDeleted}).Decode(&booking)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (r *bookingRepository) FindByPhone(ctx context.Context, phone string) ([]models.Booking, error) {
+	return r.find(ctx, bson.M{"phone": phone, "deleted_at": notDeleted})
+}
+
+func (r *bookingRepository) Search(ctx context.Context, query string, limit int) ([]models.Booking, error) {
+	return search(ctx, r.coll, query, bookingSearchWeights, limit, func(b *models.Booking) primitive.ObjectID { return b.ID })
+}
+
+// bookingSortFields maps listing sort fields to document fields
+var bookingSortFields = map[string]string{
+	storage.SortCreatedAt: "created_at",
+	storage.SortEventDate: "event_date",
+	storage.SortAmount:    "amount",
+	storage.SortName:      "name",
+}
+
+// caseInsensitive compares strings ignoring case; the city index uses the same collation
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+// bookingQuery returns the query selecting the bookings that match filter
+func bookingQuery(filter storage.BookingFilter) bson.M {
+	query := bson.M{"deleted_at": notDeleted}
+	if filter.Status != "" {
+		query["status"] = statusMatch(filter.Status)
+	}
+	dateRange := bson.M{}
+	if !filter.From.IsZero() {
+		dateRange["$gte"] = filter.From
+	}
+	if !filter.Before.IsZero() {
+		dateRange["$lt"] = filter.Before
+	}
+	if len(dateRange) > 0 {
+		query["event_date"] = dateRange
+	}
+	if filter.City != "" {
+		query["city"] = filter.City
+	}
+	if filter.PackageType != "" {
+		query["package_type"] = filter.PackageType
+	}
+	if filter.Phone != "" {
+		query["phone"] = filter.Phone
+	}
+	switch filter.Archived {
+	case storage.ArchivedExclude:
+		query["archived_at"] = bson.M{"$exists": false}
+	case storage.ArchivedOnly:
+		query["archived_at"] = bson.M{"$exists": true}
+	}
+	return query
+}
+
+// bookingPageOptions returns find options selecting page
+func bookingPageOptions(page storage.BookingPage) *options.FindOptions {
+	field, ok := bookingSortFields[page.Sort]
+	if !ok {
+		field = "created_at"
+	}
+	direction := 1
+	if page.Desc {
+		direction = -1
+	}
+	opts := options.Find().
+		SetCollation(caseInsensitive).
+		SetSort(bson.D{{Key: field, Value: direction}, {Key: "booking_id", Value: 1}}).
+		SetSkip(int64(page.Offset))
+	if page.Limit > 0 {
+		opts.SetLimit(int64(page.Limit))
+	}
+	return opts
+}
+
+func (r *bookingRepository) List(ctx context.Context, filter storage.BookingFilter, page storage.BookingPage) ([]models.Booking, int64, error) {
+	query := bookingQuery(filter)
+	total, err := r.coll.CountDocuments(ctx, query, options.Count().SetCollation(caseInsensitive))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor, err := r.coll.Find(ctx, query, bookingPageOptions(page))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	bookings := []models.Booking{}
+	if err = cursor.All(ctx, &bookings); err != nil {
+		return nil, 0, err
+	}
+	return bookings, total, nil
+}
+
+func (r *bookingRepository) Batches(ctx context.Context, filter storage.BookingFilter, page storage.BookingPage, size int, fn func([]models.Booking) error) error {
+	cursor, err := r.coll.Find(ctx, bookingQuery(filter), bookingPageOptions(page).SetBatchSize(int32(size)))
+	if err != nil {
+		return err
+	}
+	return batches(ctx, cursor, size, fn)
+}
+
+func (r *bookingRepository) UpdateStatus(ctx context.Context, bookingID, from string, change models.StatusChange) error {
+	if change.To != models.BookingStatusCompleted {
+		return r.setStatus(ctx, bookingID, from, change)
+	}
+
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// Completing the booking and accruing its crew's wages happen together
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := r.setStatus(sessCtx, bookingID, from, change); err != nil {
+			return nil, err
+		}
+		return nil, r.accrueEarnings(sessCtx, bookingID, change.At)
+	})
+	return err
+}
+
+// setStatus moves a booking from status from to change.To
+func (r *bookingRepository) setStatus(ctx context.Context, bookingID, from string, change models.StatusChange) error {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"booking_id": bookingID, "status": statusMatch(from), "deleted_at": notDeleted},
+		bson.M{
+			"$set":  bson.M{"status": change.To},
+			"$push": bson.M{"status_history": change},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		count, err := r.coll.CountDocuments(ctx, bson.M{"booking_id": bookingID, "deleted_at": notDeleted})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return storage.ErrNotFound
+		}
+		return storage.ErrConflict
+	}
+	return nil
+}
+
+// statusMatch returns a filter value matching bookings in status. Bookings stored
+// before statuses existed have none and count as confirmed.
+func statusMatch(status string) interface{} {
+	if status == models.BookingStatusConfirmed {
+		return bson.M{"$in": bson.A{status, "", nil}}
+	}
+	return status
+}
+
+// find returns the bookings matching filter sorted by created_at in descending order
+func (r *bookingRepository) find(ctx context.Context, filter bson.M) ([]models.Booking, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	bookings := []models.Booking{}
+	if err = cursor.All(ctx, &bookings); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *bookingRepository) Update(ctx context.Context, booking *models.Booking, revision *models.BookingRevision, limits *storage.CapacityLimits) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if limits != nil {
+			if err := r.checkCapacity(sessCtx, booking, *limits); err != nil {
+				return nil, err
+			}
+		}
+
+		result, err := r.coll.UpdateOne(sessCtx,
+			bson.M{
+				"booking_id": booking.BookingID,
+				"revision":   revisionMatch(booking.Revision - 1),
+				"status":     statusMatch(booking.CurrentStatus()),
+				"deleted_at": notDeleted,
+			},
+			bson.M{"$set": bson.M{
+				"name":             booking.Name,
+				"email":            booking.Email,
+				"additional_phone": booking.AdditionalPhone,
+				"package_type":     booking.PackageType,
+				"event_date":       booking.EventDate,
+				"venue":            booking.Venue,
+				"city":             booking.City,
+				"customization":    booking.Customization,
+				"band_time":        booking.BandTime,
+				"custom_time_slot": booking.CustomTimeSlot,
+				"time_slot":        booking.TimeSlot,
+				"number_of_people": booking.NumberOfPeople,
+				"number_of_lights": booking.NumberOfLights,
+				"number_of_dhols":  booking.NumberOfDhols,
+				"ghoda_baggi":      booking.GhodaBaggi,
+				"ghodi_for_baraat": booking.GhodiForBaraat,
+				"fireworks":        booking.Fireworks,
+				"fireworks_amount": booking.FireworksAmount,
+				"flower_canon":     booking.FlowerCanon,
+				"doli_for_vidai":   booking.DoliForVidai,
+				"amount":           booking.Amount,
+				"quote":            booking.Quote,
+				"revision":         booking.Revision,
+			}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			count, err := r.coll.CountDocuments(sessCtx, bson.M{"booking_id": booking.BookingID, "deleted_at": notDeleted})
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return nil, storage.ErrNotFound
+			}
+			return nil, storage.ErrConflict
+		}
+
+		inserted, err := r.revisions.InsertOne(sessCtx, revision)
+		if err != nil {
+			return nil, err
+		}
+		revision.ID = inserted.InsertedID.(primitive.ObjectID)
+		return nil, nil
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return storage.ErrConflict
+	}
+	return err
+}
+
+// revisionMatch returns a filter value matching bookings at revision. Bookings
+// stored before revisions existed have none and count as revision 0.
+func revisionMatch(revision int) interface{} {
+	if revision == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return revision
+}
+
+func (r *bookingRepository) ListRevisions(ctx context.Context, bookingID string) ([]models.BookingRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: 1}})
+
+	cursor, err := r.revisions.Find(ctx, bson.M{"booking_id": bookingID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []models.BookingRevision{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *bookingRepository) Delete(ctx context.Context, bookingID, by string, at time.Time) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// The crew follows the booking into the trash
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		result, err := r.coll.UpdateOne(sessCtx,
+			bson.M{"booking_id": bookingID, "deleted_at": notDeleted},
+			bson.M{"$set": bson.M{"deleted_at": at, "deleted_by": by}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, storage.ErrNotFound
+		}
+		_, err = r.crew.UpdateMany(sessCtx,
+			bson.M{"booking_id": bookingID, "deleted_at": notDeleted},
+			bson.M{"$set": bson.M{"deleted_at": at}},
+		)
+		return nil, err
+	})
+	return err
+}
+
+func (r *bookingRepository) FindDeleted(ctx context.Context, bookingID string) (*models.Booking, error) {
+	var booking models.Booking
+	err := r.coll.FindOne(ctx, bson.M{"booking_id": bookingID, "deleted_at": bson.M{"$exists": true}}).Decode(&booking)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (r *bookingRepository) Restore(ctx context.Context, bookingID string, limits *storage.CapacityLimits) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		var booking models.Booking
+		err := r.coll.FindOne(sessCtx, bson.M{"booking_id": bookingID, "deleted_at": bson.M{"$exists": true}}).Decode(&booking)
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if limits != nil {
+			if err := r.checkCapacity(sessCtx, &booking, *limits); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = r.coll.UpdateOne(sessCtx,
+			bson.M{"_id": booking.ID},
+			bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		return nil, r.restoreCrew(sessCtx, bookingID)
+	})
+	return err
+}
+
+// restoreCrew brings back the crew of a booking taken out of the trash. The
+// assignments of employees now in the trash, or since assigned to another event
+// at the same time, are removed instead.
+func (r *bookingRepository) restoreCrew(sessCtx mongo.SessionContext, bookingID string) error {
+	cursor, err := r.crew.Find(sessCtx, bson.M{"booking_id": bookingID, "deleted_at": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+	trashed := []models.CrewAssignment{}
+	if err := cursor.All(sessCtx, &trashed); err != nil {
+		return err
+	}
+
+	for i := range trashed {
+		a := &trashed[i]
+		employees, err := r.employees.CountDocuments(sessCtx, bson.M{"_id": a.EmployeeID, "deleted_at": notDeleted})
+		if err != nil {
+			return err
+		}
+		free := employees > 0
+		if free {
+			sameDay, err := lockCrewDay(sessCtx, r.locks, r.crew, a)
+			if err != nil {
+				return err
+			}
+			for j := range sameDay {
+				free = free && !sameDay[j].Overlaps(a)
+			}
+		}
+
+		if free {
+			_, err = r.crew.UpdateOne(sessCtx, bson.M{"_id": a.ID}, bson.M{"$unset": bson.M{"deleted_at": ""}})
+		} else {
+			_, err = r.crew.DeleteOne(sessCtx, bson.M{"_id": a.ID})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *bookingRepository) ListDeleted(ctx context.Context, limit int) ([]models.Booking, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "booking_id", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.coll.Find(ctx, bson.M{"deleted_at": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	bookings := []models.Booking{}
+	if err = cursor.All(ctx, &bookings); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *bookingRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.EndSession(ctx)
+
+	// Remove the bookings with the crew that followed them into the trash
+	purged, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{"deleted_at": bson.M{"$lt": t}}
+		ids, err := r.coll.Distinct(sessCtx, "booking_id", filter)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.crew.DeleteMany(sessCtx, bson.M{"booking_id": bson.M{"$in": ids}}); err != nil {
+			return nil, err
+		}
+		result, err := r.coll.DeleteMany(sessCtx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return result.DeletedCount, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged.(int64), nil
+}
+
+func (r *bookingRepository) ArchiveBefore(ctx context.Context, t, at time.Time) (int64, error) {
+	result, err := r.coll.UpdateMany(ctx,
+		bson.M{
+			"event_date":  bson.M{"$lt": t},
+			"status":      bson.M{"$in": bson.A{models.BookingStatusEnquiry, models.BookingStatusCompleted, models.BookingStatusCancelled}},
+			"archived_at": bson.M{"$exists": false},
+			"deleted_at":  notDeleted,
+		},
+		bson.M{"$set": bson.M{"archived_at": at}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
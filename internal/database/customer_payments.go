@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// customerPaymentRepository implements storage.CustomerPaymentRepository on MongoDB
+type customerPaymentRepository struct {
+	coll *mongo.Collection
+}
+
+func (r *customerPaymentRepository) Create(ctx context.Context, payment *models.CustomerPayment) error {
+	result, err := r.coll.InsertOne(ctx, payment)
+	if err != nil {
+		return err
+	}
+	payment.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *customerPaymentRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.CustomerPayment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "paid_at", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.coll.Find(ctx, bson.M{"booking_id": bookingID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	payments := []models.CustomerPayment{}
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *customerPaymentRepository) Void(ctx context.Context, bookingID string, paymentID primitive.ObjectID, by, reason string, at time.Time) error {
+	filter := bson.M{"_id": paymentID, "booking_id": bookingID}
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": paymentID, "booking_id": bookingID, "voided_at": nil},
+		bson.M{"$set": bson.M{"voided_at": at, "voided_by": by, "void_reason": reason}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		count, err := r.coll.CountDocuments(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return storage.ErrNotFound
+		}
+		return storage.ErrConflict
+	}
+	return nil
+}
+
+func (r *customerPaymentRepository) TotalsByBooking(ctx context.Context, bookingIDs []string) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"booking_id": bson.M{"$in": bookingIDs}, "voided_at": nil}}},
+		{{Key: "$group", Value: bson.M{"_id": "$booking_id", "total": bson.M{"$sum": "$amount"}}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		BookingID string `bson:"_id"`
+		Total     int    `bson:"total"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int, len(results))
+	for _, result := range results {
+		totals[result.BookingID] = result.Total
+	}
+	return totals, nil
+}
+
+func (r *customerPaymentRepository) Batches(ctx context.Context, filter storage.CustomerPaymentFilter, size int, fn func([]models.CustomerPayment) error) error {
+	query := bson.M{}
+	dateRange := bson.M{}
+	if !filter.From.IsZero() {
+		dateRange["$gte"] = filter.From
+	}
+	if !filter.Before.IsZero() {
+		dateRange["$lt"] = filter.Before
+	}
+	if len(dateRange) > 0 {
+		query["paid_at"] = dateRange
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "paid_at", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetBatchSize(int32(size))
+	cursor, err := r.coll.Find(ctx, query, opts)
+	if err != nil {
+		return err
+	}
+	return batches(ctx, cursor, size, fn)
+}
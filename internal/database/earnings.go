@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// earningRepository implements storage.EarningRepository on MongoDB
+type earningRepository struct {
+	coll *mongo.Collection
+}
+
+// earningMatch builds a filter selecting the earnings matching filter
+func earningMatch(filter storage.EarningFilter) bson.M {
+	match := bson.M{}
+	if !filter.EmployeeID.IsZero() {
+		match["employee_id"] = filter.EmployeeID
+	}
+	dateRange := bson.M{}
+	if !filter.From.IsZero() {
+		dateRange["$gte"] = filter.From
+	}
+	if !filter.Before.IsZero() {
+		dateRange["$lt"] = filter.Before
+	}
+	if len(dateRange) > 0 {
+		match["event_date"] = dateRange
+	}
+	return match
+}
+
+func (r *earningRepository) List(ctx context.Context, filter storage.EarningFilter) ([]models.Earning, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "event_date", Value: 1}})
+	cursor, err := r.coll.Find(ctx, earningMatch(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	earnings := []models.Earning{}
+	if err = cursor.All(ctx, &earnings); err != nil {
+		return nil, err
+	}
+	return earnings, nil
+}
+
+func (r *earningRepository) TotalsByEmployee(ctx context.Context, filter storage.EarningFilter) (map[primitive.ObjectID]models.PaymentTotal, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: earningMatch(filter)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$employee_id",
+			"amount": bson.M{"$sum": "$amount"},
+			"count":  bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		EmployeeID          primitive.ObjectID `bson:"_id"`
+		models.PaymentTotal `bson:",inline"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[primitive.ObjectID]models.PaymentTotal, len(results))
+	for _, result := range results {
+		totals[result.EmployeeID] = result.PaymentTotal
+	}
+	return totals, nil
+}
+
+// accrueEarnings records an earning for each crew assignment of a booking
+func (r *bookingRepository) accrueEarnings(ctx context.Context, bookingID string, at time.Time) error {
+	cursor, err := r.crew.Find(ctx, bson.M{"booking_id": bookingID, "deleted_at": notDeleted})
+	if err != nil {
+		return err
+	}
+	crew := []models.CrewAssignment{}
+	if err := cursor.All(ctx, &crew); err != nil {
+		return err
+	}
+	if len(crew) == 0 {
+		return nil
+	}
+
+	earnings := make([]interface{}, len(crew))
+	for i := range crew {
+		earnings[i] = models.NewEarning(&crew[i], at)
+	}
+	_, err = r.earnings.InsertMany(ctx, earnings)
+	return err
+}
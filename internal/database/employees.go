@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// employeeRepository implements storage.EmployeeRepository on MongoDB
+type employeeRepository struct {
+	client   *mongo.Client
+	coll     *mongo.Collection
+	payments *mongo.Collection
+	crew     *mongo.Collection
+	earnings *mongo.Collection
+}
+
+func (r *employeeRepository) Create(ctx context.Context, employee *models.Employee) error {
+	result, err := r.coll.InsertOne(ctx, employee)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return storage.ErrDuplicate
+		}
+		return err
+	}
+	employee.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *employeeRepository) CreateMany(ctx context.Context, employees []models.Employee) error {
+	return insertMany(ctx, r.client, r.coll, employees, func(e *models.Employee, id primitive.ObjectID) { e.ID = id })
+}
+
+func (r *employeeRepository) Exists(ctx context.Context, username string) (bool, error) {
+	count, err := r.coll.CountDocuments(ctx, bson.M{"username": username})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *employeeRepository) FindByUsername(ctx context.Context, username string) (*models.Employee, error) {
+	var employee models.Employee
+	err := r.coll.FindOne(ctx, bson.M{"username": username, "deleted_at": notDeleted}).Decode(&employee)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &employee, nil
+}
+
+func (r *employeeRepository) List(ctx context.Context) ([]models.Employee, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.coll.Find(ctx, bson.M{"deleted_at": notDeleted}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	employees := []models.Employee{}
+	if err = cursor.All(ctx, &employees); err != nil {
+		return nil, err
+	}
+	return employees, nil
+}
+
+func (r *employeeRepository) Search(ctx context.Context, query string, limit int) ([]models.Employee, error) {
+	return search(ctx, r.coll, query, employeeSearchWeights, limit, func(e *models.Employee) primitive.ObjectID { return e.ID })
+}
+
+func (r *employeeRepository) Delete(ctx context.Context, id primitive.ObjectID, by string, at time.Time) error {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": notDeleted},
+		bson.M{"$set": bson.M{"deleted_at": at, "deleted_by": by}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *employeeRepository) Restore(ctx context.Context, username string) error {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"username": username, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *employeeRepository) ListDeleted(ctx context.Context, limit int) ([]models.Employee, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "username", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.coll.Find(ctx, bson.M{"deleted_at": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	employees := []models.Employee{}
+	if err = cursor.All(ctx, &employees); err != nil {
+		return nil, err
+	}
+	return employees, nil
+}
+
+func (r *employeeRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.EndSession(ctx)
+
+	// Remove the employees, their payments, crew assignments and earnings together
+	purged, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{"deleted_at": bson.M{"$lt": t}}
+		ids, err := r.coll.Distinct(sessCtx, "_id", filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return int64(0), nil
+		}
+
+		owned := bson.M{"employee_id": bson.M{"$in": ids}}
+		if _, err := r.payments.DeleteMany(sessCtx, owned); err != nil {
+			return nil, err
+		}
+		if _, err := r.crew.DeleteMany(sessCtx, owned); err != nil {
+			return nil, err
+		}
+		if _, err := r.earnings.DeleteMany(sessCtx, owned); err != nil {
+			return nil, err
+		}
+
+		result, err := r.coll.DeleteMany(sessCtx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return nil, err
+		}
+		return result.DeletedCount, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged.(int64), nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// inventoryRepository implements storage.InventoryRepository on MongoDB
+type inventoryRepository struct {
+	coll *mongo.Collection
+}
+
+func (r *inventoryRepository) List(ctx context.Context) ([]models.InventoryItem, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "code", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	items := []models.InventoryItem{}
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *inventoryRepository) Put(ctx context.Context, item *models.InventoryItem) error {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var stored models.InventoryItem
+	err := r.coll.FindOneAndUpdate(ctx,
+		bson.M{"code": item.Code},
+		bson.M{"$set": bson.M{
+			"name":             item.Name,
+			"quantity_owned":   item.QuantityOwned,
+			"in_maintenance":   item.InMaintenance,
+			"maintenance_note": item.MaintenanceNote,
+			"updated_by":       item.UpdatedBy,
+			"updated_at":       item.UpdatedAt,
+		}},
+		opts,
+	).Decode(&stored)
+	if err != nil {
+		return err
+	}
+	item.ID = stored.ID
+	return nil
+}
+
+func (r *inventoryRepository) Delete(ctx context.Context, code string) error {
+	result, err := r.coll.DeleteOne(ctx, bson.M{"code": code})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
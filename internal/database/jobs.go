@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// jobRepository implements storage.JobRepository on MongoDB. Locks are taken
+// with single-document conditional updates, so only one replica holds a job's
+// lock at a time.
+type jobRepository struct {
+	coll *mongo.Collection
+	runs *mongo.Collection
+}
+
+// unlocked matches jobs whose lock, if any, has expired at now
+func unlocked(now time.Time) bson.A {
+	return bson.A{
+		bson.M{"locked_until": bson.M{"$exists": false}},
+		bson.M{"locked_until": bson.M{"$lte": now}},
+	}
+}
+
+func (r *jobRepository) Ensure(ctx context.Context, state *models.JobState) error {
+	_, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": state.Name},
+		bson.M{"$setOnInsert": bson.M{
+			"schedule":    state.Schedule,
+			"enabled":     state.Enabled,
+			"next_run_at": state.NextRunAt,
+			"updated_at":  state.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *jobRepository) List(ctx context.Context) ([]models.JobState, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []models.JobState{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) Find(ctx context.Context, name string) (*models.JobState, error) {
+	var j models.JobState
+	err := r.coll.FindOne(ctx, bson.M{"_id": name}).Decode(&j)
+	if err == mongo.ErrNoDocuments {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (r *jobRepository) UpdateSchedule(ctx context.Context, state *models.JobState) error {
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": state.Name}, bson.M{"$set": bson.M{
+		"schedule":    state.Schedule,
+		"enabled":     state.Enabled,
+		"next_run_at": state.NextRunAt,
+		"updated_by":  state.UpdatedBy,
+		"updated_at":  state.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *jobRepository) Lock(ctx context.Context, name, owner string, now, lockedUntil time.Time) (bool, error) {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": name, "$or": unlocked(now)},
+		bson.M{"$set": bson.M{"locked_by": owner, "locked_until": lockedUntil}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *jobRepository) LockDue(ctx context.Context, name, owner string, now, lockedUntil, next time.Time) (bool, error) {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": name, "enabled": true, "next_run_at": bson.M{"$lte": now}, "$or": unlocked(now)},
+		bson.M{"$set": bson.M{"locked_by": owner, "locked_until": lockedUntil, "next_run_at": next}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *jobRepository) Unlock(ctx context.Context, name, owner string, run *models.JobRun) error {
+	// The last run is recorded even if the lock expired and passed to another owner
+	if _, err := r.coll.UpdateOne(ctx, bson.M{"_id": name}, bson.M{"$set": bson.M{"last_run": run}}); err != nil {
+		return err
+	}
+	_, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": name, "locked_by": owner},
+		bson.M{"$unset": bson.M{"locked_by": "", "locked_until": ""}})
+	return err
+}
+
+func (r *jobRepository) CreateRun(ctx context.Context, run *models.JobRun) error {
+	run.ID = primitive.NewObjectID()
+	_, err := r.runs.InsertOne(ctx, run)
+	return err
+}
+
+func (r *jobRepository) FinishRun(ctx context.Context, run *models.JobRun) error {
+	result, err := r.runs.UpdateOne(ctx, bson.M{"_id": run.ID}, bson.M{"$set": bson.M{
+		"status":      run.Status,
+		"result":      run.Result,
+		"error":       run.Error,
+		"finished_at": run.FinishedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *jobRepository) ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.runs.Find(ctx, bson.M{"job": job}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	runs := []models.JobRun{}
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *jobRepository) DeleteRunsBefore(ctx context.Context, t time.Time) (int64, error) {
+	result, err := r.runs.DeleteMany(ctx, bson.M{"started_at": bson.M{"$lt": t}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
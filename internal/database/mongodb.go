@@ -20,10 +20,26 @@ var (
 	db              *mongo.Database
 	dbOnce          sync.Once
 	collectionNames = map[string]string{
-		"bookings":    "bookings",
-		"employees":   "employees",
-		"payments":    "payments",
-		"admin_users": "admin_users",
+		"bookings":           "bookings",
+		"employees":          "employees",
+		"payments":           "payments",
+		"admin_users":        "admin_users",
+		"otps":               "otps",
+		"otp_events":         "otp_events",
+		"rate_cards":         "rate_cards",
+		"packages":           "packages",
+		"settings":           "settings",
+		"booking_locks":      "booking_locks",
+		"booking_revisions":  "booking_revisions",
+		"customer_payments":  "customer_payments",
+		"crew_assignments":   "crew_assignments",
+		"earnings":           "earnings",
+		"inventory":          "inventory",
+		"tax_invoices":       "tax_invoices",
+		"tax_invoice_series": "tax_invoice_series",
+		"notifications":      "notifications",
+		"jobs":               "jobs",
+		"job_runs":           "job_runs",
 	}
 )
 
@@ -118,14 +134,34 @@ func createIndexesInternal(ctx context.Context, database *mongo.Database) error
 	bookingsColl := database.Collection(collectionNames["bookings"])
 	_, err := bookingsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
-			Keys:    bson.D{{"booking_id", 1}},
+			Keys:    bson.D{{Key: "booking_id", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys: bson.D{{"phone", 1}},
+			Keys: bson.D{{Key: "phone", Value: 1}},
 		},
 		{
-			Keys: bson.D{{"event_date", 1}},
+			Keys: bson.D{{Key: "event_date", Value: 1}, {Key: "time_slot", Value: 1}},
+		},
+		// Listing filters and sort orders
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "event_date", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "city", Value: 1}, {Key: "event_date", Value: 1}},
+			Options: options.Index().SetCollation(caseInsensitive),
+		},
+		{
+			Keys: bson.D{{Key: "package_type", Value: 1}, {Key: "event_date", Value: 1}},
+		},
+		textIndex("bookings_search", bookingSearchWeights),
+		// Trash
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+			Options: options.Index().SetSparse(true),
 		},
 	})
 	if err != nil {
@@ -136,9 +172,14 @@ func createIndexesInternal(ctx context.Context, database *mongo.Database) error
 	employeesColl := database.Collection(collectionNames["employees"])
 	_, err = employeesColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
-			Keys:    bson.D{{"username", 1}},
+			Keys:    bson.D{{Key: "username", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		textIndex("employees_search", employeeSearchWeights),
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+			Options: options.Index().SetSparse(true),
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("error creating employees indexes: %w", err)
@@ -148,7 +189,7 @@ func createIndexesInternal(ctx context.Context, database *mongo.Database) error
 	adminUsersColl := database.Collection(collectionNames["admin_users"])
 	_, err = adminUsersColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
-			Keys:    bson.D{{"username", 1}},
+			Keys:    bson.D{{Key: "username", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
 	})
@@ -160,16 +201,198 @@ func createIndexesInternal(ctx context.Context, database *mongo.Database) error
 	paymentsColl := database.Collection(collectionNames["payments"])
 	_, err = paymentsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
-			Keys: bson.D{{"employee_id", 1}},
+			Keys: bson.D{{Key: "employee_id", Value: 1}},
 		},
 		{
-			Keys: bson.D{{"date", 1}},
+			Keys: bson.D{{Key: "date", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+			Options: options.Index().SetSparse(true),
 		},
 	})
 	if err != nil {
 		return fmt.Errorf("error creating payments indexes: %w", err)
 	}
 
+	// OTPs collection indexes; expired verifications are removed by MongoDB
+	otpsColl := database.Collection(collectionNames["otps"])
+	_, err = otpsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "phone", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating otps indexes: %w", err)
+	}
+
+	// OTP events collection indexes; events are removed by MongoDB once they
+	// leave the rolling window
+	otpEventsColl := database.Collection(collectionNames["otp_events"])
+	_, err = otpEventsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "phone", Value: 1}, {Key: "kind", Value: 1}, {Key: "at", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating otp_events indexes: %w", err)
+	}
+
+	// Rate cards collection indexes
+	rateCardsColl := database.Collection(collectionNames["rate_cards"])
+	_, err = rateCardsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating rate_cards indexes: %w", err)
+	}
+
+	// Packages collection indexes
+	packagesColl := database.Collection(collectionNames["packages"])
+	_, err = packagesColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating packages indexes: %w", err)
+	}
+
+	// Booking revisions collection indexes; the unique revision number rejects concurrent edits
+	revisionsColl := database.Collection(collectionNames["booking_revisions"])
+	_, err = revisionsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "booking_id", Value: 1}, {Key: "revision", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating booking_revisions indexes: %w", err)
+	}
+
+	// Customer payments collection indexes
+	customerPaymentsColl := database.Collection(collectionNames["customer_payments"])
+	_, err = customerPaymentsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "booking_id", Value: 1}, {Key: "paid_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "paid_at", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating customer_payments indexes: %w", err)
+	}
+
+	// Crew assignments collection indexes
+	crewColl := database.Collection(collectionNames["crew_assignments"])
+	_, err = crewColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "booking_id", Value: 1}, {Key: "employee_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "employee_id", Value: 1}, {Key: "event_date", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating crew_assignments indexes: %w", err)
+	}
+
+	// Earnings collection indexes; a booking accrues each employee's wage once
+	earningsColl := database.Collection(collectionNames["earnings"])
+	_, err = earningsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "booking_id", Value: 1}, {Key: "employee_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "employee_id", Value: 1}, {Key: "event_date", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating earnings indexes: %w", err)
+	}
+
+	// Inventory collection indexes
+	inventoryColl := database.Collection(collectionNames["inventory"])
+	_, err = inventoryColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating inventory indexes: %w", err)
+	}
+
+	// Tax invoice collection indexes
+	taxInvoicesColl := database.Collection(collectionNames["tax_invoices"])
+	_, err = taxInvoicesColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "booking_id", Value: 1}, {Key: "kind", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "financial_year", Value: 1}, {Key: "issued_at", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating tax_invoices indexes: %w", err)
+	}
+
+	// Notification outbox indexes
+	notificationsColl := database.Collection(collectionNames["notifications"])
+	_, err = notificationsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "booking_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"key": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating notifications indexes: %w", err)
+	}
+
+	// Job run history indexes
+	jobRunsColl := database.Collection(collectionNames["job_runs"])
+	_, err = jobRunsColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "job", Value: 1}, {Key: "started_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "started_at", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating job_runs indexes: %w", err)
+	}
+
 	return nil
 }
 
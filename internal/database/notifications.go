@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// notificationRepository implements storage.NotificationRepository on MongoDB
+type notificationRepository struct {
+	coll *mongo.Collection
+}
+
+func (r *notificationRepository) Enqueue(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(notifications))
+	for i := range notifications {
+		notifications[i].ID = primitive.NewObjectID()
+		docs[i] = notifications[i]
+	}
+
+	// Unordered, so a notification whose key is taken does not stop the rest
+	_, err := r.coll.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return err
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			return err
+		}
+		notifications[writeErr.Index].ID = primitive.NilObjectID
+	}
+	return nil
+}
+
+func (r *notificationRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Notification, error) {
+	// Each notification is claimed with its own atomic update, so a notification
+	// claimed by another dispatcher in the meantime is simply no longer due
+	claimed := []models.Notification{}
+	for len(claimed) < limit {
+		var n models.Notification
+		err := r.coll.FindOneAndUpdate(ctx,
+			bson.M{"status": models.NotificationPending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"next_attempt_at": leaseUntil}, "$inc": bson.M{"attempts": 1}},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+				SetReturnDocument(options.After),
+		).Decode(&n)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, n)
+	}
+	return claimed, nil
+}
+
+func (r *notificationRepository) MarkSent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	return r.update(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": models.NotificationSent, "sent_at": at},
+		"$unset": bson.M{"last_error": ""},
+	})
+}
+
+func (r *notificationRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, lastError string, retryAt time.Time) error {
+	set := bson.M{"last_error": lastError}
+	if retryAt.IsZero() {
+		set["status"] = models.NotificationFailed
+	} else {
+		set["next_attempt_at"] = retryAt
+	}
+	return r.update(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+}
+
+func (r *notificationRepository) Retry(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	err := r.update(ctx, bson.M{"_id": id, "status": models.NotificationFailed}, bson.M{
+		"$set": bson.M{"status": models.NotificationPending, "attempts": 0, "next_attempt_at": at},
+	})
+	if err != storage.ErrNotFound {
+		return err
+	}
+	count, err := r.coll.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return storage.ErrConflict
+	}
+	return storage.ErrNotFound
+}
+
+// update applies an update to one notification, returning ErrNotFound if none matched
+func (r *notificationRepository) update(ctx context.Context, filter, update bson.M) error {
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) List(ctx context.Context, filter storage.NotificationFilter, limit int) ([]models.Notification, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.BookingID != "" {
+		query["booking_id"] = filter.BookingID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.coll.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []models.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
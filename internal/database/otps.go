@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// otpRepository implements storage.OTPRepository on MongoDB
+type otpRepository struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+	events *mongo.Collection
+	locks  *mongo.Collection // Shared with bookings; OTP limit locks are keyed by phone and kind
+}
+
+func (r *otpRepository) FindByPhone(ctx context.Context, phone string) (*models.OTPVerification, error) {
+	var record models.OTPVerification
+	err := r.coll.FindOne(ctx, bson.M{"phone": phone}).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *otpRepository) Issue(ctx context.Context, phone, codeHash string, now, codeExpiresAt time.Time) error {
+	// Replace any previous code; a new code also clears an earlier verification
+	_, err := r.coll.UpdateOne(ctx,
+		bson.M{"phone": phone},
+		bson.M{
+			"$set": bson.M{
+				"code_hash":       codeHash,
+				"code_expires_at": codeExpiresAt,
+				"attempts":        0,
+				"last_sent_at":    now,
+				"expires_at":      codeExpiresAt,
+			},
+			"$unset":       bson.M{"verified_at": ""},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *otpRepository) RecordAttempt(ctx context.Context, phone string, now time.Time, maxAttempts int) (*models.OTPVerification, error) {
+	var record models.OTPVerification
+	err := r.coll.FindOneAndUpdate(ctx,
+		bson.M{
+			"phone":           phone,
+			"code_hash":       bson.M{"$exists": true},
+			"code_expires_at": bson.M{"$gt": now},
+			"attempts":        bson.M{"$lt": maxAttempts},
+		},
+		bson.M{"$inc": bson.M{"attempts": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *otpRepository) MarkVerified(ctx context.Context, phone string, verifiedAt, expiresAt time.Time) error {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"phone": phone},
+		bson.M{
+			"$set":   bson.M{"verified_at": verifiedAt, "expires_at": expiresAt},
+			"$unset": bson.M{"code_hash": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *otpRepository) LogEvent(ctx context.Context, event *models.OTPEvent, since time.Time, limit int) (bool, error) {
+	if limit <= 0 {
+		result, err := r.events.InsertOne(ctx, event)
+		if err != nil {
+			return false, err
+		}
+		event.ID = result.InsertedID.(primitive.ObjectID)
+		return true, nil
+	}
+
+	session, err := r.client.StartSession()
+	if err != nil {
+		return false, err
+	}
+	defer session.EndSession(ctx)
+
+	logged, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		// Writing the phone's lock document makes concurrent events of the kind conflict
+		_, err := r.locks.UpdateOne(sessCtx,
+			bson.M{"_id": "otp:" + event.Phone + ":" + event.Kind},
+			bson.M{"$set": bson.M{"updated_at": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return false, err
+		}
+
+		count, err := r.events.CountDocuments(sessCtx, bson.M{
+			"phone": event.Phone,
+			"kind":  event.Kind,
+			"at":    bson.M{"$gt": since},
+		})
+		if err != nil {
+			return false, err
+		}
+		if count >= int64(limit) {
+			return false, nil
+		}
+
+		result, err := r.events.InsertOne(sessCtx, event)
+		if err != nil {
+			return false, err
+		}
+		event.ID = result.InsertedID.(primitive.ObjectID)
+		return true, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return logged.(bool), nil
+}
+
+func (r *otpRepository) EventsSince(ctx context.Context, phone, kind string, since time.Time) ([]time.Time, error) {
+	cursor, err := r.events.Find(ctx,
+		bson.M{"phone": phone, "kind": kind, "at": bson.M{"$gt": since}},
+		options.Find().SetSort(bson.D{{Key: "at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.OTPEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, len(events))
+	for i, e := range events {
+		times[i] = e.At
+	}
+	return times, nil
+}
+
+// DeleteExpired removes expired records without waiting for the TTL monitor,
+// which runs only once a minute
+func (r *otpRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	if _, err := r.events.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": now}}); err != nil {
+		return 0, err
+	}
+	result, err := r.coll.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": now}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
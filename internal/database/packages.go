@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// packageRepository implements storage.PackageRepository on MongoDB
+type packageRepository struct {
+	coll *mongo.Collection
+}
+
+func (r *packageRepository) Create(ctx context.Context, pkg *models.Package) error {
+	result, err := r.coll.InsertOne(ctx, pkg)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return storage.ErrDuplicate
+		}
+		return err
+	}
+	pkg.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *packageRepository) FindByCode(ctx context.Context, code string) (*models.Package, error) {
+	var pkg models.Package
+	err := r.coll.FindOne(ctx, bson.M{"code": code}).Decode(&pkg)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+func (r *packageRepository) List(ctx context.Context) ([]models.Package, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "price", Value: 1}, {Key: "code", Value: 1}})
+
+	cursor, err := r.coll.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	packages := []models.Package{}
+	if err = cursor.All(ctx, &packages); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
+
+func (r *packageRepository) Update(ctx context.Context, pkg *models.Package) error {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"code": pkg.Code},
+		bson.M{"$set": bson.M{
+			"name":        pkg.Name,
+			"description": pkg.Description,
+			"musicians":   pkg.Musicians,
+			"lights":      pkg.Lights,
+			"dhols":       pkg.Dhols,
+			"hours":       pkg.Hours,
+			"price":       pkg.Price,
+			"active_from": pkg.ActiveFrom,
+			"active_to":   pkg.ActiveTo,
+			"updated_at":  pkg.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *packageRepository) Delete(ctx context.Context, code string) error {
+	result, err := r.coll.DeleteOne(ctx, bson.M{"code": code})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
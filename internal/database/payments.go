@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// paymentRepository implements storage.PaymentRepository on MongoDB
+type paymentRepository struct {
+	coll *mongo.Collection
+}
+
+func (r *paymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	result, err := r.coll.InsertOne(ctx, payment)
+	if err != nil {
+		return err
+	}
+	payment.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *paymentRepository) ListByEmployee(ctx context.Context, employeeID primitive.ObjectID) ([]models.Payment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}})
+
+	cursor, err := r.coll.Find(ctx, bson.M{"employee_id": employeeID, "deleted_at": notDeleted}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	payments := []models.Payment{}
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) Delete(ctx context.Context, employeeID, paymentID primitive.ObjectID, by string, at time.Time) error {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": paymentID, "employee_id": employeeID, "deleted_at": notDeleted},
+		bson.M{"$set": bson.M{"deleted_at": at, "deleted_by": by}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *paymentRepository) Restore(ctx context.Context, employeeID, paymentID primitive.ObjectID) error {
+	result, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": paymentID, "employee_id": employeeID, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *paymentRepository) ListDeleted(ctx context.Context, limit int) ([]models.Payment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.coll.Find(ctx, bson.M{"deleted_at": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	payments := []models.Payment{}
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	result, err := r.coll.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": t}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// paymentQuery returns the query selecting the payments that match filter
+func paymentQuery(filter storage.PaymentFilter) bson.M {
+	match := bson.M{"deleted_at": notDeleted}
+	if !filter.EmployeeID.IsZero() {
+		match["employee_id"] = filter.EmployeeID
+	}
+	dateRange := bson.M{}
+	if !filter.From.IsZero() {
+		dateRange["$gte"] = filter.From
+	}
+	if !filter.Before.IsZero() {
+		dateRange["$lt"] = filter.Before
+	}
+	if len(dateRange) > 0 {
+		match["date"] = dateRange
+	}
+	return match
+}
+
+func (r *paymentRepository) TotalsByEmployee(ctx context.Context, filter storage.PaymentFilter) (map[primitive.ObjectID]models.PaymentTotal, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: paymentQuery(filter)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$employee_id",
+			"amount": bson.M{"$sum": "$amount_paid"},
+			"count":  bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		EmployeeID          primitive.ObjectID `bson:"_id"`
+		models.PaymentTotal `bson:",inline"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[primitive.ObjectID]models.PaymentTotal, len(results))
+	for _, result := range results {
+		totals[result.EmployeeID] = result.PaymentTotal
+	}
+	return totals, nil
+}
+
+func (r *paymentRepository) Batches(ctx context.Context, filter storage.PaymentFilter, size int, fn func([]models.Payment) error) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetBatchSize(int32(size))
+	cursor, err := r.coll.Find(ctx, paymentQuery(filter), opts)
+	if err != nil {
+		return err
+	}
+	return batches(ctx, cursor, size, fn)
+}
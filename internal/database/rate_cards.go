@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// rateCardRepository implements storage.RateCardRepository on MongoDB
+type rateCardRepository struct {
+	coll *mongo.Collection
+}
+
+func (r *rateCardRepository) Current(ctx context.Context) (*models.RateCard, error) {
+	var card models.RateCard
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err := r.coll.FindOne(ctx, bson.M{}, opts).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (r *rateCardRepository) Create(ctx context.Context, card *models.RateCard) error {
+	result, err := r.coll.InsertOne(ctx, card)
+	if err != nil {
+		return err
+	}
+	card.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Searchable fields and their text index weights
+var (
+	bookingSearchWeights = bson.D{
+		{Key: "booking_id", Value: 10},
+		{Key: "name", Value: 5},
+		{Key: "phone", Value: 5},
+		{Key: "additional_phone", Value: 5},
+		{Key: "email", Value: 3},
+		{Key: "venue", Value: 2},
+		{Key: "city", Value: 1},
+	}
+	employeeSearchWeights = bson.D{
+		{Key: "username", Value: 10},
+		{Key: "name", Value: 5},
+		{Key: "mobile_number", Value: 5},
+		{Key: "email", Value: 3},
+		{Key: "address", Value: 1},
+	}
+)
+
+// textIndex returns a text index over the weighted fields. Stemming is disabled
+// because the fields hold names, places and numbers rather than English prose.
+func textIndex(name string, weights bson.D) mongo.IndexModel {
+	keys := bson.D{}
+	for _, field := range weights {
+		keys = append(keys, bson.E{Key: field.Key, Value: "text"})
+	}
+	return mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetName(name).SetWeights(weights).SetDefaultLanguage("none"),
+	}
+}
+
+// search returns up to limit documents outside the trash containing every term of query. Whole-word
+// matches come first, ranked by the collection's text index; the rest are filled
+// with documents whose fields contain the terms as fragments, newest first.
+func search[T any](ctx context.Context, coll *mongo.Collection, query string, weights bson.D, limit int, id func(*T) primitive.ObjectID) ([]T, error) {
+	results := []T{}
+	terms := storage.SearchTerms(query)
+	if len(terms) == 0 {
+		return results, nil
+	}
+
+	// Quoted terms must all be present, unlike bare words which match any
+	phrases := ""
+	for _, term := range terms {
+		if term = strings.ReplaceAll(term, `"`, ""); term != "" {
+			phrases += `"` + term + `" `
+		}
+	}
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().SetProjection(bson.M{"score": score}).SetSort(bson.M{"score": score}).SetLimit(int64(limit))
+	cursor, err := coll.Find(ctx, bson.M{"$text": bson.M{"$search": phrases}, "deleted_at": notDeleted}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if len(results) >= limit {
+		return results, nil
+	}
+
+	// Fill up with fragment matches the text index cannot find
+	found := []primitive.ObjectID{}
+	for i := range results {
+		found = append(found, id(&results[i]))
+	}
+	filter := bson.A{bson.M{"_id": bson.M{"$nin": found}, "deleted_at": notDeleted}}
+	for _, term := range terms {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(term), Options: "i"}
+		fields := bson.A{}
+		for _, field := range weights {
+			fields = append(fields, bson.M{field.Key: pattern})
+		}
+		filter = append(filter, bson.M{"$or": fields})
+	}
+	opts = options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit - len(results)))
+	cursor, err = coll.Find(ctx, bson.M{"$and": filter}, opts)
+	if err != nil {
+		return nil, err
+	}
+	fragments := []T{}
+	if err = cursor.All(ctx, &fragments); err != nil {
+		return nil, err
+	}
+	return append(results, fragments...), nil
+}
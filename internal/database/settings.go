@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// settingsRepository implements storage.SettingsRepository on MongoDB. Each
+// setting is a document whose _id is the key and whose value field holds the data.
+type settingsRepository struct {
+	coll *mongo.Collection
+}
+
+func (r *settingsRepository) Get(ctx context.Context, key string, v interface{}) error {
+	var doc struct {
+		Value bson.Raw `bson:"value"`
+	}
+	err := r.coll.FindOne(ctx, bson.M{"_id": key}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return storage.ErrNotFound
+		}
+		return err
+	}
+	return bson.Unmarshal(doc.Value, v)
+}
+
+func (r *settingsRepository) Put(ctx context.Context, key string, v interface{}) error {
+	_, err := r.coll.ReplaceOne(ctx,
+		bson.M{"_id": key},
+		bson.M{"_id": key, "value": v, "updated_at": time.Now()},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
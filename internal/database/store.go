@@ -0,0 +1,78 @@
+package database
+
+import (
+	"time"
+
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// notDeleted is the deleted_at filter value matching documents outside the trash
+var notDeleted = bson.M{"$exists": false}
+
+// NewMongoStore returns repositories backed by the given MongoDB database
+func NewMongoStore(database *mongo.Database) *storage.Store {
+	collection := func(name string) *mongo.Collection {
+		return database.Collection(collectionNames[name])
+	}
+
+	return &storage.Store{
+		Bookings: &bookingRepository{
+			client:    database.Client(),
+			coll:      collection("bookings"),
+			locks:     collection("booking_locks"),
+			revisions: collection("booking_revisions"),
+			crew:      collection("crew_assignments"),
+			earnings:  collection("earnings"),
+			employees: collection("employees"),
+			otps:      collection("otps"),
+		},
+		Employees: &employeeRepository{
+			client:   database.Client(),
+			coll:     collection("employees"),
+			payments: collection("payments"),
+			crew:     collection("crew_assignments"),
+			earnings: collection("earnings"),
+		},
+		Payments:         &paymentRepository{coll: collection("payments")},
+		CustomerPayments: &customerPaymentRepository{coll: collection("customer_payments")},
+		Assignments: &assignmentRepository{
+			client: database.Client(),
+			coll:   collection("crew_assignments"),
+			locks:  collection("booking_locks"),
+		},
+		Earnings: &earningRepository{coll: collection("earnings")},
+		Admins: &adminRepository{
+			client: database.Client(),
+			coll:   collection("admin_users"),
+			locks:  collection("booking_locks"),
+		},
+		OTPs: &otpRepository{
+			client: database.Client(),
+			coll:   collection("otps"),
+			events: collection("otp_events"),
+			locks:  collection("booking_locks"),
+		},
+		RateCards: &rateCardRepository{coll: collection("rate_cards")},
+		Packages:  &packageRepository{coll: collection("packages")},
+		Settings:  &settingsRepository{coll: collection("settings")},
+		Inventory: &inventoryRepository{coll: collection("inventory")},
+		TaxInvoices: &taxInvoiceRepository{
+			client: database.Client(),
+			coll:   collection("tax_invoices"),
+			series: collection("tax_invoice_series"),
+		},
+		Notifications: &notificationRepository{coll: collection("notifications")},
+		Jobs: &jobRepository{
+			coll: collection("jobs"),
+			runs: collection("job_runs"),
+		},
+	}
+}
+
+// startOfDay truncates a time to midnight UTC of its UTC calendar day
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
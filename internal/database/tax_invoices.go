@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// taxInvoiceRepository implements storage.TaxInvoiceRepository on MongoDB. The
+// last number used in each series is kept in a counter document.
+type taxInvoiceRepository struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+	series *mongo.Collection
+}
+
+func (r *taxInvoiceRepository) Issue(ctx context.Context, doc *models.TaxInvoice) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// Incrementing the counter and inserting the document commit together, so an
+	// aborted insert gives its number back; concurrent issues conflict on the counter
+	issued := *doc
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		var counter struct {
+			Sequence int `bson:"sequence"`
+		}
+		err := r.series.FindOneAndUpdate(sessCtx,
+			bson.M{"_id": doc.Series()},
+			bson.M{"$inc": bson.M{"sequence": 1}},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&counter)
+		if err != nil {
+			return nil, err
+		}
+
+		issued = *doc
+		issued.ID = primitive.NewObjectID()
+		issued.SetSequence(counter.Sequence)
+		_, err = r.coll.InsertOne(sessCtx, &issued)
+		return nil, err
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return storage.ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	*doc = issued
+	return nil
+}
+
+func (r *taxInvoiceRepository) FindByNumber(ctx context.Context, number string) (*models.TaxInvoice, error) {
+	var doc models.TaxInvoice
+	err := r.coll.FindOne(ctx, bson.M{"number": number}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *taxInvoiceRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.TaxInvoice, error) {
+	return r.find(ctx, bson.M{"booking_id": bookingID})
+}
+
+func (r *taxInvoiceRepository) List(ctx context.Context, financialYear, kind string) ([]models.TaxInvoice, error) {
+	filter := bson.M{"financial_year": financialYear}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+	return r.find(ctx, filter)
+}
+
+// find returns the documents matching filter in the order they were issued
+func (r *taxInvoiceRepository) find(ctx context.Context, filter bson.M) ([]models.TaxInvoice, error) {
+	cursor, err := r.coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "issued_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []models.TaxInvoice{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
@@ -0,0 +1,124 @@
+// Package export writes tables of records as CSV or Excel spreadsheets, one row
+// at a time, so that large exports can be streamed to the client.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Supported formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnknownFormat is returned for a format other than FormatCSV and FormatXLSX
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Writer writes a table one row at a time
+type Writer interface {
+	// Write appends a row. Values are strings, ints, float64s or bools.
+	Write(row []interface{}) error
+	// Close completes the document. It does not close the underlying writer.
+	Close() error
+}
+
+// NewWriter returns a Writer producing format on w. sheet names the worksheet of
+// an Excel document.
+func NewWriter(format string, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, sheet)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// ContentType returns the MIME type of format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Column describes one column of an export of T
+type Column[T any] struct {
+	Key    string // Name used to select the column
+	Header string
+	Value  func(*T) interface{}
+}
+
+// SelectColumns returns the columns named by keys in that order, or every column
+// if keys is empty
+func SelectColumns[T any](columns []Column[T], keys []string) ([]Column[T], error) {
+	if len(keys) == 0 {
+		return columns, nil
+	}
+
+	byKey := make(map[string]Column[T], len(columns))
+	for _, column := range columns {
+		byKey[column.Key] = column
+	}
+	selected := make([]Column[T], 0, len(keys))
+	for _, key := range keys {
+		column, ok := byKey[strings.TrimSpace(key)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", key)
+		}
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
+
+// Keys returns the keys of columns
+func Keys[T any](columns []Column[T]) []string {
+	keys := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column.Key
+	}
+	return keys
+}
+
+// Header returns the header row for columns
+func Header[T any](columns []Column[T]) []interface{} {
+	row := make([]interface{}, len(columns))
+	for i, column := range columns {
+		row[i] = column.Header
+	}
+	return row
+}
+
+// Row returns the values of record for columns
+func Row[T any](columns []Column[T], record *T) []interface{} {
+	row := make([]interface{}, len(columns))
+	for i, column := range columns {
+		row[i] = column.Value(record)
+	}
+	return row
+}
+
+// formatValue renders a cell value as text
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -6,17 +6,14 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/modernband/booking/internal/database"
+	"github.com/modernband/booking/internal/auth"
 	"github.com/modernband/booking/internal/models"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"github.com/modernband/booking/internal/storage"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // CreateAdminUser creates a new admin user
-func CreateAdminUser(c *gin.Context) {
+func (h *Handler) CreateAdminUser(c *gin.Context) {
 	var request models.CreateAdminUserRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
@@ -30,51 +27,70 @@ func CreateAdminUser(c *gin.Context) {
 		return
 	}
 
-	adminColl := database.GetCollection("admin_users")
-	employeeColl := database.GetCollection("employees")
 	ctx := context.Background()
 
 	// Check if username already exists in admin users
-	count, err := adminColl.CountDocuments(ctx, bson.M{"username": request.Username})
+	exists, err := h.store.Admins.Exists(ctx, request.Username)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
 	}
 
-	if count > 0 {
+	if exists {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Admin username already exists"})
 		return
 	}
 
 	// Check if username already exists in employees
-	count, err = employeeColl.CountDocuments(ctx, bson.M{"username": request.Username})
+	exists, err = h.store.Employees.Exists(ctx, request.Username)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
 	}
 
-	if count > 0 {
+	if exists {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Username already exists as an employee"})
 		return
 	}
 
+	// Without a signed-in admin this is the bootstrap of the first admin, who is
+	// always the owner; later admins default to manager
+	bootstrap := currentClaims(c) == nil
+	role := auth.Role(request.Role)
+	if bootstrap {
+		role = auth.RoleOwner
+	} else if role == "" {
+		role = auth.RoleManager
+	}
+
 	// Create new admin user
 	now := time.Now()
 	adminUser := models.AdminUser{
-		ID:           primitive.NewObjectID(),
 		Name:         request.Name,
 		MobileNumber: request.MobileNumber,
 		Email:        request.Email,
 		Username:     request.Username,
 		Password:     string(hashedPassword),
 		IsAdminUser:  true,
+		Role:         string(role),
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
 
-	_, err = adminColl.InsertOne(ctx, adminUser)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create admin user", "details": err.Error()})
+	create := h.store.Admins.Create
+	if bootstrap {
+		// Only one of concurrent bootstrap requests may create the first admin
+		create = h.store.Admins.CreateFirst
+	}
+	if err := create(ctx, &adminUser); err != nil {
+		switch err {
+		case storage.ErrDuplicate:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Admin username already exists"})
+		case storage.ErrConflict:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "An admin user already exists. Sign in as an owner to add admin users"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create admin user", "details": err.Error()})
+		}
 		return
 	}
 
@@ -88,31 +104,24 @@ func CreateAdminUser(c *gin.Context) {
 			"email":        adminUser.Email,
 			"username":     adminUser.Username,
 			"isAdminUser":  adminUser.IsAdminUser,
+			"role":         adminUser.Role,
 			"createdAt":    adminUser.CreatedAt,
 		},
 	})
 }
 
 // GetAllAdminUsers retrieves all admin users
-func GetAllAdminUsers(c *gin.Context) {
-	coll := database.GetCollection("admin_users")
+func (h *Handler) GetAllAdminUsers(c *gin.Context) {
 	ctx := context.Background()
 
-	cursor, err := coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"created_at": -1}))
+	adminUsers, err := h.store.Admins.List(ctx)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve admin users", "details": err.Error()})
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var adminUsers []models.AdminUser
-	if err = cursor.All(ctx, &adminUsers); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse admin user data", "details": err.Error()})
-		return
-	}
 
 	// Create response without passwords
-	var response []gin.H
+	response := []gin.H{}
 	for _, admin := range adminUsers {
 		response = append(response, gin.H{
 			"id":           admin.ID,
@@ -121,6 +130,7 @@ func GetAllAdminUsers(c *gin.Context) {
 			"email":        admin.Email,
 			"username":     admin.Username,
 			"isAdminUser":  admin.IsAdminUser,
+			"role":         auth.AdminRole(admin.Role),
 			"createdAt":    admin.CreatedAt,
 		})
 	}
@@ -132,21 +142,19 @@ func GetAllAdminUsers(c *gin.Context) {
 }
 
 // DeleteAdminUser deletes an admin user by username
-func DeleteAdminUser(c *gin.Context) {
+func (h *Handler) DeleteAdminUser(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
 		return
 	}
 
-	coll := database.GetCollection("admin_users")
 	ctx := context.Background()
 
 	// Find admin user by username
-	var adminUser models.AdminUser
-	err := coll.FindOne(ctx, bson.M{"username": username}).Decode(&adminUser)
+	adminUser, err := h.store.Admins.FindByUsername(ctx, username)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == storage.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -154,15 +162,16 @@ func DeleteAdminUser(c *gin.Context) {
 		return
 	}
 
-	// Delete admin user
-	result, err := coll.DeleteOne(ctx, bson.M{"_id": adminUser.ID})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete admin user", "details": err.Error()})
-		return
-	}
-
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found"})
+	// Delete admin user unless they are the only owner
+	if err := h.store.Admins.Delete(ctx, adminUser.ID); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found"})
+		case storage.ErrLastOwner:
+			c.JSON(http.StatusConflict, gin.H{"error": "The only owner cannot be deleted. Make another admin user an owner first"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete admin user", "details": err.Error()})
+		}
 		return
 	}
 
@@ -172,7 +181,7 @@ func DeleteAdminUser(c *gin.Context) {
 }
 
 // UpdateAdminUser updates an admin user's information
-func UpdateAdminUser(c *gin.Context) {
+func (h *Handler) UpdateAdminUser(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
@@ -184,6 +193,7 @@ func UpdateAdminUser(c *gin.Context) {
 		MobileNumber string `json:"mobileNumber"`
 		Email        string `json:"email"`
 		Password     string `json:"password,omitempty"`
+		Role         string `json:"role" binding:"omitempty,oneof=owner manager accountant"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -191,14 +201,12 @@ func UpdateAdminUser(c *gin.Context) {
 		return
 	}
 
-	coll := database.GetCollection("admin_users")
 	ctx := context.Background()
 
 	// Find admin user by username
-	var adminUser models.AdminUser
-	err := coll.FindOne(ctx, bson.M{"username": username}).Decode(&adminUser)
+	adminUser, err := h.store.Admins.FindByUsername(ctx, username)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == storage.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -207,12 +215,10 @@ func UpdateAdminUser(c *gin.Context) {
 	}
 
 	// Prepare update fields
-	update := bson.M{
-		"name":          request.Name,
-		"mobile_number": request.MobileNumber,
-		"email":         request.Email,
-		"updated_at":    time.Now(),
-	}
+	adminUser.Name = request.Name
+	adminUser.MobileNumber = request.MobileNumber
+	adminUser.Email = request.Email
+	adminUser.UpdatedAt = time.Now()
 
 	// Update password if provided
 	if request.Password != "" {
@@ -221,22 +227,24 @@ func UpdateAdminUser(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
 			return
 		}
-		update["password"] = string(hashedPassword)
+		adminUser.Password = string(hashedPassword)
 	}
 
-	// Update admin user
-	result, err := coll.UpdateOne(
-		ctx,
-		bson.M{"_id": adminUser.ID},
-		bson.M{"$set": update},
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update admin user", "details": err.Error()})
-		return
+	// Update role if provided
+	if request.Role != "" {
+		adminUser.Role = request.Role
 	}
 
-	if result.ModifiedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found or no changes made"})
+	// Update admin user unless it would demote the only owner
+	if err := h.store.Admins.Update(ctx, adminUser); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found"})
+		case storage.ErrLastOwner:
+			c.JSON(http.StatusConflict, gin.H{"error": "The only owner cannot be given another role. Make another admin user an owner first"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update admin user", "details": err.Error()})
+		}
 		return
 	}
 
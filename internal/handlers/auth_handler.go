@@ -5,29 +5,26 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/modernband/booking/internal/database"
+	"github.com/modernband/booking/internal/auth"
 	"github.com/modernband/booking/internal/models"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/modernband/booking/internal/storage"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Login handles user authentication (no JWT)
-func Login(c *gin.Context) {
+// Login handles employee authentication and issues an access token
+func (h *Handler) Login(c *gin.Context) {
 	var request models.LoginRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
 		return
 	}
 
-	coll := database.GetCollection("employees")
 	ctx := context.Background()
 
 	// Find employee by username
-	var employee models.Employee
-	err := coll.FindOne(ctx, bson.M{"username": request.Username}).Decode(&employee)
+	employee, err := h.store.Employees.FindByUsername(ctx, request.Username)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == storage.ErrNotFound {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -42,8 +39,24 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	// Return success response (no token)
+	// Issue access token
+	token, expiresAt, err := auth.GenerateToken(employee.ID.Hex(), employee.Username, auth.RoleCrew)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not issue token"})
+		return
+	}
+
+	// Show the crew member where they are playing next
+	assignments, err := h.employeeAssignments(ctx, employee.ID, startOfToday())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve assignments", "details": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
+		"token":               token,
+		"expiresAt":           expiresAt,
+		"upcomingAssignments": assignments,
 		"employee": gin.H{
 			"id":                       employee.ID,
 			"name":                     employee.Name,
@@ -54,26 +67,25 @@ func Login(c *gin.Context) {
 			"totalAmountToBePaid":      employee.TotalAmountToBePaid,
 			"totalAmountPaidInAdvance": employee.TotalAmountPaidInAdvance,
 			"username":                 employee.Username,
+			"role":                     auth.RoleCrew,
 		},
 	})
 }
 
-// AdminLogin handles admin user authentication (no JWT)
-func AdminLogin(c *gin.Context) {
+// AdminLogin handles admin user authentication and issues an access token
+func (h *Handler) AdminLogin(c *gin.Context) {
 	var request models.LoginRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
 		return
 	}
 
-	coll := database.GetCollection("admin_users")
 	ctx := context.Background()
 
 	// Find admin user by username
-	var adminUser models.AdminUser
-	err := coll.FindOne(ctx, bson.M{"username": request.Username}).Decode(&adminUser)
+	adminUser, err := h.store.Admins.FindByUsername(ctx, request.Username)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == storage.ErrNotFound {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -88,8 +100,17 @@ func AdminLogin(c *gin.Context) {
 		return
 	}
 
-	// Return success response (no token)
+	// Issue access token
+	role := auth.AdminRole(adminUser.Role)
+	token, expiresAt, err := auth.GenerateToken(adminUser.ID.Hex(), adminUser.Username, role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not issue token"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
+		"token":     token,
+		"expiresAt": expiresAt,
 		"admin": gin.H{
 			"id":           adminUser.ID,
 			"name":         adminUser.Name,
@@ -97,6 +118,7 @@ func AdminLogin(c *gin.Context) {
 			"email":        adminUser.Email,
 			"username":     adminUser.Username,
 			"isAdmin":      adminUser.IsAdminUser,
+			"role":         role,
 		},
 	})
 }
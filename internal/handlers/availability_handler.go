@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+const (
+	capacitySettingsKey = "capacity"
+	maxAvailabilityDays = 366 // Longest range GetAvailability will return
+)
+
+// loadCapacity returns the capacity settings, defaulting to unlimited when none are saved
+func (h *Handler) loadCapacity(ctx context.Context) (*models.CapacitySettings, error) {
+	var settings models.CapacitySettings
+	if err := h.store.Settings.Get(ctx, capacitySettingsKey, &settings); err != nil {
+		if err == storage.ErrNotFound {
+			return &models.CapacitySettings{DateOverrides: map[string]int{}}, nil
+		}
+		return nil, err
+	}
+	if settings.DateOverrides == nil {
+		settings.DateOverrides = map[string]int{}
+	}
+	return &settings, nil
+}
+
+// capacityLimits returns the booking limits for a day (YYYY-MM-DD) and whether the
+// day accepts bookings at all. A date override replaces the per-day limit.
+func capacityLimits(settings *models.CapacitySettings, day string) (storage.CapacityLimits, bool) {
+	limits := storage.CapacityLimits{PerDay: settings.TeamsPerDay, PerSlot: settings.TeamsPerSlot}
+	if override, ok := settings.DateOverrides[day]; ok {
+		if override == 0 {
+			return limits, false
+		}
+		limits.PerDay = override
+	}
+	return limits, true
+}
+
+// hasCapacity reports whether a booking currently fits within limits. It lets
+// CreateBooking fail early; CreateWithinCapacity still enforces limits atomically.
+func (h *Handler) hasCapacity(ctx context.Context, booking *models.Booking, limits storage.CapacityLimits) (bool, error) {
+	if limits.PerDay == 0 && limits.PerSlot == 0 {
+		return true, nil
+	}
+	counts, err := h.store.Bookings.CountBySlot(ctx, booking.EventDate, booking.EventDate)
+	if err != nil {
+		return false, err
+	}
+
+	dayCount, slotCount := 0, 0
+	for _, sc := range counts {
+		dayCount += sc.Count
+		if sc.Slot == booking.TimeSlot {
+			slotCount += sc.Count
+		}
+	}
+	if limits.PerDay > 0 && dayCount >= limits.PerDay {
+		return false, nil
+	}
+	return limits.PerSlot == 0 || booking.TimeSlot == "" || slotCount < limits.PerSlot, nil
+}
+
+// GetAvailability reports how booked each day in a date range is
+func (h *Handler) GetAvailability(c *gin.Context) {
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing from date. Use YYYY-MM-DD"})
+		return
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing to date. Use YYYY-MM-DD"})
+		return
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+	if to.Sub(from) >= maxAvailabilityDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Date range is too long. Request at most 366 days"})
+		return
+	}
+
+	ctx := context.Background()
+
+	settings, err := h.loadCapacity(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	counts, err := h.store.Bookings.CountBySlot(ctx, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve bookings", "details": err.Error()})
+		return
+	}
+	slotsByDay := map[string]map[string]int{}
+	for _, sc := range counts {
+		if slotsByDay[sc.Day] == nil {
+			slotsByDay[sc.Day] = map[string]int{}
+		}
+		slotsByDay[sc.Day][sc.Slot] += sc.Count
+	}
+
+	days := []models.DayAvailability{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := date.Format("2006-01-02")
+		slots := slotsByDay[day]
+		if slots == nil {
+			slots = map[string]int{}
+		}
+
+		availability := models.DayAvailability{Date: day, Slots: slots}
+		for _, count := range slots {
+			availability.Booked += count
+		}
+
+		limits, open := capacityLimits(settings, day)
+		switch {
+		case !open:
+			zero := 0
+			availability.Capacity, availability.Remaining = &zero, &zero
+			availability.Status = models.AvailabilityFull
+		case limits.PerDay > 0:
+			remaining := limits.PerDay - availability.Booked
+			if remaining < 0 {
+				remaining = 0
+			}
+			availability.Capacity, availability.Remaining = &limits.PerDay, &remaining
+			availability.Status = dayStatus(availability.Booked, remaining)
+		default:
+			// Unlimited days are only limited once a slot is taken and slots are capped
+			availability.Status = models.AvailabilityFree
+			if limits.PerSlot > 0 && availability.Booked > 0 {
+				availability.Status = models.AvailabilityLimited
+			}
+		}
+		days = append(days, availability)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":         from.Format("2006-01-02"),
+		"to":           to.Format("2006-01-02"),
+		"teamsPerSlot": settings.TeamsPerSlot,
+		"days":         days,
+	})
+}
+
+// dayStatus classifies a capped day by how many bookings it has left
+func dayStatus(booked, remaining int) string {
+	switch {
+	case remaining == 0:
+		return models.AvailabilityFull
+	case booked > 0:
+		return models.AvailabilityLimited
+	default:
+		return models.AvailabilityFree
+	}
+}
+
+// GetCapacity retrieves the current capacity settings
+func (h *Handler) GetCapacity(c *gin.Context) {
+	settings, err := h.loadCapacity(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"capacity": settings})
+}
+
+// UpdateCapacity replaces the capacity settings
+func (h *Handler) UpdateCapacity(c *gin.Context) {
+	var request models.UpdateCapacityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Normalize override dates so lookups by formatted day match
+	overrides := make(map[string]int, len(request.DateOverrides))
+	for day, teams := range request.DateOverrides {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(day))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date override " + day + ". Use YYYY-MM-DD"})
+			return
+		}
+		overrides[date.Format("2006-01-02")] = teams
+	}
+
+	settings := models.CapacitySettings{
+		TeamsPerDay:   request.TeamsPerDay,
+		TeamsPerSlot:  request.TeamsPerSlot,
+		DateOverrides: overrides,
+		UpdatedAt:     time.Now(),
+	}
+	if claims := currentClaims(c); claims != nil {
+		settings.UpdatedBy = claims.Username
+	}
+
+	if err := h.store.Settings.Put(context.Background(), capacitySettingsKey, &settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update capacity", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Capacity updated successfully",
+		"capacity": settings,
+	})
+}
@@ -2,89 +2,108 @@ package handlers
 
 import (
 	"context"
-	"crypto/rand"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/modernband/booking/internal/database"
 	"github.com/modernband/booking/internal/models"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/modernband/booking/internal/notify"
+	"github.com/modernband/booking/internal/storage"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// generateBookingID creates a unique 6-character alphanumeric booking ID
-func generateBookingID() (string, error) {
-	// Define character set (alphanumeric)
-	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	const length = 6
-	const charsetLen = byte(len(charset))
-
-	// Create a byte slice for the result
-	result := make([]byte, length)
-
-	for i := 0; i < length; i++ {
-		// We need a random number from 0 to len(charset)-1
-		// To avoid modulo bias, we get a random byte and check if it's in the valid range
-		max := 256 - (256 % int(charsetLen))
-		b := make([]byte, 1)
-		for {
-			_, err := rand.Read(b)
-			if err != nil {
-				return "", err
-			}
-			// Reject values that would create modulo bias
-			if int(b[0]) < max {
-				// Use modulo now that we've eliminated the bias
-				result[i] = charset[b[0]%charsetLen]
-				break
-			}
-		}
-	}
-
-	return string(result), nil
-}
-
-// checkBookingIDExists checks if a booking ID already exists in the database
-func checkBookingIDExists(ctx context.Context, coll *mongo.Collection, id string) (bool, error) {
-	count, err := coll.CountDocuments(ctx, bson.M{"booking_id": id})
+// findBooking looks up the booking named by the :id parameter, writing the error
+// response if it cannot be found
+func (h *Handler) findBooking(ctx context.Context, c *gin.Context) (*models.Booking, bool) {
+	booking, err := h.store.Bookings.FindByBookingID(ctx, c.Param("id"))
 	if err != nil {
-		return false, err
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return nil, false
 	}
-	return count > 0, nil
+	return booking, true
 }
 
 // CreateBooking handles the creation of a new booking
-func CreateBooking(c *gin.Context) {
+func (h *Handler) CreateBooking(c *gin.Context) {
 	var booking models.Booking
 	if err := c.ShouldBindJSON(&booking); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
 		return
 	}
 
-	// Get MongoDB collection
-	coll := database.GetCollection("bookings")
 	ctx := context.Background()
 
+	// Bookings are per calendar day; store the event date as midnight UTC
+	year, month, day := booking.EventDate.Date()
+	booking.EventDate = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	booking.TimeSlot = booking.SlotName()
+
+	capacity, err := h.loadCapacity(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	limits, open := capacityLimits(capacity, booking.EventDate.Format("2006-01-02"))
+	if !open {
+		c.JSON(http.StatusConflict, gin.H{"error": "The band is not taking bookings on the selected date"})
+		return
+	}
+	available, err := h.hasCapacity(ctx, &booking, limits)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if !available {
+		c.JSON(http.StatusConflict, gin.H{"error": "The selected date and time slot is fully booked"})
+		return
+	}
+	stock, ok := h.checkStock(ctx, c, &booking, nil)
+	if !ok {
+		return
+	}
+	limits.Stock = stock
+
+	// Price the booking server-side; a client-supplied total must match
+	quote, err := h.quoteBooking(ctx, &booking)
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+	if booking.Amount != 0 && booking.Amount != quote.Total {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Amount does not match the calculated price",
+			"quote": quote,
+		})
+		return
+	}
+	booking.Quote = quote
+	booking.Amount = quote.Total
+
+	// The booking phone needs a fresh OTP verification, which the insert uses up
+	booking.Phone = models.NormalizePhone(booking.Phone)
+
 	// Try to generate a unique booking ID up to 5 times
 	var bookingID string
-	var err error
 	var exists bool
 
 	for attempt := 0; attempt < 5; attempt++ {
 		// Generate a booking ID
-		bookingID, err = generateBookingID()
+		bookingID, err = models.GenerateBookingID()
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate booking ID"})
 			return
 		}
 
 		// Check if ID already exists
-		exists, err = checkBookingIDExists(ctx, coll, bookingID)
+		exists, err = h.store.Bookings.Exists(ctx, bookingID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 			return
@@ -102,23 +121,44 @@ func CreateBooking(c *gin.Context) {
 		}
 	}
 
-	// Set booking fields
+	// Set booking fields; new bookings are enquiries until staff confirm them, and
+	// start out live, unarchived and unedited whatever the request said
+	booking.ID = primitive.NilObjectID
 	booking.BookingID = bookingID
 	booking.CreatedAt = time.Now()
 	booking.PhoneVerified = true
-
-	// Insert booking into MongoDB
-	result, err := coll.InsertOne(ctx, booking)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create booking", "details": err.Error()})
+	booking.AdvancePayment = 0 // Advances are recorded in the payment ledger
+	booking.Status = models.BookingStatusEnquiry
+	booking.StatusHistory = []models.StatusChange{
+		{To: models.BookingStatusEnquiry, At: booking.CreatedAt, By: "customer"},
+	}
+	booking.Revision = 0
+	booking.ArchivedAt = nil
+	booking.DeletedAt, booking.DeletedBy = nil, ""
+
+	// Insert booking unless its date or time slot is already fully booked, its equipment
+	// is out of stock or its phone is not verified; each verification books once
+	if err := h.store.Bookings.CreateWithinCapacity(ctx, &booking, limits, time.Now().Add(-verificationWindow)); err != nil {
+		switch err {
+		case storage.ErrNotVerified:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Phone number is not verified. Please verify it with an OTP before booking"})
+		case storage.ErrCapacityExceeded:
+			c.JSON(http.StatusConflict, gin.H{"error": "The selected date and time slot is fully booked"})
+		case storage.ErrInsufficientStock:
+			c.JSON(http.StatusConflict, gin.H{"error": "Not enough equipment is available on the selected date"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create booking", "details": err.Error()})
+		}
 		return
 	}
 
-	// Set the MongoDB ID
-	booking.ID = result.InsertedID.(primitive.ObjectID)
+	if err := h.store.AttachBalances(ctx, &booking); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balance", "details": err.Error()})
+		return
+	}
 
-	// Send booking confirmation (as a log, since we're simulating)
-	log.Printf("BOOKING CONFIRMATION: Dear %s, your booking with Modern Band (ID: %s) has been confirmed! We look forward to making your event special.", booking.Name, booking.BookingID)
+	// Acknowledge the booking request
+	h.notifyCustomer(ctx, notify.EventBookingReceived, &booking, notify.BookingData(&booking))
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Booking created successfully",
@@ -127,7 +167,7 @@ func CreateBooking(c *gin.Context) {
 }
 
 // GetBooking retrieves booking details by ID or phone number
-func GetBooking(c *gin.Context) {
+func (h *Handler) GetBooking(c *gin.Context) {
 	bookingID := c.Query("booking_id")
 	contactNumber := c.Query("contact_number")
 
@@ -136,149 +176,199 @@ func GetBooking(c *gin.Context) {
 		return
 	}
 
-	coll := database.GetCollection("bookings")
 	ctx := context.Background()
 
-	var filter bson.M
 	if bookingID != "" {
-		filter = bson.M{"booking_id": bookingID}
-	} else {
-		filter = bson.M{"phone": contactNumber}
-	}
-
-	// Set options for sorting by created_at in descending order
-	opts := options.Find()
-	if contactNumber != "" {
-		opts.SetSort(bson.D{{"created_at", -1}})
+		// If querying by ID, return single booking
+		booking, err := h.store.Bookings.FindByBookingID(ctx, bookingID)
+		if err != nil {
+			if err == storage.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "No bookings found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking"})
+			}
+			return
+		}
+		if err := h.store.AttachBalances(ctx, booking); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"booking": booking})
+		return
 	}
 
-	cursor, err := coll.Find(ctx, filter, opts)
+	// If querying by phone, return multiple bookings sorted by created_at in descending order
+	bookings, err := h.store.Bookings.FindByPhone(ctx, models.NormalizePhone(contactNumber))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking"})
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var bookings []models.Booking
-	if err = cursor.All(ctx, &bookings); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse booking data"})
-		return
-	}
 
 	if len(bookings) == 0 {
 		c.JSON(http.StatusNotFound, gin.H{"error": "No bookings found"})
 		return
 	}
-
-	if bookingID != "" && len(bookings) == 1 {
-		// If querying by ID, return single booking
-		c.JSON(http.StatusOK, gin.H{"booking": bookings[0]})
-	} else {
-		// If querying by phone, return multiple bookings
-		c.JSON(http.StatusOK, gin.H{"bookings": bookings})
+	if err := h.store.AttachBalances(ctx, bookingPointers(bookings)...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking"})
+		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{"bookings": bookings})
 }
 
-// GetAllBookings retrieves all bookings from the database
-func GetAllBookings(c *gin.Context) {
-	coll := database.GetCollection("bookings")
-	ctx := context.Background()
+const (
+	defaultPageSize = 50  // Bookings per page when no limit is given
+	maxPageSize     = 200 // Largest page GetAllBookings returns
+)
 
-	// Set options for sorting by created_at in descending order
-	opts := options.Find().SetSort(bson.D{{"created_at", -1}})
+// bookingSorts lists the values accepted by the sort query parameter. A leading
+// "-" sorts in descending order.
+var bookingSorts = map[string]bool{
+	storage.SortCreatedAt: true,
+	storage.SortEventDate: true,
+	storage.SortAmount:    true,
+	storage.SortName:      true,
+}
 
-	cursor, err := coll.Find(ctx, bson.M{}, opts)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve bookings", "details": err.Error()})
-		return
+// parseBookingFilter reads the booking listing filters from the query string:
+// status, from and to (event dates, inclusive), city, packageType, phone and
+// archived. It writes a 400 response and returns false if a filter is invalid.
+func parseBookingFilter(c *gin.Context) (storage.BookingFilter, bool) {
+	filter := storage.BookingFilter{
+		Status:      c.Query("status"),
+		City:        strings.TrimSpace(c.Query("city")),
+		PackageType: c.Query("packageType"),
+		Archived:    c.Query("archived"),
 	}
-	defer cursor.Close(ctx)
-
-	var bookings []models.Booking
-	if err = cursor.All(ctx, &bookings); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse booking data", "details": err.Error()})
-		return
+	if filter.Status != "" && !models.IsValidBookingStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use enquiry, confirmed, completed or cancelled"})
+		return filter, false
 	}
-
-	// Check if any bookings were found
-	if len(bookings) == 0 {
-		c.JSON(http.StatusOK, gin.H{"bookings": []models.Booking{}, "count": 0})
-		return
+	switch filter.Archived {
+	case "exclude":
+		filter.Archived = storage.ArchivedExclude
+	case storage.ArchivedExclude, storage.ArchivedOnly, storage.ArchivedInclude:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid archived. Use exclude, only or include"})
+		return filter, false
+	}
+	if phone := c.Query("phone"); phone != "" {
+		filter.Phone = models.NormalizePhone(phone)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"bookings": bookings,
-		"count":    len(bookings),
-	})
+	var ok bool
+	filter.From, filter.Before, ok = parsePeriod(c)
+	return filter, ok
 }
 
-// DeleteBooking deletes a booking by ID
-func DeleteBooking(c *gin.Context) {
-	bookingID := c.Param("id")
+// parseBookingSort reads the sort query parameter; the newest bookings come first
+// by default. It writes a 400 response and returns false if it is invalid.
+func parseBookingSort(c *gin.Context) (storage.BookingPage, bool) {
+	page := storage.BookingPage{Sort: storage.SortCreatedAt, Desc: true}
+	if sort := c.Query("sort"); sort != "" {
+		field := strings.TrimPrefix(sort, "-")
+		if !bookingSorts[field] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort. Use createdAt, date, amount or name, prefixed with - for descending order"})
+			return page, false
+		}
+		page.Sort, page.Desc = field, strings.HasPrefix(sort, "-")
+	}
+	return page, true
+}
 
-	// Add more debug logging
-	fmt.Printf("DeleteBooking handler called with ID: '%s'\n", bookingID)
+// parseBookingPage reads the sort, page and limit query parameters. It writes a
+// 400 response and returns false if one is invalid.
+func parseBookingPage(c *gin.Context) (storage.BookingPage, int, bool) {
+	page, ok := parseBookingSort(c)
+	if !ok {
+		return page, 0, false
+	}
+	page.Limit = defaultPageSize
+
+	number := 1
+	if value := c.Query("page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive number"})
+			return page, 0, false
+		}
+		number = n
+	}
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxPageSize)})
+			return page, 0, false
+		}
+		page.Limit = n
+	}
+	page.Offset = (number - 1) * page.Limit
+	return page, number, true
+}
 
-	if bookingID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Booking ID is required"})
+// GetAllBookings retrieves a page of bookings, optionally filtered and sorted
+func (h *Handler) GetAllBookings(c *gin.Context) {
+	filter, ok := parseBookingFilter(c)
+	if !ok {
+		return
+	}
+	page, number, ok := parseBookingPage(c)
+	if !ok {
 		return
 	}
 
-	coll := database.GetCollection("bookings")
 	ctx := context.Background()
 
-	// Check if booking exists
-	count, err := coll.CountDocuments(ctx, bson.M{"booking_id": bookingID})
+	bookings, total, err := h.store.Bookings.List(ctx, filter, page)
 	if err != nil {
-		fmt.Printf("Database error when checking if booking exists: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
-		return
-	}
-
-	fmt.Printf("Found %d bookings with ID: '%s'\n", count, bookingID)
-	if count == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve bookings", "details": err.Error()})
 		return
 	}
-
-	// Delete the booking
-	result, err := coll.DeleteOne(ctx, bson.M{"booking_id": bookingID})
-	if err != nil {
-		fmt.Printf("Database error when deleting booking: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete booking", "details": err.Error()})
+	if err := h.store.AttachBalances(ctx, bookingPointers(bookings)...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balances", "details": err.Error()})
 		return
 	}
 
-	fmt.Printf("Successfully deleted booking with ID: '%s'\n", bookingID)
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Booking deleted successfully",
-		"id":      bookingID,
-		"count":   result.DeletedCount,
+		"bookings":   bookings,
+		"count":      len(bookings),
+		"total":      total,
+		"page":       number,
+		"limit":      page.Limit,
+		"totalPages": (total + int64(page.Limit) - 1) / int64(page.Limit),
 	})
 }
 
-// DeletePastBookings deletes all bookings with event dates in the past
-func DeletePastBookings(c *gin.Context) {
-	coll := database.GetCollection("bookings")
-	ctx := context.Background()
+// DeleteBooking moves a booking to the trash by ID with its crew. It can be
+// restored until the trash is purged.
+func (h *Handler) DeleteBooking(c *gin.Context) {
+	bookingID := c.Param("id")
+	if bookingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Booking ID is required"})
+		return
+	}
 
-	// Calculate the start of today (midnight)
-	now := time.Now()
-	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var by string
+	if claims := currentClaims(c); claims != nil {
+		by = claims.Username
+	}
 
-	// Delete bookings with event dates from yesterday or older
-	result, err := coll.DeleteMany(ctx, bson.M{
-		"event_date": bson.M{
-			"$lt": startOfToday, // Only delete bookings before today's start (midnight)
-		},
-	})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete past bookings", "details": err.Error()})
+	ctx := context.Background()
+
+	if err := h.store.Bookings.Delete(ctx, bookingID, by, time.Now()); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		} else {
+			log.Printf("Database error when deleting booking %s: %v", bookingID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete booking", "details": err.Error()})
+		}
 		return
 	}
 
+	log.Printf("Moved booking %s to the trash", bookingID)
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Past bookings (before today) deleted successfully",
-		"count":   result.DeletedCount,
+		"message": "Booking deleted successfully",
+		"id":      bookingID,
+		"count":   1,
 	})
 }
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/notify"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// ConfirmBooking moves an enquiry to confirmed
+func (h *Handler) ConfirmBooking(c *gin.Context) {
+	booking, ok := h.changeBookingStatus(c, models.BookingStatusConfirmed)
+	if !ok {
+		return
+	}
+
+	h.notifyCustomer(context.Background(), notify.EventBookingConfirmed, booking, notify.BookingData(booking))
+}
+
+// CompleteBooking marks a confirmed booking as completed once its event date has arrived
+func (h *Handler) CompleteBooking(c *gin.Context) {
+	h.changeBookingStatus(c, models.BookingStatusCompleted)
+}
+
+// CancelBooking cancels an enquiry or confirmed booking, freeing its slot and crew,
+// crediting its tax invoice if one was issued and notifying the customer
+func (h *Handler) CancelBooking(c *gin.Context) {
+	booking, ok := h.changeBookingStatus(c, models.BookingStatusCancelled)
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+	h.releaseCrew(ctx, booking.BookingID)
+	h.creditCancelledBooking(ctx, booking)
+
+	data := notify.BookingData(booking)
+	data.Reason = booking.StatusHistory[len(booking.StatusHistory)-1].Reason
+	h.notifyCustomer(ctx, notify.EventBookingCancelled, booking, data)
+}
+
+// changeBookingStatus moves the booking named by the :id parameter to status and
+// writes the response. It returns the updated booking and whether it succeeded.
+func (h *Handler) changeBookingStatus(c *gin.Context, status string) (*models.Booking, bool) {
+	// The reason is optional, so an empty body is allowed
+	var request models.BookingStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return nil, false
+	}
+
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return nil, false
+	}
+
+	from := booking.CurrentStatus()
+	if !models.CanTransition(from, status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot change booking status from " + from + " to " + status})
+		return nil, false
+	}
+
+	now := time.Now()
+	if status == models.BookingStatusCompleted && now.Before(booking.EventDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A booking cannot be completed before its event date"})
+		return nil, false
+	}
+
+	change := models.StatusChange{From: from, To: status, At: now, Reason: request.Reason}
+	if claims := currentClaims(c); claims != nil {
+		change.By = claims.Username
+	}
+
+	if err := h.store.Bookings.UpdateStatus(ctx, booking.BookingID, from, change); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		case storage.ErrConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": "Booking status was changed by someone else. Please reload and try again"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update booking status", "details": err.Error()})
+		}
+		return nil, false
+	}
+
+	booking.Status = status
+	booking.StatusHistory = append(booking.StatusHistory, change)
+	if err := h.store.AttachBalances(ctx, booking); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balance", "details": err.Error()})
+		return nil, false
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Booking " + status + " successfully",
+		"booking": booking,
+	})
+	return booking, true
+}
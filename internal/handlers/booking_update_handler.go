@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// setField applies an optional new value to a booking field, recording the change
+// under the field's JSON name. It reports whether the value changed.
+func setField[T comparable](changes *[]models.FieldChange, field string, value *T, target *T) bool {
+	if value == nil || *value == *target {
+		return false
+	}
+	*changes = append(*changes, models.FieldChange{Field: field, From: *target, To: *value})
+	*target = *value
+	return true
+}
+
+// UpdateBooking edits the fields of an enquiry or confirmed booking, re-pricing it
+// when the package, add-ons, city or date change, and records a revision
+func (h *Handler) UpdateBooking(c *gin.Context) {
+	var request models.UpdateBookingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+
+	status := booking.CurrentStatus()
+	if status != models.BookingStatusEnquiry && status != models.BookingStatusConfirmed {
+		c.JSON(http.StatusConflict, gin.H{"error": "A " + status + " booking cannot be edited"})
+		return
+	}
+
+	updated := *booking
+	changes := []models.FieldChange{}
+
+	// Fields that do not affect price or capacity
+	setField(&changes, "name", request.Name, &updated.Name)
+	setField(&changes, "email", request.Email, &updated.Email)
+	setField(&changes, "additionalPhone", request.AdditionalPhone, &updated.AdditionalPhone)
+	setField(&changes, "venue", request.Venue, &updated.Venue)
+	setField(&changes, "customization", request.Customization, &updated.Customization)
+	setField(&changes, "numberOfPeople", request.NumberOfPeople, &updated.NumberOfPeople)
+
+	// Time slot changes need a capacity check
+	slotChanged := setField(&changes, "bandTime", request.BandTime, &updated.BandTime)
+	slotChanged = setField(&changes, "customTimeSlot", request.CustomTimeSlot, &updated.CustomTimeSlot) || slotChanged
+	updated.TimeSlot = updated.SlotName()
+	slotChanged = slotChanged && updated.TimeSlot != booking.TimeSlot
+
+	// Date changes need a capacity check and re-pricing
+	dateChanged := false
+	if request.EventDate != nil {
+		date, err := time.Parse("2006-01-02", *request.EventDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+		from, to := booking.EventDate.UTC().Format("2006-01-02"), date.Format("2006-01-02")
+		if dateChanged = setField(&changes, "date", &to, &from); dateChanged {
+			updated.EventDate = date
+		}
+	}
+
+	// Fields that change the price
+	repriced := dateChanged
+	repriced = setField(&changes, "packageType", request.PackageType, &updated.PackageType) || repriced
+	repriced = setField(&changes, "city", request.City, &updated.City) || repriced
+	repriced = setField(&changes, "numberOfLights", request.NumberOfLights, &updated.NumberOfLights) || repriced
+	repriced = setField(&changes, "numberOfDhols", request.NumberOfDhols, &updated.NumberOfDhols) || repriced
+	repriced = setField(&changes, "ghodaBaggi", request.GhodaBaggi, &updated.GhodaBaggi) || repriced
+	repriced = setField(&changes, "ghodiForBaraat", request.GhodiForBaraat, &updated.GhodiForBaraat) || repriced
+	repriced = setField(&changes, "fireworks", request.Fireworks, &updated.Fireworks) || repriced
+	repriced = setField(&changes, "fireworksAmount", request.FireworksAmount, &updated.FireworksAmount) || repriced
+	repriced = setField(&changes, "flowerCanon", request.FlowerCanon, &updated.FlowerCanon) || repriced
+	repriced = setField(&changes, "DoliForVidai", request.DoliForVidai, &updated.DoliForVidai) || repriced
+
+	if len(changes) == 0 {
+		if err := h.store.AttachBalances(ctx, booking); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balance", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message": "No changes",
+			"booking": booking,
+		})
+		return
+	}
+
+	if repriced {
+		quote, err := h.quoteBooking(ctx, &updated)
+		if err != nil {
+			respondQuoteError(c, err)
+			return
+		}
+		setField(&changes, "amount", &quote.Total, &updated.Amount)
+		updated.Quote = quote
+	}
+
+	var limits *storage.CapacityLimits
+	if dateChanged || slotChanged {
+		// Assigned crew were checked for overlaps against the old date and time slot
+		crew, err := h.store.Assignments.ListByBooking(ctx, booking.BookingID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		if len(crew) > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Remove the assigned crew before changing the date or time slot"})
+			return
+		}
+
+		capacity, err := h.loadCapacity(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		dayLimits, open := capacityLimits(capacity, updated.EventDate.Format("2006-01-02"))
+		if !open {
+			c.JSON(http.StatusConflict, gin.H{"error": "The band is not taking bookings on the selected date"})
+			return
+		}
+		limits = &dayLimits
+	}
+
+	// Equipment is reserved per day, so it is checked again when the date or quantities change
+	if dateChanged || !maps.Equal(updated.Equipment(), booking.Equipment()) {
+		stock, ok := h.checkStock(ctx, c, &updated, booking)
+		if !ok {
+			return
+		}
+		if len(stock) > 0 {
+			if limits == nil {
+				limits = &storage.CapacityLimits{}
+			}
+			limits.Stock = stock
+		}
+	}
+
+	updated.Revision = booking.Revision + 1
+	revision := models.BookingRevision{
+		BookingID: updated.BookingID,
+		Revision:  updated.Revision,
+		Changes:   changes,
+		ChangedAt: time.Now(),
+	}
+	if claims := currentClaims(c); claims != nil {
+		revision.ChangedBy = claims.Username
+	}
+
+	if err := h.store.Bookings.Update(ctx, &updated, &revision, limits); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		case storage.ErrConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": "Booking was changed by someone else. Please reload and try again"})
+		case storage.ErrCapacityExceeded:
+			c.JSON(http.StatusConflict, gin.H{"error": "The selected date and time slot is fully booked"})
+		case storage.ErrInsufficientStock:
+			c.JSON(http.StatusConflict, gin.H{"error": "Not enough equipment is available on the selected date"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update booking", "details": err.Error()})
+		}
+		return
+	}
+
+	if err := h.store.AttachBalances(ctx, &updated); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balance", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Booking updated successfully",
+		"booking": updated,
+		"changes": changes,
+	})
+}
+
+// GetBookingHistory returns a booking's edit revisions and status changes
+func (h *Handler) GetBookingHistory(c *gin.Context) {
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.store.Bookings.ListRevisions(ctx, booking.BookingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking history", "details": err.Error()})
+		return
+	}
+
+	statusHistory := booking.StatusHistory
+	if statusHistory == nil {
+		statusHistory = []models.StatusChange{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bookingId":     booking.BookingID,
+		"revision":      booking.Revision,
+		"revisions":     revisions,
+		"statusHistory": statusHistory,
+	})
+}
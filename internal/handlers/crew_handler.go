@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AssignCrew assigns an employee to a booking in a role
+func (h *Handler) AssignCrew(c *gin.Context) {
+	var request models.AssignCrewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+	status := booking.CurrentStatus()
+	if status != models.BookingStatusEnquiry && status != models.BookingStatusConfirmed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Crew cannot be assigned to a " + status + " booking"})
+		return
+	}
+
+	employee, err := h.store.Employees.FindByUsername(ctx, request.Username)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	// The wage is fixed when the employee is assigned so later rate changes do not alter it
+	wage := request.Wage
+	if wage == nil {
+		rates, err := h.loadWageRates(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		rate := rates.Rates[request.Role]
+		wage = &rate
+	}
+
+	assignment := models.CrewAssignment{
+		BookingID:  booking.BookingID,
+		EmployeeID: employee.ID,
+		Username:   employee.Username,
+		Role:       request.Role,
+		Wage:       *wage,
+		EventDate:  booking.EventDate,
+		TimeSlot:   booking.TimeSlot,
+		AssignedAt: time.Now(),
+	}
+	if claims := currentClaims(c); claims != nil {
+		assignment.AssignedBy = claims.Username
+	}
+
+	if err := h.store.Assignments.Create(ctx, &assignment); err != nil {
+		switch err {
+		case storage.ErrDuplicate:
+			c.JSON(http.StatusConflict, gin.H{"error": "Employee is already assigned to this booking"})
+		case storage.ErrConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": "Employee is already assigned to another event at the same time"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign crew", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Crew assigned successfully",
+		"assignment": assignment,
+	})
+}
+
+// GetBookingCrew lists the employees assigned to a booking
+func (h *Handler) GetBookingCrew(c *gin.Context) {
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+
+	crew, err := h.store.Assignments.ListByBooking(ctx, booking.BookingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve crew", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bookingId": booking.BookingID,
+		"crew":      crew,
+		"count":     len(crew),
+	})
+}
+
+// RemoveCrew removes an employee's assignment from a booking
+func (h *Handler) RemoveCrew(c *gin.Context) {
+	assignmentID, err := primitive.ObjectIDFromHex(c.Param("assignmentID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID format"})
+		return
+	}
+
+	if err := h.store.Assignments.Delete(context.Background(), c.Param("id"), assignmentID); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove crew", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Crew removed successfully",
+	})
+}
+
+// GetEmployeeAssignments lists an employee's upcoming assignments, or all of them with ?all=true
+func (h *Handler) GetEmployeeAssignments(c *gin.Context) {
+	ctx := context.Background()
+
+	employee, err := h.store.Employees.FindByUsername(ctx, c.Param("username"))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	var from time.Time
+	if c.Query("all") != "true" {
+		from = startOfToday()
+	}
+
+	assignments, err := h.employeeAssignments(ctx, employee.ID, from)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve assignments", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"assignments": assignments,
+		"count":       len(assignments),
+	})
+}
+
+// employeeAssignments returns an employee's assignments for events on or after
+// from together with the details of each event
+func (h *Handler) employeeAssignments(ctx context.Context, employeeID primitive.ObjectID, from time.Time) ([]models.AssignmentResponse, error) {
+	assignments, err := h.store.Assignments.ListByEmployee(ctx, employeeID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []models.AssignmentResponse{}
+	for _, a := range assignments {
+		booking, err := h.store.Bookings.FindByBookingID(ctx, a.BookingID)
+		if err == storage.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, models.AssignmentResponse{
+			CrewAssignment: a,
+			Venue:          booking.Venue,
+			City:           booking.City,
+			BandTime:       booking.BandTime,
+			PackageType:    booking.PackageType,
+			BookingStatus:  booking.CurrentStatus(),
+		})
+	}
+	return response, nil
+}
+
+// releaseCrew removes the crew of a booking that will no longer take place
+func (h *Handler) releaseCrew(ctx context.Context, bookingID string) {
+	if err := h.store.Assignments.DeleteByBooking(ctx, bookingID); err != nil {
+		log.Printf("Failed to release crew of booking %s: %v", bookingID, err)
+	}
+}
+
+// releaseUpcoming takes an employee off the events from today on, logging rather
+// than returning failures so the caller's change stands. Past assignments are kept
+// for the employee's history.
+func (h *Handler) releaseUpcoming(ctx context.Context, employee *models.Employee) {
+	assignments, err := h.store.Assignments.ListByEmployee(ctx, employee.ID, startOfToday())
+	if err != nil {
+		log.Printf("Failed to release %s from upcoming events: %v", employee.Username, err)
+		return
+	}
+	for _, a := range assignments {
+		if err := h.store.Assignments.Delete(ctx, a.BookingID, a.ID); err != nil && err != storage.ErrNotFound {
+			log.Printf("Failed to release %s from booking %s: %v", employee.Username, a.BookingID, err)
+		}
+	}
+}
+
+// startOfToday returns midnight UTC of the current UTC day, matching how event dates are stored
+func startOfToday() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/notify"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// bookingPointers returns pointers to the elements of bookings
+func bookingPointers(bookings []models.Booking) []*models.Booking {
+	pointers := make([]*models.Booking, len(bookings))
+	for i := range bookings {
+		pointers[i] = &bookings[i]
+	}
+	return pointers
+}
+
+// GetBookingPayments lists the payments received for a booking with its balance
+func (h *Handler) GetBookingPayments(c *gin.Context) {
+	ctx := context.Background()
+
+	booking, payments, ok := h.bookingWithPayments(ctx, c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bookingId":      booking.BookingID,
+		"amount":         booking.Amount,
+		"advancePayment": booking.AdvancePayment,
+		"amountPaid":     booking.AmountPaid,
+		"balanceDue":     booking.BalanceDue,
+		"payments":       payments,
+	})
+}
+
+// RecordBookingPayment records a payment received from the customer of a booking
+func (h *Handler) RecordBookingPayment(c *gin.Context) {
+	var request models.RecordCustomerPaymentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Only cash can be traced without a reference
+	reference := strings.TrimSpace(request.Reference)
+	if request.Method != models.PaymentMethodCash && reference == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A reference is required for " + request.Method + " payments"})
+		return
+	}
+
+	now := time.Now()
+	paidAt := now
+	if request.Date != "" {
+		date, err := time.Parse("2006-01-02", request.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+		paidAt = date
+	}
+
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+	if booking.CurrentStatus() == models.BookingStatusCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payments cannot be recorded for a cancelled booking"})
+		return
+	}
+
+	payment := models.CustomerPayment{
+		BookingID: booking.BookingID,
+		Amount:    request.Amount,
+		Method:    request.Method,
+		Reference: reference,
+		PaidAt:    paidAt,
+		CreatedAt: now,
+	}
+	if claims := currentClaims(c); claims != nil {
+		payment.ReceivedBy = claims.Username
+	}
+
+	if err := h.store.CustomerPayments.Create(ctx, &payment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record payment", "details": err.Error()})
+		return
+	}
+	if err := h.store.AttachBalances(ctx, booking); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balance", "details": err.Error()})
+		return
+	}
+	h.notifyCustomer(ctx, notify.EventPaymentReceived, booking, notify.PaymentData(booking, &payment))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Payment recorded successfully",
+		"payment":    payment,
+		"amountPaid": booking.AmountPaid,
+		"balanceDue": booking.BalanceDue,
+	})
+}
+
+// VoidBookingPayment voids a mistaken customer payment, keeping it in the ledger
+func (h *Handler) VoidBookingPayment(c *gin.Context) {
+	paymentID, err := primitive.ObjectIDFromHex(c.Param("paymentID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID format"})
+		return
+	}
+
+	var request models.VoidCustomerPaymentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+
+	by := ""
+	if claims := currentClaims(c); claims != nil {
+		by = claims.Username
+	}
+
+	if err := h.store.CustomerPayments.Void(ctx, booking.BookingID, paymentID, by, request.Reason, time.Now()); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		case storage.ErrConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": "Payment is already voided"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to void payment", "details": err.Error()})
+		}
+		return
+	}
+	if err := h.store.AttachBalances(ctx, booking); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balance", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Payment voided successfully",
+		"amountPaid": booking.AmountPaid,
+		"balanceDue": booking.BalanceDue,
+	})
+}
@@ -6,17 +6,14 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/modernband/booking/internal/database"
 	"github.com/modernband/booking/internal/models"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/modernband/booking/internal/storage"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // CreateEmployee handles the creation of a new employee
-func CreateEmployee(c *gin.Context) {
+func (h *Handler) CreateEmployee(c *gin.Context) {
 	var request models.CreateEmployeeRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
@@ -30,17 +27,16 @@ func CreateEmployee(c *gin.Context) {
 		return
 	}
 
-	coll := database.GetCollection("employees")
 	ctx := context.Background()
 
 	// Check if username already exists
-	count, err := coll.CountDocuments(ctx, bson.M{"username": request.Username})
+	exists, err := h.store.Employees.Exists(ctx, request.Username)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
 	}
 
-	if count > 0 {
+	if exists {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Username already exists"})
 		return
 	}
@@ -61,14 +57,15 @@ func CreateEmployee(c *gin.Context) {
 		UpdatedAt:                now,
 	}
 
-	result, err := coll.InsertOne(ctx, employee)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create employee", "details": err.Error()})
+	if err := h.store.Employees.Create(ctx, &employee); err != nil {
+		if err == storage.ErrDuplicate {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Username already exists"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create employee", "details": err.Error()})
+		}
 		return
 	}
 
-	employee.ID = result.InsertedID.(primitive.ObjectID)
-
 	c.JSON(http.StatusCreated, gin.H{
 		"message":  "Employee created successfully",
 		"employee": employee,
@@ -76,29 +73,18 @@ func CreateEmployee(c *gin.Context) {
 }
 
 // GetAllEmployees retrieves all employees from the database
-func GetAllEmployees(c *gin.Context) {
-	coll := database.GetCollection("employees")
+func (h *Handler) GetAllEmployees(c *gin.Context) {
 	ctx := context.Background()
 
-	// Set options for sorting by created_at in descending order
-	opts := options.Find().SetSort(bson.M{"created_at": -1})
-
-	cursor, err := coll.Find(ctx, bson.M{}, opts)
+	// Employees are returned sorted by created_at in descending order
+	employees, err := h.store.Employees.List(ctx)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve employees", "details": err.Error()})
 		return
 	}
-	defer cursor.Close(ctx)
-
-	// First decode into full Employee structs
-	var employees []models.Employee
-	if err = cursor.All(ctx, &employees); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse employee data", "details": err.Error()})
-		return
-	}
 
 	// Convert to EmployeeResponse structs
-	var response []models.EmployeeResponse
+	response := []models.EmployeeResponse{}
 	for _, emp := range employees {
 		response = append(response, models.EmployeeResponse{
 			ID:                       emp.ID,
@@ -119,23 +105,21 @@ func GetAllEmployees(c *gin.Context) {
 	})
 }
 
-// DeleteEmployee deletes an employee by username
-func DeleteEmployee(c *gin.Context) {
+// DeleteEmployee moves an employee to the trash by username, keeping their
+// payments and earnings, and takes them off upcoming events
+func (h *Handler) DeleteEmployee(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
 		return
 	}
 
-	coll := database.GetCollection("employees")
-	paymentsColl := database.GetCollection("payments")
 	ctx := context.Background()
 
 	// Find employee by username
-	var employee models.Employee
-	err := coll.FindOne(ctx, bson.M{"username": username}).Decode(&employee)
+	employee, err := h.store.Employees.FindByUsername(ctx, username)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == storage.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -143,35 +127,19 @@ func DeleteEmployee(c *gin.Context) {
 		return
 	}
 
-	// Start a session for transaction
-	session, err := database.GetDB().Client().StartSession()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session", "details": err.Error()})
-		return
+	var by string
+	if claims := currentClaims(c); claims != nil {
+		by = claims.Username
 	}
-	defer session.EndSession(ctx)
-
-	// Perform transaction
-	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
-		// Delete associated payments
-		_, err := paymentsColl.DeleteMany(sessCtx, bson.M{"employee_id": employee.ID})
-		if err != nil {
-			return nil, err
-		}
-
-		// Delete employee
-		_, err = coll.DeleteOne(sessCtx, bson.M{"_id": employee.ID})
-		if err != nil {
-			return nil, err
+	if err := h.store.Employees.Delete(ctx, employee.ID, by, time.Now()); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete employee", "details": err.Error()})
 		}
-
-		return nil, nil
-	})
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete employee", "details": err.Error()})
 		return
 	}
+	h.releaseUpcoming(ctx, employee)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Employee deleted successfully",
@@ -179,7 +147,7 @@ func DeleteEmployee(c *gin.Context) {
 }
 
 // AddPayment adds a payment to an employee
-func AddPayment(c *gin.Context) {
+func (h *Handler) AddPayment(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
@@ -192,15 +160,12 @@ func AddPayment(c *gin.Context) {
 		return
 	}
 
-	coll := database.GetCollection("employees")
-	paymentsColl := database.GetCollection("payments")
 	ctx := context.Background()
 
 	// Find employee by username
-	var employee models.Employee
-	err := coll.FindOne(ctx, bson.M{"username": username}).Decode(&employee)
+	employee, err := h.store.Employees.FindByUsername(ctx, username)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == storage.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -223,22 +188,19 @@ func AddPayment(c *gin.Context) {
 		CreatedAt:  time.Now(),
 	}
 
-	result, err := paymentsColl.InsertOne(ctx, payment)
-	if err != nil {
+	if err := h.store.Payments.Create(ctx, &payment); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payment", "details": err.Error()})
 		return
 	}
 
-	payment.ID = result.InsertedID.(primitive.ObjectID)
-
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Payment added successfully",
 		"payment": payment,
 	})
 }
 
-// DeletePayment deletes a payment record
-func DeletePayment(c *gin.Context) {
+// DeletePayment moves a payment record to the trash
+func (h *Handler) DeletePayment(c *gin.Context) {
 	username := c.Param("username")
 	paymentIDStr := c.Param("paymentID")
 
@@ -253,15 +215,12 @@ func DeletePayment(c *gin.Context) {
 		return
 	}
 
-	coll := database.GetCollection("employees")
-	paymentsColl := database.GetCollection("payments")
 	ctx := context.Background()
 
 	// Find employee by username
-	var employee models.Employee
-	err = coll.FindOne(ctx, bson.M{"username": username}).Decode(&employee)
+	employee, err := h.store.Employees.FindByUsername(ctx, username)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == storage.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -269,18 +228,16 @@ func DeletePayment(c *gin.Context) {
 		return
 	}
 
-	// Delete payment
-	result, err := paymentsColl.DeleteOne(ctx, bson.M{
-		"_id":         paymentID,
-		"employee_id": employee.ID,
-	})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payment", "details": err.Error()})
-		return
+	var by string
+	if claims := currentClaims(c); claims != nil {
+		by = claims.Username
 	}
-
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+	if err := h.store.Payments.Delete(ctx, employee.ID, paymentID, by, time.Now()); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payment", "details": err.Error()})
+		}
 		return
 	}
 
@@ -290,22 +247,19 @@ func DeletePayment(c *gin.Context) {
 }
 
 // GetEmployeeDetails retrieves detailed information about an employee
-func GetEmployeeDetails(c *gin.Context) {
+func (h *Handler) GetEmployeeDetails(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
 		return
 	}
 
-	coll := database.GetCollection("employees")
-	paymentsColl := database.GetCollection("payments")
 	ctx := context.Background()
 
 	// Find employee by username
-	var employee models.Employee
-	err := coll.FindOne(ctx, bson.M{"username": username}).Decode(&employee)
+	employee, err := h.store.Employees.FindByUsername(ctx, username)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == storage.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -313,19 +267,25 @@ func GetEmployeeDetails(c *gin.Context) {
 		return
 	}
 
-	// Get employee payments
-	cursor, err := paymentsColl.Find(ctx, bson.M{"employee_id": employee.ID}, options.Find().SetSort(bson.D{{Key: "date", Value: -1}}))
+	// Get employee payments, most recent first
+	payments, err := h.store.Payments.ListByEmployee(ctx, employee.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payments", "details": err.Error()})
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var payments []models.Payment
-	if err = cursor.All(ctx, &payments); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse payment data", "details": err.Error()})
+	// Reconcile the wages earned with the payment records
+	paid, err := h.store.Payments.TotalsByEmployee(ctx, storage.PaymentFilter{EmployeeID: employee.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to total payments", "details": err.Error()})
+		return
+	}
+	earned, err := h.store.Earnings.TotalsByEmployee(ctx, storage.EarningFilter{EmployeeID: employee.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to total earnings", "details": err.Error()})
 		return
 	}
+	balance := employeeBalance(employee, earned[employee.ID].Amount, paid[employee.ID].Amount)
 
 	// Create response
 	response := models.EmployeeResponse{
@@ -339,6 +299,7 @@ func GetEmployeeDetails(c *gin.Context) {
 		TotalAmountPaidInAdvance: employee.TotalAmountPaidInAdvance,
 		Username:                 employee.Username,
 		Payments:                 payments,
+		Balance:                  &balance,
 	}
 
 	c.JSON(http.StatusOK, response)
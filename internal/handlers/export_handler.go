@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/export"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// exportBatchSize is the number of records read from the store at a time
+const exportBatchSize = 500
+
+// exportTimeLayout formats timestamps in exports
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+// bookingExportColumns are the columns a booking export can contain, in default order
+var bookingExportColumns = []export.Column[models.Booking]{
+	{Key: "bookingId", Header: "Booking ID", Value: func(b *models.Booking) interface{} { return b.BookingID }},
+	{Key: "name", Header: "Name", Value: func(b *models.Booking) interface{} { return b.Name }},
+	{Key: "phone", Header: "Phone", Value: func(b *models.Booking) interface{} { return b.Phone }},
+	{Key: "additionalPhone", Header: "Additional Phone", Value: func(b *models.Booking) interface{} { return b.AdditionalPhone }},
+	{Key: "email", Header: "Email", Value: func(b *models.Booking) interface{} { return b.Email }},
+	{Key: "date", Header: "Event Date", Value: func(b *models.Booking) interface{} { return b.EventDate.UTC().Format("2006-01-02") }},
+	{Key: "bandTime", Header: "Band Time", Value: func(b *models.Booking) interface{} { return b.BandTime }},
+	{Key: "venue", Header: "Venue", Value: func(b *models.Booking) interface{} { return b.Venue }},
+	{Key: "city", Header: "City", Value: func(b *models.Booking) interface{} { return b.City }},
+	{Key: "packageType", Header: "Package", Value: func(b *models.Booking) interface{} { return b.PackageType }},
+	{Key: "status", Header: "Status", Value: func(b *models.Booking) interface{} { return b.CurrentStatus() }},
+	{Key: "numberOfLights", Header: "Lights", Value: func(b *models.Booking) interface{} { return b.NumberOfLights }},
+	{Key: "numberOfDhols", Header: "Dhols", Value: func(b *models.Booking) interface{} { return b.NumberOfDhols }},
+	{Key: "ghodaBaggi", Header: "Ghoda Baggi", Value: func(b *models.Booking) interface{} { return b.GhodaBaggi }},
+	{Key: "ghodiForBaraat", Header: "Ghodi", Value: func(b *models.Booking) interface{} { return b.GhodiForBaraat }},
+	{Key: "fireworksAmount", Header: "Fireworks Amount", Value: func(b *models.Booking) interface{} { return b.FireworksAmount }},
+	{Key: "amount", Header: "Amount", Value: func(b *models.Booking) interface{} { return b.Amount }},
+	{Key: "amountPaid", Header: "Amount Paid", Value: func(b *models.Booking) interface{} { return b.AmountPaid }},
+	{Key: "balanceDue", Header: "Balance Due", Value: func(b *models.Booking) interface{} { return b.BalanceDue }},
+	{Key: "createdAt", Header: "Created At", Value: func(b *models.Booking) interface{} { return b.CreatedAt.UTC().Format(exportTimeLayout) }},
+}
+
+// customerPaymentExportColumns are the columns a customer payment export can contain
+var customerPaymentExportColumns = []export.Column[models.CustomerPayment]{
+	{Key: "paidAt", Header: "Paid At", Value: func(p *models.CustomerPayment) interface{} { return p.PaidAt.UTC().Format("2006-01-02") }},
+	{Key: "bookingId", Header: "Booking ID", Value: func(p *models.CustomerPayment) interface{} { return p.BookingID }},
+	{Key: "amount", Header: "Amount", Value: func(p *models.CustomerPayment) interface{} { return p.Amount }},
+	{Key: "method", Header: "Method", Value: func(p *models.CustomerPayment) interface{} { return p.Method }},
+	{Key: "reference", Header: "Reference", Value: func(p *models.CustomerPayment) interface{} { return p.Reference }},
+	{Key: "receivedBy", Header: "Received By", Value: func(p *models.CustomerPayment) interface{} { return p.ReceivedBy }},
+	{Key: "voided", Header: "Voided", Value: func(p *models.CustomerPayment) interface{} { return p.VoidedAt != nil }},
+	{Key: "voidedBy", Header: "Voided By", Value: func(p *models.CustomerPayment) interface{} { return p.VoidedBy }},
+	{Key: "voidReason", Header: "Void Reason", Value: func(p *models.CustomerPayment) interface{} { return p.VoidReason }},
+	{Key: "paymentId", Header: "Payment ID", Value: func(p *models.CustomerPayment) interface{} { return p.ID.Hex() }},
+}
+
+// employeePaymentRow is an employee payment together with the employee paid
+type employeePaymentRow struct {
+	payment  *models.Payment
+	employee models.Employee
+}
+
+// employeePaymentExportColumns are the columns an employee payment export can contain
+var employeePaymentExportColumns = []export.Column[employeePaymentRow]{
+	{Key: "date", Header: "Date", Value: func(r *employeePaymentRow) interface{} { return r.payment.Date.UTC().Format("2006-01-02") }},
+	{Key: "username", Header: "Username", Value: func(r *employeePaymentRow) interface{} { return r.employee.Username }},
+	{Key: "name", Header: "Name", Value: func(r *employeePaymentRow) interface{} { return r.employee.Name }},
+	{Key: "amount", Header: "Amount", Value: func(r *employeePaymentRow) interface{} { return r.payment.AmountPaid }},
+	{Key: "paymentId", Header: "Payment ID", Value: func(r *employeePaymentRow) interface{} { return r.payment.ID.Hex() }},
+	{Key: "createdAt", Header: "Recorded At", Value: func(r *employeePaymentRow) interface{} { return r.payment.CreatedAt.UTC().Format(exportTimeLayout) }},
+}
+
+// parseExport reads the format (csv by default) and columns query parameters. It
+// writes a 400 response and returns false if either is invalid.
+func parseExport[T any](c *gin.Context, all []export.Column[T]) (string, []export.Column[T], bool) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format. Use csv or xlsx"})
+		return "", nil, false
+	}
+
+	var keys []string
+	if value := c.Query("columns"); value != "" {
+		keys = strings.Split(value, ",")
+	}
+	columns, err := export.SelectColumns(all, keys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid columns",
+			"details": err.Error(),
+			"columns": export.Keys(all),
+		})
+		return "", nil, false
+	}
+	return format, columns, true
+}
+
+// streamExport starts a download of name in format and writes the header row
+// followed by the rows produced by write. Once the response has started an error
+// can no longer be reported to the client, so it is logged and the download is cut short.
+func streamExport[T any](c *gin.Context, name, format string, columns []export.Column[T], write func(emit func(*T) error) error) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("2006-01-02"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	w, err := export.NewWriter(format, c.Writer, name)
+	if err == nil {
+		err = w.Write(export.Header(columns))
+	}
+	if err == nil {
+		err = write(func(record *T) error { return w.Write(export.Row(columns, record)) })
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		log.Printf("Export of %s failed: %v", name, err)
+		c.Abort()
+	}
+}
+
+// ExportBookings downloads the bookings matching the listing filters as CSV or XLSX.
+// Archived bookings are included unless the archived filter says otherwise.
+func (h *Handler) ExportBookings(c *gin.Context) {
+	filter, ok := parseBookingFilter(c)
+	if !ok {
+		return
+	}
+	if c.Query("archived") == "" {
+		filter.Archived = storage.ArchivedInclude
+	}
+	page, ok := parseBookingSort(c)
+	if !ok {
+		return
+	}
+	format, columns, ok := parseExport(c, bookingExportColumns)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+
+	streamExport(c, "bookings", format, columns, func(emit func(*models.Booking) error) error {
+		return h.store.Bookings.Batches(ctx, filter, page, exportBatchSize, func(bookings []models.Booking) error {
+			if err := h.store.AttachBalances(ctx, bookingPointers(bookings)...); err != nil {
+				return err
+			}
+			for i := range bookings {
+				if err := emit(&bookings[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// ExportCustomerPayments downloads the payments received from customers, optionally
+// within a range of payment dates, as CSV or XLSX
+func (h *Handler) ExportCustomerPayments(c *gin.Context) {
+	from, before, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+	format, columns, ok := parseExport(c, customerPaymentExportColumns)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	filter := storage.CustomerPaymentFilter{From: from, Before: before}
+
+	streamExport(c, "customer-payments", format, columns, func(emit func(*models.CustomerPayment) error) error {
+		return h.store.CustomerPayments.Batches(ctx, filter, exportBatchSize, func(payments []models.CustomerPayment) error {
+			for i := range payments {
+				if err := emit(&payments[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// ExportEmployeePayments downloads the payments made to employees, optionally for
+// one employee and within a range of payment dates, as CSV or XLSX
+func (h *Handler) ExportEmployeePayments(c *gin.Context) {
+	from, before, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+	format, columns, ok := parseExport(c, employeePaymentExportColumns)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	filter := storage.PaymentFilter{From: from, Before: before}
+
+	employees := map[primitive.ObjectID]models.Employee{}
+	if username := c.Query("username"); username != "" {
+		employee, err := h.store.Employees.FindByUsername(ctx, username)
+		if err != nil {
+			if err == storage.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			}
+			return
+		}
+		employees[employee.ID] = *employee
+		filter.EmployeeID = employee.ID
+	} else {
+		all, err := h.store.Employees.List(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve employees", "details": err.Error()})
+			return
+		}
+		// Payments to employees in the trash are kept, so they are exported under their names too
+		deleted, err := h.store.Employees.ListDeleted(ctx, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve employees", "details": err.Error()})
+			return
+		}
+		for _, employee := range append(all, deleted...) {
+			employees[employee.ID] = employee
+		}
+	}
+
+	streamExport(c, "employee-payments", format, columns, func(emit func(*employeePaymentRow) error) error {
+		return h.store.Payments.Batches(ctx, filter, exportBatchSize, func(payments []models.Payment) error {
+			for i := range payments {
+				row := employeePaymentRow{payment: &payments[i], employee: employees[payments[i].EmployeeID]}
+				if err := emit(&row); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/modernband/booking/internal/jobs"
+	"github.com/modernband/booking/internal/notify"
+	"github.com/modernband/booking/internal/sms"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// Handler serves the API using the injected storage backend, SMS sender, notifier
+// and job runner
+type Handler struct {
+	store    *storage.Store
+	sms      sms.Sender
+	notifier *notify.Notifier
+	jobs     *jobs.Runner
+}
+
+// NewHandler creates a Handler. A nil sender falls back to logging messages. The
+// notifier only queues notifications; they are delivered by its Run loop. The
+// runner is used to manage and run jobs on demand; scheduled runs happen in its
+// Run loop.
+func NewHandler(store *storage.Store, sender sms.Sender, notifier *notify.Notifier, runner *jobs.Runner) *Handler {
+	if sender == nil {
+		sender = sms.LogSender{}
+	}
+	return &Handler{store: store, sms: sender, notifier: notifier, jobs: runner}
+}
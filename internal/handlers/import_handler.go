@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/importer"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// maxImportSize is the largest CSV file accepted for import
+const maxImportSize = 10 << 20
+
+// importFile returns the uploaded CSV, sent either as the "file" field of a
+// multipart form or as the raw request body. It writes a 400 response and returns
+// false if there is none.
+func importFile(c *gin.Context) (io.ReadCloser, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		return c.Request.Body, true
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		if tooLarge(err) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File too large", "details": err.Error()})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing file", "details": err.Error()})
+		}
+		return nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file", "details": err.Error()})
+		return nil, false
+	}
+	return file, true
+}
+
+// tooLarge reports whether err was caused by a file over maxImportSize
+func tooLarge(err error) bool {
+	var maxBytes *http.MaxBytesError
+	return errors.As(err, &maxBytes)
+}
+
+// runImport imports the uploaded CSV with run and responds with the report. With
+// dryRun=true every row is validated but nothing is inserted.
+func (h *Handler) runImport(c *gin.Context, run func(context.Context, *storage.Store, io.Reader, importer.Options) (*importer.Report, error)) {
+	file, ok := importFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	opts := importer.Options{
+		DryRun: c.Query("dryRun") == "true",
+		By:     currentClaims(c).Username,
+	}
+	report, err := run(context.Background(), h.store, file, opts)
+	if err != nil {
+		switch {
+		case tooLarge(err):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File too large", "details": err.Error()})
+		case errors.Is(err, importer.ErrInvalidFile):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import file", "details": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Import failed", "details": err.Error(), "report": report})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ImportBookings imports historical bookings from a CSV file
+func (h *Handler) ImportBookings(c *gin.Context) {
+	h.runImport(c, importer.ImportBookings)
+}
+
+// ImportEmployees imports employees, with their initial passwords, from a CSV file
+func (h *Handler) ImportEmployees(c *gin.Context) {
+	h.runImport(c, importer.ImportEmployees)
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// inventoryItemNames describes the inventory item codes in customer-facing messages
+var inventoryItemNames = map[string]string{
+	models.ItemLights:     "lights",
+	models.ItemDhols:      "dhols",
+	models.ItemGhodaBaggi: "ghoda baggis",
+	models.ItemGhodi:      "ghodis",
+}
+
+// inventoryCode normalizes the :code parameter of the inventory routes, writing a
+// response and returning false if it names no inventory item
+func inventoryCode(c *gin.Context) (string, bool) {
+	code := strings.ToLower(strings.TrimSpace(c.Param("code")))
+	if _, ok := inventoryItemNames[code]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown inventory item. Use lights, dhols, ghoda_baggi or ghodi"})
+		return "", false
+	}
+	return code, true
+}
+
+// loadStock returns the units available per tracked inventory item
+func (h *Handler) loadStock(ctx context.Context) (map[string]int, error) {
+	items, err := h.store.Inventory.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stock := make(map[string]int, len(items))
+	for i := range items {
+		stock[items[i].Code] = items[i].Available()
+	}
+	return stock, nil
+}
+
+// checkStock loads the stock and checks that the equipment requested by booking is
+// left on its date, ignoring what previous (the stored version of an edited booking)
+// reserved. It lets handlers fail early with the item that ran out; the repository
+// still enforces stock atomically. It writes an error response and returns false if
+// the stock is insufficient.
+func (h *Handler) checkStock(ctx context.Context, c *gin.Context, booking, previous *models.Booking) (map[string]int, bool) {
+	stock, err := h.loadStock(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return nil, false
+	}
+	if len(stock) == 0 {
+		return stock, true
+	}
+
+	reservations, err := h.store.Bookings.ReservedEquipment(ctx, booking.EventDate, booking.EventDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return nil, false
+	}
+	reserved := map[string]int{}
+	for _, res := range reservations {
+		reserved[res.Item] += res.Quantity
+	}
+	day := booking.EventDate.Format("2006-01-02")
+	if previous != nil && previous.CurrentStatus() != models.BookingStatusCancelled &&
+		previous.EventDate.UTC().Format("2006-01-02") == day {
+		for item, quantity := range previous.Equipment() {
+			reserved[item] -= quantity
+		}
+	}
+
+	for item, quantity := range booking.Equipment() {
+		available, tracked := stock[item]
+		if !tracked {
+			continue
+		}
+		if remaining := available - reserved[item]; quantity > remaining {
+			if remaining < 0 {
+				remaining = 0
+			}
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     fmt.Sprintf("Only %d %s are available on %s", remaining, inventoryItemNames[item], day),
+				"item":      item,
+				"remaining": remaining,
+			})
+			return nil, false
+		}
+	}
+	return stock, true
+}
+
+// GetInventory lists the equipment the band owns
+func (h *Handler) GetInventory(c *gin.Context) {
+	items, err := h.store.Inventory.List(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve inventory", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"count": len(items),
+	})
+}
+
+// PutInventoryItem sets the stock and maintenance status of an inventory item,
+// starting to track it if it is not tracked yet
+func (h *Handler) PutInventoryItem(c *gin.Context) {
+	code, ok := inventoryCode(c)
+	if !ok {
+		return
+	}
+
+	var request models.PutInventoryItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	item := models.InventoryItem{
+		Code:            code,
+		Name:            request.Name,
+		QuantityOwned:   request.QuantityOwned,
+		InMaintenance:   request.InMaintenance,
+		MaintenanceNote: request.MaintenanceNote,
+		UpdatedAt:       time.Now(),
+	}
+	if claims := currentClaims(c); claims != nil {
+		item.UpdatedBy = claims.Username
+	}
+
+	if err := h.store.Inventory.Put(context.Background(), &item); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save inventory item", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Inventory item saved successfully",
+		"item":    item,
+	})
+}
+
+// DeleteInventoryItem stops tracking an inventory item so it no longer limits bookings
+func (h *Handler) DeleteInventoryItem(c *gin.Context) {
+	code, ok := inventoryCode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Inventory.Delete(context.Background(), code); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Inventory item not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete inventory item", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Inventory item deleted successfully",
+	})
+}
+
+// GetInventoryAvailability reports the stock of every tracked item left on each day in a date range
+func (h *Handler) GetInventoryAvailability(c *gin.Context) {
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing from date. Use YYYY-MM-DD"})
+		return
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing to date. Use YYYY-MM-DD"})
+		return
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+	if to.Sub(from) >= maxAvailabilityDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Date range is too long. Request at most 366 days"})
+		return
+	}
+
+	ctx := context.Background()
+
+	stock, err := h.loadStock(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve inventory", "details": err.Error()})
+		return
+	}
+
+	reservations, err := h.store.Bookings.ReservedEquipment(ctx, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve bookings", "details": err.Error()})
+		return
+	}
+	reservedByDay := map[string]map[string]int{}
+	for _, res := range reservations {
+		if reservedByDay[res.Day] == nil {
+			reservedByDay[res.Day] = map[string]int{}
+		}
+		reservedByDay[res.Day][res.Item] += res.Quantity
+	}
+
+	days := []models.DayInventory{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := date.Format("2006-01-02")
+		items := make(map[string]models.ItemAvailability, len(stock))
+		for item, available := range stock {
+			reserved := reservedByDay[day][item]
+			items[item] = models.ItemAvailability{
+				Available: available,
+				Reserved:  reserved,
+				Remaining: available - reserved,
+			}
+		}
+		days = append(days, models.DayInventory{Date: day, Items: items})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from": from.Format("2006-01-02"),
+		"to":   to.Format("2006-01-02"),
+		"days": days,
+	})
+}
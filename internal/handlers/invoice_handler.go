@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/invoice"
+	"github.com/modernband/booking/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sendPDF responds with a rendered PDF as a download named filename
+func sendPDF(c *gin.Context, filename string, pdf *bytes.Buffer) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/pdf", pdf.Bytes())
+}
+
+// bookingWithPayments looks up the booking named by the :id parameter with its
+// balances and payments, writing the error response if either cannot be read
+func (h *Handler) bookingWithPayments(ctx context.Context, c *gin.Context) (*models.Booking, []models.CustomerPayment, bool) {
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return nil, nil, false
+	}
+	payments, err := h.store.CustomerPayments.ListByBooking(ctx, booking.BookingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payments", "details": err.Error()})
+		return nil, nil, false
+	}
+	if err := h.store.AttachBalances(ctx, booking); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balance", "details": err.Error()})
+		return nil, nil, false
+	}
+	return booking, payments, true
+}
+
+// GetBookingInvoice downloads the invoice of a booking as a PDF
+func (h *Handler) GetBookingInvoice(c *gin.Context) {
+	ctx := context.Background()
+
+	booking, payments, ok := h.bookingWithPayments(ctx, c)
+	if !ok {
+		return
+	}
+
+	var pdf bytes.Buffer
+	if err := invoice.WriteInvoice(&pdf, invoice.LetterheadFromEnv(), booking, payments, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate invoice", "details": err.Error()})
+		return
+	}
+	sendPDF(c, "invoice-"+booking.BookingID+".pdf", &pdf)
+}
+
+// GetPaymentReceipt downloads the receipt for a payment received against a booking as a PDF
+func (h *Handler) GetPaymentReceipt(c *gin.Context) {
+	paymentID, err := primitive.ObjectIDFromHex(c.Param("paymentID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID format"})
+		return
+	}
+
+	ctx := context.Background()
+
+	booking, payments, ok := h.bookingWithPayments(ctx, c)
+	if !ok {
+		return
+	}
+	var payment *models.CustomerPayment
+	for i := range payments {
+		if payments[i].ID == paymentID {
+			payment = &payments[i]
+		}
+	}
+	if payment == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		return
+	}
+
+	var pdf bytes.Buffer
+	if err := invoice.WriteReceipt(&pdf, invoice.LetterheadFromEnv(), booking, payments, payment); err != nil {
+		if err == invoice.ErrVoided {
+			c.JSON(http.StatusConflict, gin.H{"error": "Payment is voided"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate receipt", "details": err.Error()})
+		}
+		return
+	}
+	sendPDF(c, "receipt-"+booking.BookingID+"-"+paymentID.Hex()+".pdf", &pdf)
+}
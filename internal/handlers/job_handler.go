@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/jobs"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+const (
+	jobRunsDefaultLimit = 20
+	jobRunsMaxLimit     = 200
+)
+
+// jobView is a background job as shown to admins: its stored state alongside
+// what the job does and its default schedule
+type jobView struct {
+	models.JobState
+	Description     string `json:"description"`
+	DefaultSchedule string `json:"defaultSchedule"`
+}
+
+// findJob returns the job named in the path with its state, writing a response
+// and returning false if it cannot
+func (h *Handler) findJob(c *gin.Context) (*jobView, bool) {
+	job, ok := h.jobs.Job(c.Param("name"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return nil, false
+	}
+	state, err := h.store.Jobs.Find(context.Background(), job.Name)
+	if err == storage.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job", "details": err.Error()})
+		return nil, false
+	}
+	return &jobView{JobState: *state, Description: job.Description, DefaultSchedule: job.Schedule}, true
+}
+
+// ListJobs lists the background jobs with their schedules, locks and last runs
+func (h *Handler) ListJobs(c *gin.Context) {
+	states, err := h.store.Jobs.List(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve jobs", "details": err.Error()})
+		return
+	}
+
+	views := []jobView{}
+	for _, state := range states {
+		// Jobs no longer run by this version of the service are left out
+		if job, ok := h.jobs.Job(state.Name); ok {
+			views = append(views, jobView{JobState: state, Description: job.Description, DefaultSchedule: job.Schedule})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  views,
+		"count": len(views),
+	})
+}
+
+// UpdateJob changes a job's schedule or pauses and resumes it. Omitted fields
+// keep their current values.
+func (h *Handler) UpdateJob(c *gin.Context) {
+	var request struct {
+		Schedule *string `json:"schedule"`
+		Enabled  *bool   `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	view, ok := h.findJob(c)
+	if !ok {
+		return
+	}
+	schedule, enabled := view.Schedule, view.Enabled
+	if request.Schedule != nil {
+		schedule = *request.Schedule
+	}
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+	if _, err := jobs.ParseSchedule(schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule", "details": err.Error()})
+		return
+	}
+
+	var by string
+	if claims := currentClaims(c); claims != nil {
+		by = claims.Username
+	}
+	state, err := h.jobs.Update(context.Background(), view.Name, schedule, enabled, by)
+	if err == jobs.ErrUnknownJob {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job", "details": err.Error()})
+		return
+	}
+
+	view.JobState = *state
+	c.JSON(http.StatusOK, view)
+}
+
+// RunJob runs a job immediately and responds with the finished run. A job that
+// fails still answers 200; the run's status and error say what happened.
+func (h *Handler) RunJob(c *gin.Context) {
+	var by string
+	if claims := currentClaims(c); claims != nil {
+		by = claims.Username
+	}
+
+	run, err := h.jobs.Trigger(c.Request.Context(), c.Param("name"), by)
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, run)
+	case jobs.ErrUnknownJob:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+	case jobs.ErrJobRunning:
+		c.JSON(http.StatusConflict, gin.H{"error": "Job is already running"})
+	case jobs.ErrStopped:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "The service is shutting down"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to run job", "details": err.Error()})
+	}
+}
+
+// ListJobRuns lists a job's run history, newest first
+func (h *Handler) ListJobRuns(c *gin.Context) {
+	job, ok := h.jobs.Job(c.Param("name"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	limit := jobRunsDefaultLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > jobRunsMaxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be between 1 and 200"})
+			return
+		}
+		limit = n
+	}
+
+	runs, err := h.store.Jobs.ListRuns(context.Background(), job.Name, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job runs", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"runs":  runs,
+		"count": len(runs),
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/auth"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// claimsKey is the gin context key under which validated token claims are stored
+const claimsKey = "claims"
+
+// AuthRequired validates the bearer token on the request and stores its claims in the context
+func (h *Handler) AuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.authenticate(c) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission allows the request through only if the authenticated user's role
+// grants the permission. It must run after AuthRequired.
+func RequirePermission(perm auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requirePermission(c, perm) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// SelfOrPermission allows users accessing their own :username resource, or users whose
+// role grants the permission. It must run after AuthRequired.
+func SelfOrPermission(perm auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := currentClaims(c)
+		if claims != nil && claims.Username == c.Param("username") {
+			c.Next()
+			return
+		}
+		if !requirePermission(c, perm) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// PermissionOrBootstrap requires the permission, except while no admin user exists yet
+// so the first admin account can be created. Requests let through unauthenticated
+// must create it with AdminRepository.CreateFirst, as several may race.
+func (h *Handler) PermissionOrBootstrap(perm auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		count, err := h.store.Admins.Count(context.Background())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+
+		if count > 0 && (!h.authenticate(c) || !requirePermission(c, perm)) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate parses the bearer token and stores its claims, aborting the request on failure.
+// The account is reloaded so deleted users are refused and role changes apply at once.
+func (h *Handler) authenticate(c *gin.Context) bool {
+	header := c.GetHeader("Authorization")
+	tokenString, found := strings.CutPrefix(header, "Bearer ")
+	if !found || tokenString == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization token is required"})
+		return false
+	}
+
+	claims, err := auth.ParseToken(tokenString)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		return false
+	}
+
+	role, err := h.currentRole(context.Background(), claims)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "The account no longer exists"})
+		} else {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return false
+	}
+	claims.Role = role
+
+	c.Set(claimsKey, claims)
+	return true
+}
+
+// currentRole returns the stored role of the account a token was issued to,
+// or ErrNotFound if the account was deleted or replaced since
+func (h *Handler) currentRole(ctx context.Context, claims *auth.Claims) (auth.Role, error) {
+	if claims.Role == auth.RoleCrew {
+		employee, err := h.store.Employees.FindByUsername(ctx, claims.Username)
+		if err != nil {
+			return "", err
+		}
+		if employee.ID.Hex() != claims.Subject {
+			return "", storage.ErrNotFound
+		}
+		return auth.RoleCrew, nil
+	}
+
+	admin, err := h.store.Admins.FindByUsername(ctx, claims.Username)
+	if err != nil {
+		return "", err
+	}
+	if admin.ID.Hex() != claims.Subject {
+		return "", storage.ErrNotFound
+	}
+	return auth.AdminRole(admin.Role), nil
+}
+
+// requirePermission aborts the request unless the stored claims grant the permission
+func requirePermission(c *gin.Context, perm auth.Permission) bool {
+	claims := currentClaims(c)
+	if claims == nil || !claims.Role.Can(perm) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permission denied", "permission": perm})
+		return false
+	}
+	return true
+}
+
+// currentClaims returns the token claims stored by AuthRequired, or nil if absent
+func currentClaims(c *gin.Context) *auth.Claims {
+	value, exists := c.Get(claimsKey)
+	if !exists {
+		return nil
+	}
+	claims, _ := value.(*auth.Claims)
+	return claims
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/notify"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	notificationsDefaultLimit = 50
+	notificationsMaxLimit     = 500
+)
+
+// notifyCustomer queues a notification of a booking event to the booking's
+// customer. Failures are logged; they do not fail the request that caused the event.
+func (h *Handler) notifyCustomer(ctx context.Context, event string, booking *models.Booking, data notify.Data) {
+	to := notify.Recipient{Phone: booking.Phone, Email: booking.Email}
+	if err := h.notifier.Notify(ctx, event, to, data); err != nil {
+		log.Printf("Failed to queue %s notification for booking %s: %v", event, booking.BookingID, err)
+	}
+}
+
+// ListNotifications lists the notification outbox, newest first, optionally
+// filtered by status and booking
+func (h *Handler) ListNotifications(c *gin.Context) {
+	filter := storage.NotificationFilter{
+		Status:    c.Query("status"),
+		BookingID: c.Query("bookingId"),
+	}
+	if filter.Status != "" && !models.IsNotificationStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use pending, sent or failed"})
+		return
+	}
+
+	limit := notificationsDefaultLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > notificationsMaxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be between 1 and 500"})
+			return
+		}
+		limit = n
+	}
+
+	notifications, err := h.store.Notifications.List(context.Background(), filter, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve notifications", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"count":         len(notifications),
+	})
+}
+
+// RetryNotification queues a failed notification to be sent again
+func (h *Handler) RetryNotification(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID format"})
+		return
+	}
+
+	if err := h.store.Notifications.Retry(context.Background(), id, time.Now()); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		case storage.ErrConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": "Only failed notifications can be retried"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry notification", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification queued for retry"})
+}
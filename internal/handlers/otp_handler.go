@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	otpLength          = 6
+	otpTTL             = 5 * time.Minute  // How long a sent code can be used
+	otpResendCooldown  = 60 * time.Second // Minimum gap between two codes to the same phone
+	otpMaxAttempts     = 5                // Wrong guesses allowed per code
+	otpLimitWindow     = time.Hour        // Rolling window of the per-phone limits below
+	otpMaxSends        = 5                // Codes sent per phone per window
+	otpMaxFailures     = 10               // Wrong guesses per phone per window, across codes
+	verificationWindow = 30 * time.Minute // How long a verified phone may be used to book
+)
+
+// generateOTP creates a random numeric code using crypto/rand
+func generateOTP() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < otpLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", otpLength, n), nil
+}
+
+// otpRetryAfter returns the seconds until fewer than limit of the events logged
+// at times, oldest first, remain in the rolling window
+func otpRetryAfter(times []time.Time, limit int, now time.Time) int {
+	if len(times) < limit {
+		return 0
+	}
+	return int(times[len(times)-limit].Add(otpLimitWindow).Sub(now).Seconds()) + 1
+}
+
+// SendOTP generates a new OTP for a phone number and sends it by SMS
+func (h *Handler) SendOTP(c *gin.Context) {
+	var request models.SendOTPRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	phone := models.NormalizePhone(request.ContactNumber)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	// Enforce resend cooldown
+	existing, err := h.store.OTPs.FindByPhone(ctx, phone)
+	if err != nil && err != storage.ErrNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err == nil {
+		if wait := existing.LastSentAt.Add(otpResendCooldown).Sub(now); wait > 0 {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":      "Please wait before requesting another OTP",
+				"retryAfter": int(wait.Seconds()) + 1,
+			})
+			return
+		}
+	}
+
+	// Cap the codes sent to the phone per rolling window, however often the cooldown passes
+	sent := &models.OTPEvent{Phone: phone, Kind: models.OTPEventSent, At: now, ExpiresAt: now.Add(otpLimitWindow)}
+	logged, err := h.store.OTPs.LogEvent(ctx, sent, now.Add(-otpLimitWindow), otpMaxSends)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if !logged {
+		times, err := h.store.OTPs.EventsSince(ctx, phone, models.OTPEventSent, now.Add(-otpLimitWindow))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":      "Too many OTPs requested for this phone. Please try again later",
+			"retryAfter": otpRetryAfter(times, otpMaxSends, now),
+		})
+		return
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate OTP"})
+		return
+	}
+
+	codeHash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash OTP"})
+		return
+	}
+
+	// Replace any previous code; a new code also clears an earlier verification
+	if err := h.store.OTPs.Issue(ctx, phone, string(codeHash), now, now.Add(otpTTL)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store OTP", "details": err.Error()})
+		return
+	}
+
+	message := fmt.Sprintf("Your Modern Band verification code is %s. It expires in %d minutes.", code, int(otpTTL.Minutes()))
+	if err := h.sms.Send(ctx, phone, message); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send OTP", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "OTP sent successfully",
+		"expiresIn": int(otpTTL.Seconds()),
+	})
+}
+
+// VerifyOTP checks a submitted OTP and marks the phone number as verified
+func (h *Handler) VerifyOTP(c *gin.Context) {
+	var request models.VerifyOTPRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	phone := models.NormalizePhone(request.ContactNumber)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	// Lock the phone out after too many wrong guesses in the rolling window, so
+	// requesting new codes does not reset the limit
+	failures, err := h.store.OTPs.EventsSince(ctx, phone, models.OTPEventFailed, now.Add(-otpLimitWindow))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if len(failures) >= otpMaxFailures {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":      "Too many wrong OTPs for this phone. Please try again later",
+			"retryAfter": otpRetryAfter(failures, otpMaxFailures, now),
+		})
+		return
+	}
+
+	// Count the attempt before comparing so concurrent guesses cannot exceed the limit
+	record, err := h.store.OTPs.RecordAttempt(ctx, phone, now, otpMaxAttempts)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "OTP expired, already used or too many attempts. Please request a new OTP"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(record.CodeHash), []byte(request.OTP)) != nil {
+		failed := &models.OTPEvent{Phone: phone, Kind: models.OTPEventFailed, At: now, ExpiresAt: now.Add(otpLimitWindow)}
+		if _, err := h.store.OTPs.LogEvent(ctx, failed, now.Add(-otpLimitWindow), 0); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":             "Invalid OTP",
+			"attemptsRemaining": min(otpMaxAttempts-record.Attempts, otpMaxFailures-len(failures)-1),
+		})
+		return
+	}
+
+	// Mark verified and keep the record for the booking window
+	if err := h.store.OTPs.MarkVerified(ctx, phone, now, now.Add(verificationWindow)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify OTP", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Phone number verified successfully",
+		"validFor": int(verificationWindow.Seconds()),
+		"contact":  phone,
+		"verified": true,
+	})
+}
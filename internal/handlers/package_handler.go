@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/pricing"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// parseOptionalDate parses a YYYY-MM-DD date, returning nil for an empty string
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// packageFromRequest validates a package request and converts it to a Package
+func packageFromRequest(c *gin.Context) (*models.Package, bool) {
+	var request models.PackageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return nil, false
+	}
+
+	activeFrom, err := parseOptionalDate(request.ActiveFrom)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activeFrom date format. Use YYYY-MM-DD"})
+		return nil, false
+	}
+	activeTo, err := parseOptionalDate(request.ActiveTo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activeTo date format. Use YYYY-MM-DD"})
+		return nil, false
+	}
+	if activeFrom != nil && activeTo != nil && activeTo.Before(*activeFrom) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "activeTo must not be before activeFrom"})
+		return nil, false
+	}
+
+	return &models.Package{
+		Code:        request.Code,
+		Name:        request.Name,
+		Description: request.Description,
+		Musicians:   request.Musicians,
+		Lights:      request.Lights,
+		Dhols:       request.Dhols,
+		Hours:       request.Hours,
+		Price:       request.Price,
+		ActiveFrom:  activeFrom,
+		ActiveTo:    activeTo,
+	}, true
+}
+
+// GetPackages lists the packages that can be booked on a date (default today)
+func (h *Handler) GetPackages(c *gin.Context) {
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+		date = parsed
+	}
+
+	packages, err := h.store.Packages.List(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve packages", "details": err.Error()})
+		return
+	}
+
+	available := []models.Package{}
+	for i := range packages {
+		if pricing.IsAvailable(&packages[i], date) {
+			available = append(available, packages[i])
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"packages": available,
+		"count":    len(available),
+	})
+}
+
+// GetAllPackages lists every package in the catalog, including inactive ones
+func (h *Handler) GetAllPackages(c *gin.Context) {
+	packages, err := h.store.Packages.List(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve packages", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"packages": packages,
+		"count":    len(packages),
+	})
+}
+
+// CreatePackage adds a package to the catalog
+func (h *Handler) CreatePackage(c *gin.Context) {
+	pkg, ok := packageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	pkg.CreatedAt = now
+	pkg.UpdatedAt = now
+
+	if err := h.store.Packages.Create(context.Background(), pkg); err != nil {
+		if err == storage.ErrDuplicate {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Package code already exists"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create package", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Package created successfully",
+		"package": pkg,
+	})
+}
+
+// UpdatePackage replaces the details of a catalog package
+func (h *Handler) UpdatePackage(c *gin.Context) {
+	pkg, ok := packageFromRequest(c)
+	if !ok {
+		return
+	}
+	if pkg.Code != c.Param("code") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Package code cannot be changed"})
+		return
+	}
+
+	pkg.UpdatedAt = time.Now()
+
+	if err := h.store.Packages.Update(context.Background(), pkg); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update package", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Package updated successfully",
+	})
+}
+
+// DeletePackage removes a package from the catalog. Existing bookings keep their package type and quote.
+func (h *Handler) DeletePackage(c *gin.Context) {
+	code := c.Param("code")
+
+	if err := h.store.Packages.Delete(context.Background(), code); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete package", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Package deleted successfully",
+	})
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// employeeBalance reconciles what an employee is owed, their opening balance plus
+// the wages earned at completed events, with the sum of their payment records
+func employeeBalance(employee *models.Employee, earned, paidFromPayments float64) models.EmployeeBalance {
+	balance := models.EmployeeBalance{
+		OpeningBalance:   employee.TotalAmountToBePaid,
+		EarnedFromEvents: earned,
+		AmountOwed:       employee.TotalAmountToBePaid + earned,
+		PaidInAdvance:    employee.TotalAmountPaidInAdvance,
+		PaidFromPayments: paidFromPayments,
+		PaidToDate:       employee.TotalAmountPaidInAdvance + paidFromPayments,
+	}
+	balance.RemainingDue = math.Max(0, balance.AmountOwed-balance.PaidToDate)
+	balance.Overpaid = math.Max(0, balance.PaidToDate-balance.AmountOwed)
+	return balance
+}
+
+// parsePeriod reads the optional from and to (inclusive) query dates and returns
+// the period as an inclusive start and exclusive end. It writes a 400 response
+// and returns false if either date is invalid.
+func parsePeriod(c *gin.Context) (from, before time.Time, ok bool) {
+	if value := c.Query("from"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format. Use YYYY-MM-DD"})
+			return from, before, false
+		}
+		from = date
+	}
+	if value := c.Query("to"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format. Use YYYY-MM-DD"})
+			return from, before, false
+		}
+		before = date.AddDate(0, 0, 1) // The to date is inclusive
+	}
+	if !from.IsZero() && !before.IsZero() && !before.After(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return from, before, false
+	}
+	return from, before, true
+}
+
+// GetPayrollSummary lists every employee's earnings and payments within an optional
+// date range and their balance as of the end of the range
+func (h *Handler) GetPayrollSummary(c *gin.Context) {
+	from, before, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+
+	employees, err := h.store.Employees.List(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve employees", "details": err.Error()})
+		return
+	}
+
+	paidInPeriod, err := h.store.Payments.TotalsByEmployee(ctx, storage.PaymentFilter{From: from, Before: before})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to total payments", "details": err.Error()})
+		return
+	}
+	paidToDate, err := h.store.Payments.TotalsByEmployee(ctx, storage.PaymentFilter{Before: before})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to total payments", "details": err.Error()})
+		return
+	}
+	earnedInPeriod, err := h.store.Earnings.TotalsByEmployee(ctx, storage.EarningFilter{From: from, Before: before})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to total earnings", "details": err.Error()})
+		return
+	}
+	earnedToDate, err := h.store.Earnings.TotalsByEmployee(ctx, storage.EarningFilter{Before: before})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to total earnings", "details": err.Error()})
+		return
+	}
+
+	entries := []models.PayrollEntry{}
+	var totalEarned, totalPaid, totalDue, totalOverpaid float64
+	for i := range employees {
+		emp := &employees[i]
+		entry := models.PayrollEntry{
+			EmployeeID:       emp.ID,
+			Username:         emp.Username,
+			Name:             emp.Name,
+			EarnedInPeriod:   earnedInPeriod[emp.ID].Amount,
+			EventsInPeriod:   earnedInPeriod[emp.ID].Count,
+			PaidInPeriod:     paidInPeriod[emp.ID].Amount,
+			PaymentsInPeriod: paidInPeriod[emp.ID].Count,
+			Balance:          employeeBalance(emp, earnedToDate[emp.ID].Amount, paidToDate[emp.ID].Amount),
+		}
+		totalEarned += entry.EarnedInPeriod
+		totalPaid += entry.PaidInPeriod
+		totalDue += entry.Balance.RemainingDue
+		totalOverpaid += entry.Balance.Overpaid
+		entries = append(entries, entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":      c.Query("from"),
+		"to":        c.Query("to"),
+		"employees": entries,
+		"count":     len(entries),
+		"totals": gin.H{
+			"earnedInPeriod": totalEarned,
+			"paidInPeriod":   totalPaid,
+			"remainingDue":   totalDue,
+			"overpaid":       totalOverpaid,
+		},
+	})
+}
+
+// GetEmployeeStatement itemizes the wages an employee earned and the payments they
+// received within an optional date range, with a running balance
+func (h *Handler) GetEmployeeStatement(c *gin.Context) {
+	from, before, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+
+	employee, err := h.store.Employees.FindByUsername(ctx, c.Param("username"))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	earnings, err := h.store.Earnings.List(ctx, storage.EarningFilter{EmployeeID: employee.ID, Before: before})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve earnings", "details": err.Error()})
+		return
+	}
+	payments, err := h.store.Payments.ListByEmployee(ctx, employee.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payments", "details": err.Error()})
+		return
+	}
+
+	// Everything before the period is carried into the opening balance
+	opening := employee.TotalAmountToBePaid - employee.TotalAmountPaidInAdvance
+	entries := []models.StatementEntry{}
+	for _, e := range earnings {
+		if e.EventDate.Before(from) {
+			opening += e.Amount
+			continue
+		}
+		entries = append(entries, models.StatementEntry{
+			Date:      e.EventDate,
+			Type:      models.StatementEarning,
+			BookingID: e.BookingID,
+			Role:      e.Role,
+			Earned:    e.Amount,
+		})
+	}
+	for _, p := range payments {
+		if !before.IsZero() && !p.Date.Before(before) {
+			continue
+		}
+		if p.Date.Before(from) {
+			opening -= p.AmountPaid
+			continue
+		}
+		entries = append(entries, models.StatementEntry{
+			Date: p.Date,
+			Type: models.StatementPayment,
+			Paid: p.AmountPaid,
+		})
+	}
+
+	// Oldest first; on the same day wages are earned before they are paid
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].Type == models.StatementEarning && entries[j].Type == models.StatementPayment
+	})
+
+	balance := opening
+	var totalEarned, totalPaid float64
+	for i := range entries {
+		balance += entries[i].Earned - entries[i].Paid
+		entries[i].Balance = balance
+		totalEarned += entries[i].Earned
+		totalPaid += entries[i].Paid
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"username":       employee.Username,
+		"name":           employee.Name,
+		"from":           c.Query("from"),
+		"to":             c.Query("to"),
+		"openingBalance": opening,
+		"entries":        entries,
+		"totals": gin.H{
+			"earned": totalEarned,
+			"paid":   totalPaid,
+		},
+		"closingBalance": balance,
+	})
+}
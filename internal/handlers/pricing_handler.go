@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/pricing"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// errPricingNotConfigured is returned when no rate card has been published yet
+var errPricingNotConfigured = errors.New("pricing is not configured")
+
+// quoteBooking validates the booking's package against the catalog and prices it
+// against the current rate card
+func (h *Handler) quoteBooking(ctx context.Context, booking *models.Booking) (*models.Quote, error) {
+	pkg, err := h.store.Packages.FindByCode(ctx, booking.PackageType)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, fmt.Errorf("%w: %q", pricing.ErrUnknownPackage, booking.PackageType)
+		}
+		return nil, err
+	}
+
+	card, err := h.store.RateCards.Current(ctx)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, errPricingNotConfigured
+		}
+		return nil, err
+	}
+	return pricing.CalculateQuote(card, pkg, booking)
+}
+
+// respondQuoteError writes the response for an error returned by quoteBooking
+func respondQuoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pricing.ErrUnknownPackage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown package type", "details": err.Error()})
+	case errors.Is(err, pricing.ErrPackageUnavailable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Package is not available on the event date", "details": err.Error()})
+	case errors.Is(err, pricing.ErrNegativeQuantity):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quantities and amounts must not be negative"})
+	case errors.Is(err, errPricingNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Pricing is not configured yet"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to price booking", "details": err.Error()})
+	}
+}
+
+// GetQuote returns the itemized price for booking details without creating a booking
+func (h *Handler) GetQuote(c *gin.Context) {
+	var booking models.Booking
+	if err := c.ShouldBindJSON(&booking); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	quote, err := h.quoteBooking(context.Background(), &booking)
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quote": quote})
+}
+
+// GetRateCard retrieves the rate card currently in effect
+func (h *Handler) GetRateCard(c *gin.Context) {
+	card, err := h.store.RateCards.Current(context.Background())
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No rate card has been published"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rateCard": card})
+}
+
+// UpdateRateCard publishes a new rate card that replaces the current one
+func (h *Handler) UpdateRateCard(c *gin.Context) {
+	var request models.UpdateRateCardRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// City surcharges are matched case-insensitively
+	citySurcharges := make(map[string]int, len(request.CitySurcharges))
+	for city, amount := range request.CitySurcharges {
+		citySurcharges[strings.ToLower(strings.TrimSpace(city))] = amount
+	}
+
+	// Parse date surcharge ranges
+	dateSurcharges := []models.DateSurcharge{}
+	for _, ds := range request.DateSurcharges {
+		from, err := time.Parse("2006-01-02", ds.From)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format for surcharge " + ds.Name + ". Use YYYY-MM-DD"})
+			return
+		}
+		to, err := time.Parse("2006-01-02", ds.To)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format for surcharge " + ds.Name + ". Use YYYY-MM-DD"})
+			return
+		}
+		if to.Before(from) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Surcharge " + ds.Name + " ends before it starts"})
+			return
+		}
+		dateSurcharges = append(dateSurcharges, models.DateSurcharge{
+			Name:    ds.Name,
+			From:    from,
+			To:      to,
+			Percent: ds.Percent,
+			Amount:  ds.Amount,
+		})
+	}
+
+	card := models.RateCard{
+		LightPrice:       request.LightPrice,
+		DholPrice:        request.DholPrice,
+		GhodaBaggiPrice:  request.GhodaBaggiPrice,
+		GhodiPrice:       request.GhodiPrice,
+		FlowerCanonPrice: request.FlowerCanonPrice,
+		DoliPrice:        request.DoliPrice,
+		CitySurcharges:   citySurcharges,
+		DateSurcharges:   dateSurcharges,
+		CreatedAt:        time.Now(),
+	}
+	if claims := currentClaims(c); claims != nil {
+		card.CreatedBy = claims.Username
+	}
+
+	if err := h.store.RateCards.Create(context.Background(), &card); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save rate card", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Rate card published successfully",
+		"rateCard": card,
+	})
+}
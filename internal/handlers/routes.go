@@ -5,16 +5,17 @@ import (
 	"os"
 
 	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/auth"
 )
 
 // SetupRoutes configures all the routes for the API
-func SetupRoutes(router *gin.Engine) {
+func SetupRoutes(router *gin.Engine, h *Handler) {
 	// Enable CORS
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -27,41 +28,136 @@ func SetupRoutes(router *gin.Engine) {
 	// API routes
 	api := router.Group("/api")
 	{
-		// Booking endpoints
-		api.POST("/book", CreateBooking)
-		api.GET("/booking", GetBooking)
-		api.GET("/bookings", GetAllBookings)
-		api.DELETE("/bookings/past", DeletePastBookings)
-		api.DELETE("/bookings/:id", DeleteBooking)
-
-		// Authentication endpoint
-		api.POST("/login", Login)
-		api.POST("/signin", AdminLogin)
+		// OTP endpoints
+		api.POST("/send-otp", h.SendOTP)
+		api.POST("/verify-otp", h.VerifyOTP)
 
-		// Employee endpoints
-		api.POST("/employees", CreateEmployee)
-		api.GET("/employees", GetAllEmployees)
-		api.DELETE("/employees/:username", DeleteEmployee)
-		api.POST("/employees/:username/payments", AddPayment)
-		api.DELETE("/employees/:username/payments/:paymentID", DeletePayment)
-		api.GET("/employees/:username", GetEmployeeDetails)
+		// Public booking endpoints
+		api.POST("/book", h.CreateBooking)
+		api.POST("/quote", h.GetQuote)
+		api.GET("/packages", h.GetPackages)
+		api.GET("/availability", h.GetAvailability)
+		api.GET("/booking", h.GetBooking)
 
-		// Admin endpoints
-		api.POST("/admin", CreateAdminUser)
+		// Authentication endpoint
+		api.POST("/login", h.Login)
+		api.POST("/signin", h.AdminLogin)
 
 		// Health check
 		api.GET("/health", func(c *gin.Context) {
-			port := "8081"  // Default port
+			port := "8081" // Default port
 			if p := os.Getenv("PORT"); p != "" {
 				port = p
 			}
 			c.JSON(http.StatusOK, gin.H{
-				"status": "UP",
+				"status":  "UP",
 				"message": "Server is running on port " + port,
 			})
 		})
 	}
 
+	// Authenticated routes, each guarded by the permission it needs
+	protected := api.Group("", h.AuthRequired())
+	{
+		// Booking endpoints
+		protected.GET("/bookings", RequirePermission(auth.PermBookingsRead), h.GetAllBookings)
+		protected.PATCH("/bookings/:id", RequirePermission(auth.PermBookingsWrite), h.UpdateBooking)
+		protected.GET("/bookings/:id/history", RequirePermission(auth.PermBookingsRead), h.GetBookingHistory)
+		protected.POST("/bookings/:id/confirm", RequirePermission(auth.PermBookingsWrite), h.ConfirmBooking)
+		protected.POST("/bookings/:id/complete", RequirePermission(auth.PermBookingsWrite), h.CompleteBooking)
+		protected.POST("/bookings/:id/cancel", RequirePermission(auth.PermBookingsWrite), h.CancelBooking)
+		protected.GET("/bookings/:id/invoice.pdf", RequirePermission(auth.PermBookingsRead), h.GetBookingInvoice)
+		protected.GET("/bookings/:id/payments", RequirePermission(auth.PermBookingsRead), h.GetBookingPayments)
+		protected.POST("/bookings/:id/payments", RequirePermission(auth.PermPaymentsWrite), h.RecordBookingPayment)
+		protected.GET("/bookings/:id/payments/:paymentID/receipt.pdf", RequirePermission(auth.PermBookingsRead), h.GetPaymentReceipt)
+		protected.POST("/bookings/:id/payments/:paymentID/void", RequirePermission(auth.PermPaymentsDelete), h.VoidBookingPayment)
+		protected.GET("/bookings/:id/tax-invoices", RequirePermission(auth.PermBookingsRead), h.GetBookingTaxInvoices)
+		protected.POST("/bookings/:id/tax-invoice", RequirePermission(auth.PermPaymentsWrite), h.IssueTaxInvoice)
+		protected.POST("/bookings/:id/credit-note", RequirePermission(auth.PermPaymentsWrite), h.IssueCreditNote)
+		protected.GET("/bookings/:id/crew", RequirePermission(auth.PermBookingsRead), h.GetBookingCrew)
+		protected.POST("/bookings/:id/crew", RequirePermission(auth.PermBookingsWrite), h.AssignCrew)
+		protected.DELETE("/bookings/:id/crew/:assignmentID", RequirePermission(auth.PermBookingsWrite), h.RemoveCrew)
+		protected.DELETE("/bookings/:id", RequirePermission(auth.PermBookingsDelete), h.DeleteBooking)
+		protected.POST("/bookings/:id/restore", RequirePermission(auth.PermBookingsDelete), h.RestoreBooking)
+
+		// Employee endpoints
+		protected.POST("/employees", RequirePermission(auth.PermEmployeesWrite), h.CreateEmployee)
+		protected.GET("/employees", RequirePermission(auth.PermEmployeesRead), h.GetAllEmployees)
+		protected.GET("/employees/:username", SelfOrPermission(auth.PermEmployeesRead), h.GetEmployeeDetails)
+		protected.GET("/employees/:username/assignments", SelfOrPermission(auth.PermEmployeesRead), h.GetEmployeeAssignments)
+		protected.GET("/employees/:username/statement", SelfOrPermission(auth.PermEmployeesRead), h.GetEmployeeStatement)
+		protected.DELETE("/employees/:username", RequirePermission(auth.PermEmployeesDelete), h.DeleteEmployee)
+		protected.POST("/employees/:username/restore", RequirePermission(auth.PermEmployeesDelete), h.RestoreEmployee)
+		protected.POST("/employees/:username/payments", RequirePermission(auth.PermPaymentsWrite), h.AddPayment)
+		protected.DELETE("/employees/:username/payments/:paymentID", RequirePermission(auth.PermPaymentsDelete), h.DeletePayment)
+		protected.POST("/employees/:username/payments/:paymentID/restore", RequirePermission(auth.PermPaymentsDelete), h.RestorePayment)
+
+		// Trash endpoints; deleted records can be restored until they are purged
+		protected.GET("/trash/bookings", RequirePermission(auth.PermBookingsDelete), h.ListDeletedBookings)
+		protected.GET("/trash/employees", RequirePermission(auth.PermEmployeesDelete), h.ListDeletedEmployees)
+		protected.GET("/trash/payments", RequirePermission(auth.PermPaymentsDelete), h.ListDeletedPayments)
+
+		// Payroll endpoints
+		protected.GET("/payroll/summary", RequirePermission(auth.PermEmployeesRead), h.GetPayrollSummary)
+		protected.GET("/wage-rates", RequirePermission(auth.PermEmployeesRead), h.GetWageRates)
+		protected.PUT("/wage-rates", RequirePermission(auth.PermWagesManage), h.UpdateWageRates)
+
+		// Pricing endpoints
+		protected.GET("/rate-card", RequirePermission(auth.PermPricingRead), h.GetRateCard)
+		protected.PUT("/rate-card", RequirePermission(auth.PermPricingManage), h.UpdateRateCard)
+		protected.GET("/packages/all", RequirePermission(auth.PermPricingRead), h.GetAllPackages)
+		protected.POST("/packages", RequirePermission(auth.PermPricingManage), h.CreatePackage)
+		protected.PUT("/packages/:code", RequirePermission(auth.PermPricingManage), h.UpdatePackage)
+		protected.DELETE("/packages/:code", RequirePermission(auth.PermPricingManage), h.DeletePackage)
+
+		// GST endpoints
+		protected.GET("/tax-settings", RequirePermission(auth.PermPricingRead), h.GetTaxSettings)
+		protected.PUT("/tax-settings", RequirePermission(auth.PermPricingManage), h.UpdateTaxSettings)
+		protected.GET("/tax-invoices", RequirePermission(auth.PermBookingsRead), h.ListTaxInvoices)
+		protected.GET("/tax-invoices/:number", RequirePermission(auth.PermBookingsRead), h.GetTaxInvoice)
+		protected.GET("/tax-invoices/:number/pdf", RequirePermission(auth.PermBookingsRead), h.GetTaxInvoicePDF)
+
+		// Capacity endpoints
+		protected.GET("/capacity", RequirePermission(auth.PermBookingsRead), h.GetCapacity)
+		protected.PUT("/capacity", RequirePermission(auth.PermCapacityManage), h.UpdateCapacity)
+
+		// Inventory endpoints
+		protected.GET("/inventory", RequirePermission(auth.PermBookingsRead), h.GetInventory)
+		protected.GET("/inventory/availability", RequirePermission(auth.PermBookingsRead), h.GetInventoryAvailability)
+		protected.PUT("/inventory/:code", RequirePermission(auth.PermInventoryManage), h.PutInventoryItem)
+		protected.DELETE("/inventory/:code", RequirePermission(auth.PermInventoryManage), h.DeleteInventoryItem)
+
+		// Export endpoints, streamed as CSV or XLSX
+		protected.GET("/export/bookings", RequirePermission(auth.PermBookingsRead), h.ExportBookings)
+		protected.GET("/export/customer-payments", RequirePermission(auth.PermBookingsRead), h.ExportCustomerPayments)
+		protected.GET("/export/employee-payments", RequirePermission(auth.PermEmployeesRead), h.ExportEmployeePayments)
+
+		// Import endpoints for historical records in CSV files
+		protected.POST("/import/bookings", RequirePermission(auth.PermBookingsWrite), h.ImportBookings)
+		protected.POST("/import/employees", RequirePermission(auth.PermEmployeesWrite), h.ImportEmployees)
+
+		// Notification outbox endpoints
+		protected.GET("/notifications", RequirePermission(auth.PermBookingsRead), h.ListNotifications)
+		protected.POST("/notifications/:id/retry", RequirePermission(auth.PermBookingsWrite), h.RetryNotification)
+
+		// Search endpoint; employees are included for callers who may read them
+		protected.GET("/search", RequirePermission(auth.PermBookingsRead), h.Search)
+
+		// Background job endpoints
+		protected.GET("/jobs", RequirePermission(auth.PermAdminsManage), h.ListJobs)
+		protected.PUT("/jobs/:name", RequirePermission(auth.PermAdminsManage), h.UpdateJob)
+		protected.POST("/jobs/:name/run", RequirePermission(auth.PermAdminsManage), h.RunJob)
+		protected.GET("/jobs/:name/runs", RequirePermission(auth.PermAdminsManage), h.ListJobRuns)
+
+		// Admin user endpoints
+		protected.GET("/admins", RequirePermission(auth.PermAdminsManage), h.GetAllAdminUsers)
+		protected.PUT("/admins/:username", RequirePermission(auth.PermAdminsManage), h.UpdateAdminUser)
+		protected.DELETE("/admins/:username", RequirePermission(auth.PermAdminsManage), h.DeleteAdminUser)
+	}
+
+	// Bootstrap: the first admin can be created without a token
+	api.POST("/admin", h.PermissionOrBootstrap(auth.PermAdminsManage), h.CreateAdminUser)
+
 	// Default route for unknown paths
 	router.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
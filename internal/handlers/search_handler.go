@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/auth"
+	"github.com/modernband/booking/internal/models"
+)
+
+const (
+	searchMinQuery     = 2   // Shortest query accepted, in characters
+	searchMaxQuery     = 100 // Longest query accepted, in characters
+	searchDefaultLimit = 10  // Results per kind when no limit is given
+	searchMaxLimit     = 50
+)
+
+// Search finds bookings and employees matching a free-text query. Names, venues,
+// cities, emails and phone numbers match on fragments, best match first.
+// Employees are only searched for callers allowed to read them.
+func (h *Handler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if n := utf8.RuneCountInString(query); n < searchMinQuery || n > searchMaxQuery {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query must be between 2 and 100 characters"})
+		return
+	}
+
+	limit := searchDefaultLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > searchMaxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be between 1 and 50"})
+			return
+		}
+		limit = n
+	}
+
+	ctx := context.Background()
+
+	bookings, err := h.store.Bookings.Search(ctx, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search bookings", "details": err.Error()})
+		return
+	}
+	if err := h.store.AttachBalances(ctx, bookingPointers(bookings)...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking balances", "details": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"query":    query,
+		"bookings": bookings,
+	}
+
+	if claims := currentClaims(c); claims != nil && claims.Role.Can(auth.PermEmployeesRead) {
+		employees, err := h.store.Employees.Search(ctx, query, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search employees", "details": err.Error()})
+			return
+		}
+		matches := []models.EmployeeResponse{}
+		for _, emp := range employees {
+			matches = append(matches, models.EmployeeResponse{
+				ID:                       emp.ID,
+				Name:                     emp.Name,
+				MobileNumber:             emp.MobileNumber,
+				Email:                    emp.Email,
+				Address:                  emp.Address,
+				IsEmployee:               emp.IsEmployee,
+				TotalAmountToBePaid:      emp.TotalAmountToBePaid,
+				TotalAmountPaidInAdvance: emp.TotalAmountPaidInAdvance,
+				Username:                 emp.Username,
+			})
+		}
+		response["employees"] = matches
+	}
+
+	c.JSON(http.StatusOK, response)
+}
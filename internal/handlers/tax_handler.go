@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/invoice"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"github.com/modernband/booking/internal/tax"
+)
+
+const taxSettingsKey = "tax"
+
+// financialYearPattern matches financial years such as 2026-27
+var financialYearPattern = regexp.MustCompile(`^\d{4}-\d{2}$`)
+
+// loadTaxSettings returns the GST settings, empty when none are saved
+func (h *Handler) loadTaxSettings(ctx context.Context) (*models.TaxSettings, error) {
+	var settings models.TaxSettings
+	if err := h.store.Settings.Get(ctx, taxSettingsKey, &settings); err != nil && err != storage.ErrNotFound {
+		return nil, err
+	}
+	if settings.CityStates == nil {
+		settings.CityStates = map[string]string{}
+	}
+	return &settings, nil
+}
+
+// GetTaxSettings retrieves the GST settings
+func (h *Handler) GetTaxSettings(c *gin.Context) {
+	settings, err := h.loadTaxSettings(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"taxSettings": settings})
+}
+
+// UpdateTaxSettings replaces the GST settings. Invoices already issued keep the
+// settings they were issued with.
+func (h *Handler) UpdateTaxSettings(c *gin.Context) {
+	var request models.UpdateTaxSettingsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Cities are looked up case-insensitively
+	cityStates := make(map[string]string, len(request.CityStates))
+	for city, state := range request.CityStates {
+		cityStates[strings.ToLower(strings.TrimSpace(city))] = strings.TrimSpace(state)
+	}
+
+	settings := models.TaxSettings{
+		GSTIN:      request.GSTIN,
+		LegalName:  request.LegalName,
+		Address:    request.Address,
+		State:      strings.TrimSpace(request.State),
+		SAC:        request.SAC,
+		Rate:       request.Rate,
+		CityStates: cityStates,
+		UpdatedAt:  time.Now(),
+	}
+	if claims := currentClaims(c); claims != nil {
+		settings.UpdatedBy = claims.Username
+	}
+
+	if err := h.store.Settings.Put(context.Background(), taxSettingsKey, &settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tax settings", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Tax settings updated successfully",
+		"taxSettings": settings,
+	})
+}
+
+// findTaxInvoice returns the booking's document of the given kind, or nil if it has none
+func (h *Handler) findTaxInvoice(ctx context.Context, bookingID, kind string) (*models.TaxInvoice, error) {
+	docs, err := h.store.TaxInvoices.ListByBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		if docs[i].Kind == kind {
+			return &docs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// IssueTaxInvoice issues the GST invoice of a confirmed or completed booking,
+// numbered next in the current financial year
+func (h *Handler) IssueTaxInvoice(c *gin.Context) {
+	// The customer's GSTIN is optional, so an empty body is allowed
+	var request models.IssueTaxInvoiceRequest
+	if err := c.ShouldBindJSON(&request); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+	if status := booking.CurrentStatus(); status != models.BookingStatusConfirmed && status != models.BookingStatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only confirmed or completed bookings can be invoiced"})
+		return
+	}
+
+	settings, err := h.loadTaxSettings(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	lh := invoice.LetterheadFromEnv()
+	supplier := models.TaxParty{Phone: lh.Phone, Email: lh.Email}
+	doc, err := tax.NewInvoice(settings, supplier, booking, currentClaims(c).Username, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, tax.ErrNotConfigured):
+			c.JSON(http.StatusConflict, gin.H{"error": "Tax settings are not configured"})
+		case errors.Is(err, tax.ErrUnknownCity):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No state is configured for the booking's city", "details": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prepare invoice", "details": err.Error()})
+		}
+		return
+	}
+	doc.Recipient.GSTIN = request.GSTIN
+
+	if err := h.store.TaxInvoices.Issue(ctx, doc); err != nil {
+		if err == storage.ErrDuplicate {
+			c.JSON(http.StatusConflict, gin.H{"error": "The booking already has a tax invoice"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue invoice", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Tax invoice issued successfully",
+		"taxInvoice": doc,
+	})
+}
+
+// issueCreditNote reverses a booking's tax invoice. It returns a nil note without
+// an error if the booking was never invoiced.
+func (h *Handler) issueCreditNote(ctx context.Context, bookingID, reason, by string) (*models.TaxInvoice, error) {
+	original, err := h.findTaxInvoice(ctx, bookingID, models.TaxDocumentInvoice)
+	if err != nil || original == nil {
+		return nil, err
+	}
+	note := tax.NewCreditNote(original, reason, by, time.Now())
+	if err := h.store.TaxInvoices.Issue(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// creditCancelledBooking issues a credit note for a booking that was just cancelled,
+// if it had been invoiced. Failures are logged; the note can be issued again with
+// IssueCreditNote.
+func (h *Handler) creditCancelledBooking(ctx context.Context, booking *models.Booking) {
+	change := booking.StatusHistory[len(booking.StatusHistory)-1]
+	note, err := h.issueCreditNote(ctx, booking.BookingID, change.Reason, change.By)
+	if err != nil {
+		log.Printf("Failed to issue credit note for booking %s: %v", booking.BookingID, err)
+		return
+	}
+	if note != nil {
+		log.Printf("Issued credit note %s for cancelled booking %s", note.Number, booking.BookingID)
+	}
+}
+
+// IssueCreditNote reverses the GST invoice of a cancelled booking
+func (h *Handler) IssueCreditNote(c *gin.Context) {
+	// The reason is optional, so an empty body is allowed
+	var request models.IssueCreditNoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+	if booking.CurrentStatus() != models.BookingStatusCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only cancelled bookings can be credited"})
+		return
+	}
+
+	note, err := h.issueCreditNote(ctx, booking.BookingID, request.Reason, currentClaims(c).Username)
+	if err != nil {
+		if err == storage.ErrDuplicate {
+			c.JSON(http.StatusConflict, gin.H{"error": "The booking's invoice has already been credited"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue credit note", "details": err.Error()})
+		}
+		return
+	}
+	if note == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The booking has no tax invoice"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Credit note issued successfully",
+		"taxInvoice": note,
+	})
+}
+
+// GetBookingTaxInvoices lists the GST invoice and credit note of a booking
+func (h *Handler) GetBookingTaxInvoices(c *gin.Context) {
+	ctx := context.Background()
+
+	booking, ok := h.findBooking(ctx, c)
+	if !ok {
+		return
+	}
+	docs, err := h.store.TaxInvoices.ListByBooking(ctx, booking.BookingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tax invoices", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bookingId": booking.BookingID, "taxInvoices": docs})
+}
+
+// ListTaxInvoices lists the GST invoices and credit notes of a financial year
+// (the current one by default), optionally of one kind, in the order they were issued
+func (h *Handler) ListTaxInvoices(c *gin.Context) {
+	financialYear := c.DefaultQuery("financialYear", tax.FinancialYear(time.Now()))
+	if !financialYearPattern.MatchString(financialYear) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid financialYear. Use YYYY-YY, e.g. 2026-27"})
+		return
+	}
+	kind := c.Query("kind")
+	if kind != "" && !models.IsTaxDocumentKind(kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid kind. Use invoice or credit_note"})
+		return
+	}
+
+	docs, err := h.store.TaxInvoices.List(context.Background(), financialYear, kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tax invoices", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"financialYear": financialYear,
+		"taxInvoices":   docs,
+		"count":         len(docs),
+	})
+}
+
+// findTaxInvoiceByNumber looks up the document named by the :number parameter,
+// writing the error response if it cannot be found
+func (h *Handler) findTaxInvoiceByNumber(c *gin.Context) (*models.TaxInvoice, bool) {
+	doc, err := h.store.TaxInvoices.FindByNumber(context.Background(), c.Param("number"))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tax invoice not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return nil, false
+	}
+	return doc, true
+}
+
+// GetTaxInvoice retrieves a GST invoice or credit note by number
+func (h *Handler) GetTaxInvoice(c *gin.Context) {
+	doc, ok := h.findTaxInvoiceByNumber(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"taxInvoice": doc})
+}
+
+// GetTaxInvoicePDF downloads a GST invoice or credit note as a PDF
+func (h *Handler) GetTaxInvoicePDF(c *gin.Context) {
+	doc, ok := h.findTaxInvoiceByNumber(c)
+	if !ok {
+		return
+	}
+
+	var pdf bytes.Buffer
+	if err := invoice.WriteTaxInvoice(&pdf, doc); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate invoice", "details": err.Error()})
+		return
+	}
+	sendPDF(c, doc.Number+".pdf", &pdf)
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	trashDefaultLimit = 50
+	trashMaxLimit     = 500
+)
+
+// trashedPayment is a payment in the trash with the username of its employee
+type trashedPayment struct {
+	models.Payment
+	Username string `json:"username"`
+}
+
+// trashLimit parses the limit query parameter of the trash listings, writing a
+// response and returning false if it is invalid
+func trashLimit(c *gin.Context) (int, bool) {
+	value := c.Query("limit")
+	if value == "" {
+		return trashDefaultLimit, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > trashMaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be between 1 and 500"})
+		return 0, false
+	}
+	return limit, true
+}
+
+// ListDeletedBookings lists the bookings in the trash, most recently deleted first
+func (h *Handler) ListDeletedBookings(c *gin.Context) {
+	limit, ok := trashLimit(c)
+	if !ok {
+		return
+	}
+
+	bookings, err := h.store.Bookings.ListDeleted(context.Background(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve deleted bookings", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bookings": bookings,
+		"count":    len(bookings),
+	})
+}
+
+// RestoreBooking takes a booking out of the trash. Upcoming bookings must still
+// fit the day's capacity and stock. Crew who were since trashed or assigned
+// elsewhere at the same time are dropped.
+func (h *Handler) RestoreBooking(c *gin.Context) {
+	ctx := context.Background()
+	bookingID := c.Param("id")
+
+	booking, err := h.store.Bookings.FindDeleted(ctx, bookingID)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found in the trash"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	// Past and cancelled bookings take up no capacity
+	var limits *storage.CapacityLimits
+	if booking.CurrentStatus() != models.BookingStatusCancelled && !booking.EventDate.Before(startOfToday()) {
+		capacity, err := h.loadCapacity(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		dayLimits, open := capacityLimits(capacity, booking.EventDate.UTC().Format("2006-01-02"))
+		if !open {
+			c.JSON(http.StatusConflict, gin.H{"error": "The band is not taking bookings on the booking's date"})
+			return
+		}
+		stock, ok := h.checkStock(ctx, c, booking, nil)
+		if !ok {
+			return
+		}
+		dayLimits.Stock = stock
+		limits = &dayLimits
+	}
+
+	if err := h.store.Bookings.Restore(ctx, bookingID, limits); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found in the trash"})
+		case storage.ErrCapacityExceeded:
+			c.JSON(http.StatusConflict, gin.H{"error": "The booking's date and time slot is fully booked"})
+		case storage.ErrInsufficientStock:
+			c.JSON(http.StatusConflict, gin.H{"error": "Not enough equipment is available on the booking's date"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore booking", "details": err.Error()})
+		}
+		return
+	}
+	log.Printf("Restored booking %s from the trash", bookingID)
+
+	booking.DeletedAt, booking.DeletedBy = nil, ""
+	if err := h.store.AttachBalances(ctx, booking); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute balance", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Booking restored successfully",
+		"booking": booking,
+	})
+}
+
+// ListDeletedEmployees lists the employees in the trash, most recently deleted first
+func (h *Handler) ListDeletedEmployees(c *gin.Context) {
+	limit, ok := trashLimit(c)
+	if !ok {
+		return
+	}
+
+	employees, err := h.store.Employees.ListDeleted(context.Background(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve deleted employees", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"employees": employees,
+		"count":     len(employees),
+	})
+}
+
+// RestoreEmployee takes an employee out of the trash with their payments and
+// earnings. Upcoming events they were taken off are not reassigned.
+func (h *Handler) RestoreEmployee(c *gin.Context) {
+	username := c.Param("username")
+
+	if err := h.store.Employees.Restore(context.Background(), username); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found in the trash"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore employee", "details": err.Error()})
+		}
+		return
+	}
+	log.Printf("Restored employee %s from the trash", username)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Employee restored successfully",
+	})
+}
+
+// ListDeletedPayments lists the employee payments in the trash, most recently
+// deleted first
+func (h *Handler) ListDeletedPayments(c *gin.Context) {
+	limit, ok := trashLimit(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	payments, err := h.store.Payments.ListDeleted(ctx, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve deleted payments", "details": err.Error()})
+		return
+	}
+
+	// The payments' employees may be in the trash themselves
+	employees, err := h.store.Employees.List(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve employees", "details": err.Error()})
+		return
+	}
+	deleted, err := h.store.Employees.ListDeleted(ctx, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve employees", "details": err.Error()})
+		return
+	}
+	usernames := map[primitive.ObjectID]string{}
+	for _, employee := range append(employees, deleted...) {
+		usernames[employee.ID] = employee.Username
+	}
+
+	trashed := make([]trashedPayment, len(payments))
+	for i, payment := range payments {
+		trashed[i] = trashedPayment{Payment: payment, Username: usernames[payment.EmployeeID]}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"payments": trashed,
+		"count":    len(trashed),
+	})
+}
+
+// RestorePayment takes an employee's payment out of the trash. An employee in
+// the trash must be restored first.
+func (h *Handler) RestorePayment(c *gin.Context) {
+	paymentID, err := primitive.ObjectIDFromHex(c.Param("paymentID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID format"})
+		return
+	}
+
+	ctx := context.Background()
+	employee, err := h.store.Employees.FindByUsername(ctx, c.Param("username"))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	if err := h.store.Payments.Restore(ctx, employee.ID, paymentID); err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found in the trash"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore payment", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Payment restored successfully",
+	})
+}
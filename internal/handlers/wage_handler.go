@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+const wageRatesSettingsKey = "wage_rates"
+
+// loadWageRates returns the wage rates, defaulting to no rates when none are saved
+func (h *Handler) loadWageRates(ctx context.Context) (*models.WageRates, error) {
+	var rates models.WageRates
+	if err := h.store.Settings.Get(ctx, wageRatesSettingsKey, &rates); err != nil && err != storage.ErrNotFound {
+		return nil, err
+	}
+	if rates.Rates == nil {
+		rates.Rates = map[string]float64{}
+	}
+	return &rates, nil
+}
+
+// GetWageRates retrieves the wage rates per crew role
+func (h *Handler) GetWageRates(c *gin.Context) {
+	rates, err := h.loadWageRates(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"wageRates": rates})
+}
+
+// UpdateWageRates replaces the wage rates per crew role. Existing assignments keep
+// the wage agreed when they were made.
+func (h *Handler) UpdateWageRates(c *gin.Context) {
+	var request models.UpdateWageRatesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	rates := models.WageRates{
+		Rates:     request.Rates,
+		UpdatedAt: time.Now(),
+	}
+	if claims := currentClaims(c); claims != nil {
+		rates.UpdatedBy = claims.Username
+	}
+
+	if err := h.store.Settings.Put(context.Background(), wageRatesSettingsKey, &rates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save wage rates", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Wage rates updated successfully",
+		"wageRates": rates,
+	})
+}
@@ -0,0 +1,290 @@
+// Package importer loads historical bookings and employees from CSV files. Every
+// row is checked with the rules the API applies to the same records; valid rows
+// are inserted in batches and invalid ones are reported by line number.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultBatchSize is the number of rows inserted at a time when Options.BatchSize is zero
+const DefaultBatchSize = 100
+
+// ErrInvalidFile is returned when a file cannot be read as CSV or its header
+// names an unknown column
+var ErrInvalidFile = errors.New("invalid import file")
+
+// Options control an import
+type Options struct {
+	DryRun    bool   // Validate every row without inserting any
+	By        string // Recorded as the author of imported bookings' status
+	BatchSize int
+}
+
+// RowError describes why a row was not imported
+type RowError struct {
+	Row   int    `json:"row"`             // Line number in the file; the header is line 1
+	Field string `json:"field,omitempty"` // Column of the invalid value, if any
+	Error string `json:"error"`
+}
+
+// Report summarizes an import
+type Report struct {
+	DryRun   bool       `json:"dryRun"`
+	Rows     int        `json:"rows"`     // Rows read, excluding the header and blank lines
+	Valid    int        `json:"valid"`    // Rows that passed validation
+	Imported int        `json:"imported"` // Rows inserted; always 0 for a dry run
+	Errors   []RowError `json:"errors"`
+}
+
+// batch collects valid records and inserts them when full
+type batch[T any] struct {
+	opts   Options
+	report *Report
+	lines  []int
+	items  []T
+	// insertMany inserts every item or none, returning storage.ErrDuplicate on a
+	// duplicate key; insert inserts a single item
+	insertMany func(context.Context, []T) error
+	insert     func(context.Context, *T) error
+	duplicate  string // Error reported for a row whose key was taken meanwhile
+}
+
+// add queues an item read from line, inserting the batch once it is full
+func (b *batch[T]) add(ctx context.Context, line int, item T) error {
+	b.report.Valid++
+	if b.opts.DryRun {
+		return nil
+	}
+	b.lines = append(b.lines, line)
+	b.items = append(b.items, item)
+	if len(b.items) < b.opts.BatchSize {
+		return nil
+	}
+	return b.flush(ctx)
+}
+
+// flush inserts the queued items. If another writer took one of their keys since
+// they were validated, the items are inserted one by one so that only the
+// conflicting rows are rejected.
+func (b *batch[T]) flush(ctx context.Context) error {
+	if len(b.items) == 0 {
+		return nil
+	}
+	defer func() { b.lines, b.items = b.lines[:0], b.items[:0] }()
+
+	err := b.insertMany(ctx, b.items)
+	if err == nil {
+		b.report.Imported += len(b.items)
+		return nil
+	}
+	if err != storage.ErrDuplicate {
+		return err
+	}
+	for i := range b.items {
+		switch err := b.insert(ctx, &b.items[i]); err {
+		case nil:
+			b.report.Imported++
+		case storage.ErrDuplicate:
+			b.report.Valid--
+			b.report.Errors = append(b.report.Errors, RowError{Row: b.lines[i], Error: b.duplicate})
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// newReport returns an empty report and opts with defaults applied
+func newReport(opts Options) (*Report, Options) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	return &Report{DryRun: opts.DryRun, Errors: []RowError{}}, opts
+}
+
+// ImportBookings imports the bookings in a CSV file whose columns are the fields
+// of models.ImportBookingRequest. Booking IDs must be unique across the file and
+// the store; rows without one are given a new ID. The report is returned even if
+// the import stops early with an error.
+func ImportBookings(ctx context.Context, store *storage.Store, r io.Reader, opts Options) (*Report, error) {
+	report, opts := newReport(opts)
+	bookings := &batch[models.Booking]{
+		opts:       opts,
+		report:     report,
+		insertMany: store.Bookings.CreateMany,
+		insert:     store.Bookings.Create,
+		duplicate:  "bookingId already exists",
+	}
+
+	now := time.Now()
+	seen := map[string]int{} // Booking IDs in the file and the line they are on
+	err := readRows(r, func(line int, row *models.ImportBookingRequest, errs []RowError) error {
+		report.Rows++
+		errs = append(errs, validate(line, row, errs)...)
+		if len(errs) == 0 && row.BookingID != "" {
+			if first, ok := seen[row.BookingID]; ok {
+				errs = append(errs, RowError{Row: line, Field: "bookingId", Error: fmt.Sprintf("duplicates line %d", first)})
+			} else if exists, err := store.Bookings.Exists(ctx, row.BookingID); err != nil {
+				return err
+			} else if exists {
+				errs = append(errs, RowError{Row: line, Field: "bookingId", Error: "bookingId already exists"})
+			}
+		}
+		if len(errs) > 0 {
+			report.Errors = append(report.Errors, errs...)
+			return nil
+		}
+
+		if row.BookingID == "" {
+			id, err := newBookingID(ctx, store, seen)
+			if err != nil {
+				return err
+			}
+			row.BookingID = id
+		}
+		seen[row.BookingID] = line
+		return bookings.add(ctx, line, newBooking(row, now, opts.By))
+	})
+	if err == nil {
+		err = bookings.flush(ctx)
+	}
+	return report, err
+}
+
+// newBookingID generates a booking ID that is neither stored nor used in the file
+func newBookingID(ctx context.Context, store *storage.Store, seen map[string]int) (string, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		id, err := models.GenerateBookingID()
+		if err != nil {
+			return "", err
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		exists, err := store.Bookings.Exists(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return id, nil
+		}
+	}
+	return "", errors.New("could not generate a unique booking ID")
+}
+
+// newBooking converts a validated row into a booking
+func newBooking(row *models.ImportBookingRequest, now time.Time, by string) models.Booking {
+	// Parsed without errors by validation
+	eventDate, _ := time.Parse("2006-01-02", row.EventDate)
+	createdAt := now
+	if row.CreatedAt != "" {
+		createdAt, _ = time.Parse("2006-01-02", row.CreatedAt)
+	}
+	status := row.Status
+	if status == "" {
+		status = models.BookingStatusConfirmed
+	}
+
+	booking := models.Booking{
+		BookingID:       row.BookingID,
+		Name:            row.Name,
+		Email:           row.Email,
+		Phone:           models.NormalizePhone(row.Phone),
+		AdditionalPhone: row.AdditionalPhone,
+		PackageType:     row.PackageType,
+		EventDate:       eventDate,
+		Venue:           row.Venue,
+		City:            row.City,
+		Customization:   row.Customization,
+		BandTime:        row.BandTime,
+		CustomTimeSlot:  row.CustomTimeSlot,
+		NumberOfPeople:  row.NumberOfPeople,
+		NumberOfLights:  row.NumberOfLights,
+		NumberOfDhols:   row.NumberOfDhols,
+		GhodaBaggi:      row.GhodaBaggi,
+		GhodiForBaraat:  row.GhodiForBaraat,
+		Fireworks:       row.Fireworks,
+		FireworksAmount: row.FireworksAmount,
+		FlowerCanon:     row.FlowerCanon,
+		DoliForVidai:    row.DoliForVidai,
+		Amount:          row.Amount,
+		AdvancePayment:  row.AdvancePayment,
+		Status:          status,
+		StatusHistory: []models.StatusChange{
+			{To: status, At: createdAt, By: by, Reason: "Imported"},
+		},
+		CreatedAt: createdAt,
+	}
+	booking.TimeSlot = booking.SlotName()
+	return booking
+}
+
+// ImportEmployees imports the employees in a CSV file whose columns are the fields
+// of models.CreateEmployeeRequest, including each employee's initial password.
+// Usernames must be unique across the file and the store. The report is returned
+// even if the import stops early with an error.
+func ImportEmployees(ctx context.Context, store *storage.Store, r io.Reader, opts Options) (*Report, error) {
+	report, opts := newReport(opts)
+	employees := &batch[models.Employee]{
+		opts:       opts,
+		report:     report,
+		insertMany: store.Employees.CreateMany,
+		insert:     store.Employees.Create,
+		duplicate:  "username already exists",
+	}
+
+	now := time.Now()
+	seen := map[string]int{} // Usernames in the file and the line they are on
+	err := readRows(r, func(line int, row *models.CreateEmployeeRequest, errs []RowError) error {
+		report.Rows++
+		errs = append(errs, validate(line, row, errs)...)
+		if len(errs) == 0 {
+			if first, ok := seen[row.Username]; ok {
+				errs = append(errs, RowError{Row: line, Field: "username", Error: fmt.Sprintf("duplicates line %d", first)})
+			} else if exists, err := store.Employees.Exists(ctx, row.Username); err != nil {
+				return err
+			} else if exists {
+				errs = append(errs, RowError{Row: line, Field: "username", Error: "username already exists"})
+			}
+		}
+		if len(errs) > 0 {
+			report.Errors = append(report.Errors, errs...)
+			return nil
+		}
+		seen[row.Username] = line
+		if opts.DryRun {
+			return employees.add(ctx, line, models.Employee{})
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(row.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		return employees.add(ctx, line, models.Employee{
+			Name:                     row.Name,
+			MobileNumber:             row.MobileNumber,
+			Email:                    row.Email,
+			Address:                  row.Address,
+			IsEmployee:               true,
+			TotalAmountToBePaid:      row.TotalAmountToBePaid,
+			TotalAmountPaidInAdvance: row.TotalAmountPaidInAdvance,
+			Username:                 row.Username,
+			Password:                 string(hashedPassword),
+			CreatedAt:                now,
+			UpdatedAt:                now,
+		})
+	})
+	if err == nil {
+		err = employees.flush(ctx)
+	}
+	return report, err
+}
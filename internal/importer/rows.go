@@ -0,0 +1,198 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// columns maps the CSV column names accepted for T, the json names of its
+// fields, to the field indexes
+func columns(t reflect.Type) map[string]int {
+	names := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			names[name] = i
+		}
+	}
+	return names
+}
+
+// readRows reads a CSV file whose header names fields of T by their json names
+// and calls fn with each row decoded into a T, along with the errors of values
+// that could not be converted. Blank lines are skipped. It returns an error
+// wrapping ErrInvalidFile if the file is not CSV or its header is unusable.
+func readRows[T any](r io.Reader, fn func(line int, row *T, errs []RowError) error) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	known := columns(t)
+	fields := make([]int, len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) // Spreadsheets may start the file with a byte order mark
+		index, ok := known[name]
+		if !ok {
+			return fmt.Errorf("%w: unknown column %q", ErrInvalidFile, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("%w: column %q appears twice", ErrInvalidFile, name)
+		}
+		seen[name] = true
+		fields[i] = index
+		header[i] = name
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+		var errs []RowError
+		if err != nil {
+			errs = append(errs, RowError{Row: line, Error: fmt.Sprintf("expected %d columns, found %d", len(header), len(record))})
+		}
+		if blank(record) {
+			continue
+		}
+
+		row := new(T)
+		if len(errs) == 0 {
+			value := reflect.ValueOf(row).Elem()
+			for i, cell := range record {
+				if err := setField(value.Field(fields[i]), strings.TrimSpace(cell)); err != nil {
+					errs = append(errs, RowError{Row: line, Field: header[i], Error: err.Error()})
+				}
+			}
+		}
+		if err := fn(line, row, errs); err != nil {
+			return err
+		}
+	}
+}
+
+// blank reports whether every cell of a record is empty
+func blank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// setField converts a cell to the type of field. An empty cell leaves the zero value.
+func setField(field reflect.Value, cell string) error {
+	if cell == "" {
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Int:
+		n, err := strconv.Atoi(cell)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		switch strings.ToLower(cell) {
+		case "true", "yes", "y", "1":
+			field.SetBool(true)
+		case "false", "no", "n", "0":
+		default:
+			return errors.New("must be yes or no")
+		}
+	default:
+		return fmt.Errorf("cannot import %s values", field.Kind())
+	}
+	return nil
+}
+
+// validate checks a row against its binding rules, the same ones the API applies
+// to request bodies, and returns an error per invalid field. Fields already in
+// known, whose values could not be converted, are skipped.
+func validate[T any](line int, row *T, known []RowError) []RowError {
+	for _, e := range known {
+		if e.Field == "" {
+			return nil // The row could not be decoded at all
+		}
+	}
+
+	err := binding.Validator.ValidateStruct(row)
+	if err == nil {
+		return nil
+	}
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []RowError{{Row: line, Error: err.Error()}}
+	}
+
+	t := reflect.TypeOf(row).Elem()
+	jsonName := func(structField string) string {
+		if f, ok := t.FieldByName(structField); ok {
+			return strings.Split(f.Tag.Get("json"), ",")[0]
+		}
+		return structField
+	}
+
+	var errs []RowError
+next:
+	for _, fe := range fieldErrors {
+		field := jsonName(fe.StructField())
+		for _, e := range known {
+			if e.Field == field {
+				continue next
+			}
+		}
+		var message string
+		switch fe.Tag() {
+		case "required":
+			message = "is required"
+		case "email":
+			message = "must be a valid email address"
+		case "datetime":
+			message = "must be a date in YYYY-MM-DD format"
+		case "gte":
+			message = "must be at least " + fe.Param()
+		case "ltefield":
+			message = "must not exceed " + jsonName(fe.Param())
+		case "oneof":
+			message = "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+		case "len":
+			message = "must be " + fe.Param() + " characters long"
+		case "alphanum", "uppercase":
+			message = "must contain only capital letters and digits"
+		default:
+			message = "failed the " + fe.Tag() + " rule"
+		}
+		errs = append(errs, RowError{Row: line, Field: field, Error: message})
+	}
+	return errs
+}
@@ -0,0 +1,272 @@
+// Package invoice renders booking invoices and payment receipts as PDF documents
+// on the band's letterhead.
+package invoice
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/modernband/booking/internal/models"
+)
+
+// Letterhead identifies the band at the top of every document
+type Letterhead struct {
+	Name    string
+	Address string
+	Phone   string
+	Email   string
+	GSTIN   string // Printed on tax invoices
+}
+
+// LetterheadFromEnv reads the letterhead from the BandName, BandAddress, BandPhone
+// and BandEmail environment variables
+func LetterheadFromEnv() Letterhead {
+	lh := Letterhead{
+		Name:    os.Getenv("BandName"),
+		Address: os.Getenv("BandAddress"),
+		Phone:   os.Getenv("BandPhone"),
+		Email:   os.Getenv("BandEmail"),
+	}
+	if lh.Name == "" {
+		lh.Name = "Modern Band"
+	}
+	return lh
+}
+
+// Page layout in millimetres
+const (
+	margin       = 15.0
+	contentWidth = 210 - 2*margin // A4
+	lineHeight   = 6.0
+)
+
+// dateLayout formats dates on documents
+const dateLayout = "02 Jan 2006"
+
+// document is a PDF being written on the letterhead. Text is converted from UTF-8
+// to the Windows-1252 encoding of the built-in fonts.
+type document struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+// newDocument starts a document whose first page shows the letterhead and title
+func newDocument(lh Letterhead, title string, created time.Time) *document {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	d := &document{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	pdf.SetTitle(title, true)
+	pdf.SetAuthor(lh.Name, true)
+	pdf.SetCreator(lh.Name, true)
+	pdf.SetCreationDate(created)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin+5)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-margin)
+		d.font("I", 8)
+		pdf.SetTextColor(128, 128, 128)
+		d.cell(contentWidth/2, 5, "This is a computer-generated document.", "L")
+		d.cell(contentWidth/2, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "R")
+	})
+	pdf.AddPage()
+
+	// Letterhead
+	d.font("B", 20)
+	d.line(lineHeight+3, lh.Name, "L")
+	d.font("", 9)
+	pdf.SetTextColor(90, 90, 90)
+	if lh.Address != "" {
+		d.line(4.5, lh.Address, "L")
+	}
+	var contact []string
+	if lh.Phone != "" {
+		contact = append(contact, "Phone: "+lh.Phone)
+	}
+	if lh.Email != "" {
+		contact = append(contact, "Email: "+lh.Email)
+	}
+	if lh.GSTIN != "" {
+		contact = append(contact, "GSTIN: "+lh.GSTIN)
+	}
+	if len(contact) > 0 {
+		d.line(4.5, strings.Join(contact, "   |   "), "L")
+	}
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(2)
+	d.rule()
+
+	d.font("B", 14)
+	d.line(lineHeight+4, title, "R")
+	pdf.Ln(2)
+	return d
+}
+
+// font sets the Helvetica style and size
+func (d *document) font(style string, size float64) {
+	d.pdf.SetFont("Helvetica", style, size)
+}
+
+// cell writes text in a cell of width w on the current line
+func (d *document) cell(w, h float64, text, align string) {
+	d.pdf.CellFormat(w, h, d.tr(text), "", 0, align, false, 0, "")
+}
+
+// line writes text across the page and moves to the next line
+func (d *document) line(h float64, text, align string) {
+	d.pdf.CellFormat(0, h, d.tr(text), "", 1, align, false, 0, "")
+}
+
+// rule draws a horizontal line across the page
+func (d *document) rule() {
+	y := d.pdf.GetY()
+	d.pdf.SetDrawColor(160, 160, 160)
+	d.pdf.Line(margin, y, margin+contentWidth, y)
+	d.pdf.Ln(2)
+}
+
+// field is a labelled value in a details block
+type field struct {
+	label, value string
+}
+
+// details writes two columns of labelled values side by side
+func (d *document) details(left, right []field) {
+	top := d.pdf.GetY()
+	column := func(x float64, fields []field) float64 {
+		d.pdf.SetY(top)
+		for _, f := range fields {
+			if f.value == "" {
+				continue
+			}
+			d.pdf.SetX(x)
+			d.font("B", 9)
+			d.cell(30, 5.5, f.label, "L")
+			d.font("", 9)
+			d.pdf.MultiCell(contentWidth/2-32, 5.5, d.tr(f.value), "", "L", false)
+		}
+		return d.pdf.GetY()
+	}
+	bottom := column(margin, left)
+	if y := column(margin+contentWidth/2, right); y > bottom {
+		bottom = y
+	}
+	d.pdf.SetY(bottom + 4)
+}
+
+// tableColumn is a column of a table with its width and alignment
+type tableColumn struct {
+	header string
+	width  float64
+	align  string
+}
+
+// table writes a table with a shaded header row
+func (d *document) table(columns []tableColumn, rows [][]string) {
+	d.font("B", 9)
+	d.pdf.SetFillColor(230, 230, 230)
+	for _, c := range columns {
+		d.pdf.CellFormat(c.width, 7, d.tr(c.header), "", 0, c.align, true, 0, "")
+	}
+	d.pdf.Ln(-1)
+
+	d.font("", 9)
+	d.pdf.SetDrawColor(210, 210, 210)
+	for _, row := range rows {
+		for i, c := range columns {
+			d.pdf.CellFormat(c.width, 7, d.tr(row[i]), "B", 0, c.align, false, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+	d.pdf.Ln(2)
+}
+
+// total writes a right-aligned labelled amount
+func (d *document) total(label, amount string, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	d.font(style, 10)
+	d.cell(contentWidth-40, lineHeight, label, "R")
+	d.pdf.CellFormat(40, lineHeight, d.tr(amount), "", 1, "R", false, 0, "")
+}
+
+// note writes a paragraph of small text
+func (d *document) note(text string) {
+	d.font("", 9)
+	d.pdf.MultiCell(0, 5, d.tr(text), "", "L", false)
+}
+
+// space leaves a gap between sections
+func (d *document) space() {
+	d.pdf.Ln(4)
+}
+
+// output writes the finished PDF
+func (d *document) output(w io.Writer) error {
+	return d.pdf.Output(w)
+}
+
+// FormatAmount formats whole rupees with Indian digit grouping, e.g. Rs. 1,25,000
+func FormatAmount(amount int) string {
+	if amount < 0 {
+		return "-Rs. " + groupDigits(strconv.Itoa(-amount))
+	}
+	return "Rs. " + groupDigits(strconv.Itoa(amount))
+}
+
+// formatRupees formats rupees and paise with Indian digit grouping, e.g. Rs. 1,05,932.20
+func formatRupees(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	paise := int64(math.Round(amount * 100))
+	return fmt.Sprintf("%sRs. %s.%02d", sign, groupDigits(strconv.FormatInt(paise/100, 10)), paise%100)
+}
+
+// groupDigits separates the thousands and then every two digits with commas
+func groupDigits(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var groups []string
+	for len(head) > 2 {
+		groups = append([]string{head[len(head)-2:]}, groups...)
+		head = head[:len(head)-2]
+	}
+	groups = append([]string{head}, groups...)
+	return strings.Join(groups, ",") + "," + tail
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// venue joins a booking's venue and city
+func venue(booking *models.Booking) string {
+	if booking.Venue == "" {
+		return booking.City
+	}
+	return booking.Venue + ", " + booking.City
+}
+
+// timeSlot describes when the band plays at an event
+func timeSlot(booking *models.Booking) string {
+	if booking.BandTime == "" || strings.EqualFold(booking.BandTime, "custom") {
+		return booking.CustomTimeSlot
+	}
+	return booking.BandTime
+}
@@ -0,0 +1,109 @@
+package invoice
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+)
+
+// itemColumns are the columns of the itemized charges on an invoice
+var itemColumns = []tableColumn{
+	{header: "Description", width: contentWidth - 85, align: "L"},
+	{header: "Qty", width: 15, align: "R"},
+	{header: "Rate", width: 35, align: "R"},
+	{header: "Amount", width: 35, align: "R"},
+}
+
+// paymentColumns are the columns of the payments listed on an invoice
+var paymentColumns = []tableColumn{
+	{header: "Date", width: 30, align: "L"},
+	{header: "Method", width: 25, align: "L"},
+	{header: "Reference", width: contentWidth - 90, align: "L"},
+	{header: "Amount", width: 35, align: "R"},
+}
+
+// WriteInvoice writes the invoice of a booking as a PDF: its package and add-ons
+// as priced, the payments received and the balance due. The booking's balances
+// must be attached; voided payments are left out.
+func WriteInvoice(w io.Writer, lh Letterhead, booking *models.Booking, payments []models.CustomerPayment, issued time.Time) error {
+	d := newDocument(lh, "INVOICE", issued)
+
+	d.details([]field{
+		{"Bill to", booking.Name},
+		{"Phone", booking.Phone},
+		{"Email", booking.Email},
+		{"Venue", venue(booking)},
+	}, []field{
+		{"Invoice no.", "INV-" + booking.BookingID},
+		{"Issued", issued.Format(dateLayout)},
+		{"Booking ID", booking.BookingID},
+		{"Event date", booking.EventDate.UTC().Format(dateLayout)},
+		{"Time", timeSlot(booking)},
+		{"Status", capitalize(booking.CurrentStatus())},
+	})
+
+	d.table(itemColumns, invoiceItems(booking))
+	d.total("Total", FormatAmount(booking.Amount), true)
+	d.space()
+
+	var rows [][]string
+	if booking.AdvancePayment > 0 {
+		rows = append(rows, []string{booking.CreatedAt.UTC().Format(dateLayout), "Advance", "", FormatAmount(booking.AdvancePayment)})
+	}
+	for _, p := range payments {
+		if p.VoidedAt != nil {
+			continue
+		}
+		rows = append(rows, []string{p.PaidAt.UTC().Format(dateLayout), strings.ToUpper(p.Method), p.Reference, FormatAmount(p.Amount)})
+	}
+	if len(rows) > 0 {
+		d.font("B", 11)
+		d.line(lineHeight+2, "Payments received", "L")
+		d.table(paymentColumns, rows)
+	}
+
+	d.total("Amount paid", FormatAmount(booking.AmountPaid), false)
+	if booking.BalanceDue < 0 {
+		d.total("Paid in excess", FormatAmount(-booking.BalanceDue), true)
+	} else {
+		d.total("Balance due", FormatAmount(booking.BalanceDue), true)
+	}
+
+	d.space()
+	if booking.CurrentStatus() == models.BookingStatusCancelled {
+		d.note("This booking has been cancelled; no further payment is due.")
+	} else if booking.BalanceDue > 0 {
+		d.note("Please pay the balance due on or before the event date, quoting booking ID " + booking.BookingID + ".")
+	}
+	d.note("Thank you for choosing " + lh.Name + ".")
+	return d.output(w)
+}
+
+// invoiceItems returns the rows of the itemized charges. Bookings priced before
+// quotes were stored show their amount as a single package line; any difference
+// between the quote and the amount charged is shown as an adjustment.
+func invoiceItems(booking *models.Booking) [][]string {
+	var rows [][]string
+	quoted := 0
+	if booking.Quote != nil {
+		for _, item := range booking.Quote.Items {
+			rows = append(rows, []string{
+				item.Description,
+				strconv.Itoa(item.Quantity),
+				FormatAmount(item.UnitPrice),
+				FormatAmount(item.Amount),
+			})
+			quoted += item.Amount
+		}
+	}
+	if len(rows) == 0 {
+		return [][]string{{"Package: " + booking.PackageType, "1", FormatAmount(booking.Amount), FormatAmount(booking.Amount)}}
+	}
+	if adjustment := booking.Amount - quoted; adjustment != 0 {
+		rows = append(rows, []string{"Adjustment", "1", FormatAmount(adjustment), FormatAmount(adjustment)})
+	}
+	return rows
+}
@@ -0,0 +1,63 @@
+package invoice
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/modernband/booking/internal/models"
+)
+
+// ErrVoided is returned when a receipt is requested for a voided payment
+var ErrVoided = errors.New("payment is voided")
+
+// WriteReceipt writes a PDF receipt for one of a booking's payments, showing the
+// amount received to date and the balance remaining after it. payments are all of
+// the booking's payments, oldest first, and must include payment.
+func WriteReceipt(w io.Writer, lh Letterhead, booking *models.Booking, payments []models.CustomerPayment, payment *models.CustomerPayment) error {
+	if payment.VoidedAt != nil {
+		return ErrVoided
+	}
+
+	// Received up to and including this payment, in ledger order
+	received := booking.AdvancePayment
+	for _, p := range payments {
+		if p.VoidedAt == nil {
+			received += p.Amount
+		}
+		if p.ID == payment.ID {
+			break
+		}
+	}
+
+	d := newDocument(lh, "PAYMENT RECEIPT", payment.CreatedAt)
+	d.details([]field{
+		{"Received from", booking.Name},
+		{"Phone", booking.Phone},
+		{"Venue", venue(booking)},
+		{"Event date", booking.EventDate.UTC().Format(dateLayout)},
+	}, []field{
+		{"Receipt no.", "RCT-" + payment.ID.Hex()},
+		{"Paid on", payment.PaidAt.UTC().Format(dateLayout)},
+		{"Booking ID", booking.BookingID},
+		{"Method", strings.ToUpper(payment.Method)},
+		{"Reference", payment.Reference},
+		{"Received by", payment.ReceivedBy},
+	})
+
+	d.font("B", 12)
+	d.line(lineHeight+4, "Amount received: "+FormatAmount(payment.Amount), "L")
+	d.space()
+
+	d.total("Booking total", FormatAmount(booking.Amount), false)
+	d.total("Received to date", FormatAmount(received), false)
+	if balance := booking.Amount - received; balance < 0 {
+		d.total("Paid in excess", FormatAmount(-balance), true)
+	} else {
+		d.total("Balance due", FormatAmount(balance), true)
+	}
+
+	d.space()
+	d.note("Thank you for your payment to " + lh.Name + ".")
+	return d.output(w)
+}
@@ -0,0 +1,87 @@
+package invoice
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/modernband/booking/internal/clock"
+	"github.com/modernband/booking/internal/models"
+)
+
+// taxLineColumns are the columns of the charges on a tax invoice or credit note
+var taxLineColumns = []tableColumn{
+	{header: "Description", width: contentWidth - 95, align: "L"},
+	{header: "Qty", width: 15, align: "R"},
+	{header: "Taxable value", width: 40, align: "R"},
+	{header: "Amount incl. GST", width: 40, align: "R"},
+}
+
+// WriteTaxInvoice writes an issued GST invoice or credit note as a PDF. Everything
+// shown comes from the stored document, so it renders the same every time.
+func WriteTaxInvoice(w io.Writer, doc *models.TaxInvoice) error {
+	title, numberLabel := "TAX INVOICE", "Invoice no."
+	if doc.Kind == models.TaxDocumentCreditNote {
+		title, numberLabel = "CREDIT NOTE", "Credit note no."
+	}
+	lh := Letterhead{
+		Name:    doc.Supplier.Name,
+		Address: doc.Supplier.Address,
+		Phone:   doc.Supplier.Phone,
+		Email:   doc.Supplier.Email,
+		GSTIN:   doc.Supplier.GSTIN,
+	}
+	d := newDocument(lh, title, doc.IssuedAt)
+
+	d.details([]field{
+		{"Bill to", doc.Recipient.Name},
+		{"Phone", doc.Recipient.Phone},
+		{"Email", doc.Recipient.Email},
+		{"Address", doc.Recipient.Address},
+		{"GSTIN", doc.Recipient.GSTIN},
+	}, []field{
+		{numberLabel, doc.Number},
+		{"Date", doc.IssuedAt.In(clock.IndianTime).Format(dateLayout)},
+		{"Against invoice", doc.OriginalNumber},
+		{"Booking ID", doc.BookingID},
+		{"Event date", doc.EventDate.UTC().Format(dateLayout)},
+		{"Place of supply", doc.PlaceOfSupply},
+		{"SAC", doc.SAC},
+	})
+
+	rows := make([][]string, len(doc.Lines))
+	for i, line := range doc.Lines {
+		rows[i] = []string{line.Description, strconv.Itoa(line.Quantity), formatRupees(line.TaxableValue), FormatAmount(line.Amount)}
+	}
+	d.table(taxLineColumns, rows)
+
+	d.total("Taxable value", formatRupees(doc.TaxableValue), false)
+	if doc.IGSTRate > 0 {
+		d.total("IGST @ "+formatRate(doc.IGSTRate), formatRupees(doc.IGST), false)
+	} else {
+		d.total("CGST @ "+formatRate(doc.CGSTRate), formatRupees(doc.CGST), false)
+		d.total("SGST @ "+formatRate(doc.SGSTRate), formatRupees(doc.SGST), false)
+	}
+	d.total("Total", formatRupees(float64(doc.Total)), true)
+
+	d.space()
+	if doc.Kind == models.TaxDocumentCreditNote {
+		note := "This credit note reverses invoice " + doc.OriginalNumber + " in full."
+		if doc.Reason != "" {
+			note += " Reason: " + doc.Reason
+		}
+		d.note(note)
+	}
+	d.note("Amounts include GST. Tax is not payable on reverse charge.")
+	d.space()
+	d.font("B", 9)
+	d.line(5, "For "+doc.Supplier.Name, "R")
+	d.space()
+	d.font("", 9)
+	d.line(5, "Authorised signatory", "R")
+	return d.output(w)
+}
+
+// formatRate formats a tax percentage, e.g. 9%
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/modernband/booking/internal/clock"
+	"github.com/modernband/booking/internal/reminder"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// runRetention is how long the history of job runs is kept
+const runRetention = 90 * 24 * time.Hour
+
+// ArchiveAfterDaysFromEnv reads how many days after its event a booking is
+// archived from the ArchiveAfterDays environment variable, which defaults to 30
+func ArchiveAfterDaysFromEnv() (int, error) {
+	value := os.Getenv("ArchiveAfterDays")
+	if value == "" {
+		return 30, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 || days > 3650 {
+		return 0, fmt.Errorf("invalid ArchiveAfterDays %q: must be a number of days from 1 to 3650", value)
+	}
+	return days, nil
+}
+
+// TrashRetentionDaysFromEnv reads how many days deleted bookings, employees and
+// payments stay in the trash from the TrashRetentionDays environment variable,
+// which defaults to 90. 0 keeps them until they are restored.
+func TrashRetentionDaysFromEnv() (int, error) {
+	value := os.Getenv("TrashRetentionDays")
+	if value == "" {
+		return 90, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 || days > 3650 {
+		return 0, fmt.Errorf("invalid TrashRetentionDays %q: must be a number of days from 0 to 3650", value)
+	}
+	return days, nil
+}
+
+// Builtin returns the service's background jobs
+func Builtin(store *storage.Store, reminders *reminder.Scheduler, archiveAfterDays, trashRetentionDays int) []Job {
+	purgeTrash := fmt.Sprintf("Permanently removes bookings, employees and payments deleted over %d days ago", trashRetentionDays)
+	if trashRetentionDays == 0 {
+		purgeTrash = "Permanently removes deleted bookings, employees and payments; disabled as TrashRetentionDays is 0"
+	}
+
+	return []Job{
+		{
+			Name:        "archive-bookings",
+			Description: fmt.Sprintf("Archives enquiries, completed and cancelled bookings %d days after their event", archiveAfterDays),
+			Schedule:    "30 2 * * *",
+			Run: func(ctx context.Context) (string, error) {
+				now := time.Now()
+				local := now.In(clock.IndianTime)
+				today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+				archived, err := store.Bookings.ArchiveBefore(ctx, today.AddDate(0, 0, -archiveAfterDays), now)
+				return fmt.Sprintf("archived %d bookings", archived), err
+			},
+		},
+		{
+			Name:        "expire-otps",
+			Description: "Removes expired OTPs, phone verifications and OTP limit events",
+			Schedule:    "*/30 * * * *",
+			Run: func(ctx context.Context) (string, error) {
+				removed, err := store.OTPs.DeleteExpired(ctx, time.Now())
+				return fmt.Sprintf("removed %d expired OTPs", removed), err
+			},
+		},
+		{
+			Name:        "send-reminders",
+			Description: "Reminds customers and crew of upcoming confirmed events",
+			Schedule:    "*/15 * * * *",
+			Run: func(ctx context.Context) (string, error) {
+				checked, err := reminders.SendDue(ctx, time.Now())
+				return fmt.Sprintf("checked %d upcoming bookings", checked), err
+			},
+		},
+		{
+			Name:        "purge-job-runs",
+			Description: "Removes job run history older than 90 days",
+			Schedule:    "45 3 * * *",
+			Run: func(ctx context.Context) (string, error) {
+				removed, err := store.Jobs.DeleteRunsBefore(ctx, time.Now().Add(-runRetention))
+				return fmt.Sprintf("removed %d job runs", removed), err
+			},
+		},
+		{
+			Name:        "purge-trash",
+			Description: purgeTrash,
+			Schedule:    "15 3 * * *",
+			Run: func(ctx context.Context) (string, error) {
+				if trashRetentionDays == 0 {
+					return "trash retention is disabled", nil
+				}
+				before := time.Now().AddDate(0, 0, -trashRetentionDays)
+				payments, err := store.Payments.PurgeDeleted(ctx, before)
+				if err != nil {
+					return "", err
+				}
+				bookings, err := store.Bookings.PurgeDeleted(ctx, before)
+				if err != nil {
+					return fmt.Sprintf("purged %d payments", payments), err
+				}
+				employees, err := store.Employees.PurgeDeleted(ctx, before)
+				return fmt.Sprintf("purged %d bookings, %d employees and %d payments", bookings, employees, payments), err
+			},
+		},
+	}
+}
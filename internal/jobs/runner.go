@@ -0,0 +1,305 @@
+// Package jobs runs background jobs on cron-like schedules. Each job's schedule,
+// lock and run history live in the store rather than in the process, so when
+// several replicas share a database each scheduled run is taken by exactly one of
+// them, and admins can change schedules or run a job by hand while it is running
+// elsewhere without the two overlapping.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/modernband/booking/internal/clock"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+const (
+	// timeout bounds a single run of a job
+	timeout = 25 * time.Minute
+	// lease is how long a job's lock is held. It outlasts timeout so that a lock is
+	// only ever taken over from an instance that stopped mid-run.
+	lease = 30 * time.Minute
+	// recordTimeout bounds recording the end of a run and releasing its lock
+	recordTimeout = 10 * time.Second
+)
+
+var (
+	// ErrUnknownJob is returned for a job name the runner does not know
+	ErrUnknownJob = errors.New("unknown job")
+	// ErrJobRunning is returned when a job is run by hand while it is already running
+	ErrJobRunning = errors.New("job is already running")
+	// ErrStopped is returned when a job is run by hand after the runner was stopped
+	ErrStopped = errors.New("job runner is stopped")
+)
+
+// Job is a task run in the background on a schedule
+type Job struct {
+	Name        string
+	Description string
+	Schedule    string // Default cron expression, used until an admin changes it
+	// Run does the work, returning a short summary of what it did
+	Run func(ctx context.Context) (string, error)
+}
+
+// Runner runs jobs when their schedules fall due. Schedules are reckoned in Indian time.
+type Runner struct {
+	repo     storage.JobRepository
+	jobs     []Job
+	instance string // Identifies this process in locks and run history
+
+	ctx     context.Context // Cancelled by Stop; bounds runs started by hand
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	stopped bool
+	running sync.WaitGroup // Runs in progress
+}
+
+// NewRunner returns a Runner for the given jobs
+func NewRunner(repo storage.JobRepository, jobs []Job) *Runner {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{
+		repo:     repo,
+		jobs:     jobs,
+		instance: fmt.Sprintf("%s-%d-%04x", host, os.Getpid(), rand.Intn(1<<16)),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Jobs returns the jobs the runner knows, in the order they were given
+func (r *Runner) Jobs() []Job {
+	return r.jobs
+}
+
+// Job returns the job with the name
+func (r *Runner) Job(name string) (Job, bool) {
+	for _, job := range r.jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+// Start stores the default schedule of each job that has no state yet. It fails
+// if a default schedule is invalid.
+func (r *Runner) Start(ctx context.Context) error {
+	now := time.Now()
+	for _, job := range r.jobs {
+		schedule, err := ParseSchedule(job.Schedule)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+		err = r.repo.Ensure(ctx, &models.JobState{
+			Name:      job.Name,
+			Schedule:  job.Schedule,
+			Enabled:   true,
+			NextRunAt: next(schedule, now),
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return fmt.Errorf("error storing state of job %s: %w", job.Name, err)
+		}
+	}
+	return nil
+}
+
+// Stop cancels the runs in progress and waits for them to record their end and
+// release their locks. No run starts afterwards.
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+
+	r.cancel()
+	r.running.Wait()
+}
+
+// begin counts a run about to start, reporting false once the runner is stopped
+func (r *Runner) begin() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		return false
+	}
+	r.running.Add(1)
+	return true
+}
+
+// Run starts the jobs that are due until ctx is cancelled, checking every
+// interval. Scheduled runs are cancelled with ctx.
+func (r *Runner) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.runDue(ctx, time.Now()); err != nil {
+			log.Printf("Failed to check for due jobs: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue starts, each in its own goroutine, the due jobs this instance manages to lock
+func (r *Runner) runDue(ctx context.Context, now time.Time) error {
+	states, err := r.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, state := range states {
+		job, ok := r.Job(state.Name)
+		if !ok || !state.Enabled || state.NextRunAt.After(now) {
+			continue
+		}
+		schedule, err := ParseSchedule(state.Schedule)
+		if err != nil {
+			log.Printf("Skipping job %s: %v", state.Name, err)
+			continue
+		}
+
+		if !r.begin() {
+			return nil
+		}
+		locked, err := r.repo.LockDue(ctx, job.Name, r.instance, now, now.Add(lease), next(schedule, now))
+		if err != nil {
+			log.Printf("Failed to lock job %s: %v", job.Name, err)
+		}
+		if !locked {
+			r.running.Done()
+			continue
+		}
+		go func(job Job) {
+			defer r.running.Done()
+			r.execute(ctx, job, models.JobTriggerSchedule, "")
+		}(job)
+	}
+	return nil
+}
+
+// Trigger runs a job now on behalf of an admin and returns the finished run,
+// leaving its schedule unchanged. It returns ErrJobRunning if the job is running
+// on any instance, or ErrStopped once the runner is stopped.
+func (r *Runner) Trigger(ctx context.Context, name, by string) (*models.JobRun, error) {
+	job, ok := r.Job(name)
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+	if !r.begin() {
+		return nil, ErrStopped
+	}
+	defer r.running.Done()
+
+	now := time.Now()
+	locked, err := r.repo.Lock(ctx, name, r.instance, now, now.Add(lease))
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrJobRunning
+	}
+	// The run outlives a client that stops waiting for it, but not the runner
+	return r.execute(r.ctx, job, models.JobTriggerManual, by), nil
+}
+
+// Update changes a job's schedule and whether it runs, rescheduling its next run
+func (r *Runner) Update(ctx context.Context, name, expr string, enabled bool, by string) (*models.JobState, error) {
+	if _, ok := r.Job(name); !ok {
+		return nil, ErrUnknownJob
+	}
+	schedule, err := ParseSchedule(expr)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = r.repo.UpdateSchedule(ctx, &models.JobState{
+		Name:      name,
+		Schedule:  expr,
+		Enabled:   enabled,
+		NextRunAt: next(schedule, now),
+		UpdatedBy: by,
+		UpdatedAt: now,
+	})
+	if err == storage.ErrNotFound {
+		return nil, ErrUnknownJob
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Find(ctx, name)
+}
+
+// execute runs a locked job, records the run and releases the lock
+func (r *Runner) execute(ctx context.Context, job Job, trigger, by string) *models.JobRun {
+	run := &models.JobRun{
+		Job:         job.Name,
+		Trigger:     trigger,
+		TriggeredBy: by,
+		Instance:    r.instance,
+		Status:      models.JobRunRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := r.repo.CreateRun(ctx, run); err != nil {
+		log.Printf("Failed to record start of job %s: %v", job.Name, err)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	result, err := call(runCtx, job)
+	cancel()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Result = result
+	if err != nil {
+		run.Status = models.JobRunFailed
+		run.Error = err.Error()
+		log.Printf("Job %s failed: %v", job.Name, err)
+	} else {
+		run.Status = models.JobRunSucceeded
+		log.Printf("Job %s finished: %s", job.Name, result)
+	}
+
+	// Record the end even when ctx was cancelled by a shutdown, so the lock is not
+	// left held until its lease expires
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+	if !run.ID.IsZero() {
+		if err := r.repo.FinishRun(recordCtx, run); err != nil {
+			log.Printf("Failed to record end of job %s: %v", job.Name, err)
+		}
+	}
+	if err := r.repo.Unlock(recordCtx, job.Name, r.instance, run); err != nil {
+		log.Printf("Failed to unlock job %s: %v", job.Name, err)
+	}
+	return run
+}
+
+// call runs a job, turning a panic into an error so one faulty job cannot stop the service
+func call(ctx context.Context, job Job) (result string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// next returns the first time the schedule matches after now, reckoned in Indian time
+func next(schedule *Schedule, now time.Time) time.Time {
+	return schedule.Next(now.In(clock.IndianTime)).UTC()
+}
@@ -0,0 +1,240 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage/memory"
+)
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewStore().Jobs
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	if err := repo.Ensure(ctx, &models.JobState{Name: "cleanup", Enabled: true, NextRunAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	lock := func(owner string, at time.Time) bool {
+		t.Helper()
+		locked, err := repo.Lock(ctx, "cleanup", owner, at, at.Add(lease))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return locked
+	}
+
+	if !lock("a", now) {
+		t.Fatal("a could not lock an unlocked job")
+	}
+	if lock("a", now.Add(time.Minute)) {
+		t.Error("a locked the job again while holding its lock")
+	}
+	if lock("b", now.Add(lease-time.Second)) {
+		t.Error("b locked the job while a held its lock")
+	}
+
+	// An expired lock is taken over, and its old holder cannot release the new one
+	expired := now.Add(lease)
+	if !lock("b", expired) {
+		t.Fatal("b could not take over an expired lock")
+	}
+	if err := repo.Unlock(ctx, "cleanup", "a", &models.JobRun{Job: "cleanup"}); err != nil {
+		t.Fatal(err)
+	}
+	if lock("c", expired.Add(time.Minute)) {
+		t.Error("c locked the job after a released a lock it no longer held")
+	}
+
+	if err := repo.Unlock(ctx, "cleanup", "b", &models.JobRun{Job: "cleanup"}); err != nil {
+		t.Fatal(err)
+	}
+	if !lock("c", expired.Add(time.Minute)) {
+		t.Error("c could not lock the job after b released it")
+	}
+
+	if locked, err := repo.Lock(ctx, "unknown", "a", now, now.Add(lease)); err != nil || locked {
+		t.Errorf("locking an unknown job = %v, %v, want false", locked, err)
+	}
+}
+
+func TestLockDue(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewStore().Jobs
+	due := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	next := due.Add(time.Hour)
+	if err := repo.Ensure(ctx, &models.JobState{Name: "cleanup", Enabled: true, NextRunAt: due}); err != nil {
+		t.Fatal(err)
+	}
+
+	if locked, _ := repo.LockDue(ctx, "cleanup", "a", due.Add(-time.Second), due.Add(lease), next); locked {
+		t.Fatal("a job was locked before it was due")
+	}
+
+	// Exactly one of many instances takes each scheduled run
+	var wg sync.WaitGroup
+	var taken atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(owner string) {
+			defer wg.Done()
+			locked, err := repo.LockDue(ctx, "cleanup", owner, due, due.Add(lease), next)
+			if err != nil {
+				t.Error(err)
+			}
+			if locked {
+				taken.Add(1)
+			}
+		}(string(rune('a' + i)))
+	}
+	wg.Wait()
+	if n := taken.Load(); n != 1 {
+		t.Fatalf("%d instances took the run, want 1", n)
+	}
+
+	state, err := repo.Find(ctx, "cleanup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.NextRunAt.Equal(next) {
+		t.Errorf("next run = %v, want %v", state.NextRunAt, next)
+	}
+	if err := repo.Unlock(ctx, "cleanup", state.LockedBy, &models.JobRun{Job: "cleanup"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The run already taken is not taken again once the lock is released
+	if locked, _ := repo.LockDue(ctx, "cleanup", "z", due, due.Add(lease), next); locked {
+		t.Error("a run was taken twice")
+	}
+	if locked, _ := repo.LockDue(ctx, "cleanup", "z", next, next.Add(lease), next.Add(time.Hour)); !locked {
+		t.Error("the next run could not be taken")
+	}
+}
+
+func TestTriggerWhileRunning(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewStore().Jobs
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	var runs atomic.Int32
+	job := Job{
+		Name:     "cleanup",
+		Schedule: "@hourly",
+		Run: func(ctx context.Context) (string, error) {
+			runs.Add(1)
+			started <- struct{}{}
+			<-release
+			return "done", nil
+		},
+	}
+	a, b := NewRunner(repo, []Job{job}), NewRunner(repo, []Job{job})
+	a.instance, b.instance = "a", "b"
+	if err := a.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	state, err := repo.Find(ctx, "cleanup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.runDue(ctx, state.NextRunAt); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.runDue(ctx, state.NextRunAt); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// Neither instance may run the job by hand while the scheduled run holds it
+	for _, r := range []*Runner{a, b} {
+		if _, err := r.Trigger(ctx, "cleanup", "admin"); !errors.Is(err, ErrJobRunning) {
+			t.Errorf("%s: Trigger error = %v, want %v", r.instance, err, ErrJobRunning)
+		}
+	}
+
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		state, err = repo.Find(ctx, "cleanup")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.LockedBy == "" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the scheduled run never released its lock")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if state.LastRun == nil || state.LastRun.Instance != "a" || state.LastRun.Status != models.JobRunSucceeded {
+		t.Errorf("last run = %+v, want a successful run by a", state.LastRun)
+	}
+
+	run, err := b.Trigger(ctx, "cleanup", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != models.JobRunSucceeded || run.Trigger != models.JobTriggerManual || run.Instance != "b" {
+		t.Errorf("run = %+v, want a successful manual run by b", run)
+	}
+	if n := runs.Load(); n != 2 {
+		t.Errorf("the job ran %d times, want 2", n)
+	}
+}
+
+func TestStop(t *testing.T) {
+	repo := memory.NewStore().Jobs
+	started := make(chan struct{})
+	job := Job{
+		Name:     "cleanup",
+		Schedule: "@hourly",
+		Run: func(ctx context.Context) (string, error) {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}
+	r := NewRunner(repo, []Job{job})
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	state, err := repo.Find(ctx, "cleanup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.runDue(ctx, state.NextRunAt); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// A shutdown cancels the run, which still records its end and releases its lock
+	cancel()
+	r.Stop()
+	state, err = repo.Find(context.Background(), "cleanup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.LockedBy != "" || state.LastRun == nil || state.LastRun.Status != models.JobRunFailed {
+		t.Errorf("state = %+v, want an unlocked job whose last run failed", state)
+	}
+	runs, err := repo.ListRuns(context.Background(), "cleanup", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].FinishedAt == nil {
+		t.Errorf("runs = %+v, want one finished run", runs)
+	}
+
+	if _, err := r.Trigger(context.Background(), "cleanup", "admin"); !errors.Is(err, ErrStopped) {
+		t.Errorf("Trigger error = %v, want %v", err, ErrStopped)
+	}
+}
@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/modernband/booking/internal/clock"
+)
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5/10 * * * *",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"0 0 30 2 *",
+		"0 0 31 4,6,9,11 *",
+	} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, clock.IndianTime)
+	}
+	friday := at(2026, 10, 16, 10, 7)
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"*/15 * * * *", friday, at(2026, 10, 16, 10, 15)},
+		{"*/15 * * * *", friday.Add(30 * time.Second), at(2026, 10, 16, 10, 15)},
+		{"0 9 * * *", at(2026, 10, 16, 9, 0), at(2026, 10, 17, 9, 0)}, // Strictly after
+		{"0 9-17/4 * * *", friday, at(2026, 10, 16, 13, 0)},
+		{"30 2 * * 1-5", friday, at(2026, 10, 19, 2, 30)},
+		{"0 8 * * 7", friday, at(2026, 10, 18, 8, 0)}, // 7 is Sunday
+		{"0 8 * * 0", friday, at(2026, 10, 18, 8, 0)},
+		{"0 0 13 * 5", friday, at(2026, 10, 23, 0, 0)}, // The 13th or a Friday
+		{"0 0 13 * 5", at(2026, 11, 7, 0, 0), at(2026, 11, 13, 0, 0)},
+		{"0 0 29 2 *", at(2026, 3, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+		{"0 0 31 * *", at(2026, 11, 1, 0, 0), at(2026, 12, 31, 0, 0)},
+		{"@monthly", at(2026, 12, 15, 0, 0), at(2027, 1, 1, 0, 0)},
+		{"@hourly", at(2026, 12, 31, 23, 59), at(2027, 1, 1, 0, 0)},
+		{" 0,30 6 1 1,7 * ", friday, at(2027, 1, 1, 6, 0)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q after %v = %v, want %v", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestNextInIndianTime(t *testing.T) {
+	schedule, err := ParseSchedule("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 07:30 in India, so 9 am is later the same day
+	got := next(schedule, time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC))
+	want := time.Date(2026, 10, 16, 3, 30, 0, 0, time.UTC)
+	if !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("next = %v, want %v", got, want)
+	}
+
+	// 21:00 UTC is already the next morning in India
+	got = next(schedule, time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC))
+	want = time.Date(2026, 10, 17, 3, 30, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("next = %v, want %v", got, want)
+	}
+}
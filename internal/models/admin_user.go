@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/modernband/booking/internal/auth"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -15,6 +16,13 @@ type AdminUser struct {
 	Username     string             `json:"username" bson:"username"`
 	Password     string             `json:"-" bson:"password"` // Password is not exposed in JSON responses
 	IsAdminUser  bool               `json:"isAdminUser" bson:"is_admin_user"`
+	Role         string             `json:"role" bson:"role,omitempty"` // owner, manager or accountant; empty means owner
 	CreatedAt    time.Time          `json:"createdAt" bson:"created_at"`
 	UpdatedAt    time.Time          `json:"updatedAt" bson:"updated_at"`
 }
+
+// IsOwner reports whether the admin user is an owner, with full access. At least
+// one admin user must stay an owner.
+func (a *AdminUser) IsOwner() bool {
+	return auth.AdminRole(a.Role) == auth.RoleOwner
+}
@@ -1,11 +1,23 @@
 package models
 
 import (
+	"crypto/rand"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Band times a booking can be made for. Each one but BandTimeCustom is a time
+// slot with its own capacity; a custom time, described in CustomTimeSlot, counts
+// only against the day's capacity.
+const (
+	BandTimeMorning   = "morning"
+	BandTimeAfternoon = "afternoon"
+	BandTimeEvening   = "evening"
+	BandTimeNight     = "night"
+	BandTimeCustom    = "custom"
+)
+
 // Booking represents a user's booking request data
 type Booking struct {
 	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
@@ -19,19 +31,72 @@ type Booking struct {
 	Venue           string             `json:"venue" bson:"venue"`
 	City            string             `json:"city" bson:"city"`
 	Customization   string             `json:"customization,omitempty" bson:"customization,omitempty"`
-	BandTime        string             `json:"bandTime,omitempty" bson:"band_time,omitempty"`
+	BandTime        string             `json:"bandTime,omitempty" bson:"band_time,omitempty" binding:"omitempty,oneof=morning afternoon evening night custom"`
 	CustomTimeSlot  string             `json:"customTimeSlot,omitempty" bson:"custom_time_slot,omitempty"`
-	NumberOfPeople  int                `json:"numberOfPeople,omitempty" bson:"number_of_people,omitempty"`
-	NumberOfLights  int                `json:"numberOfLights,omitempty" bson:"number_of_lights,omitempty"`
-	NumberOfDhols   int                `json:"numberOfDhols,omitempty" bson:"number_of_dhols,omitempty"`
-	GhodaBaggi      int                `json:"ghodaBaggi,omitempty" bson:"ghoda_baggi,omitempty"`
+	TimeSlot        string             `json:"timeSlot,omitempty" bson:"time_slot,omitempty"` // Derived from BandTime for capacity checks
+	NumberOfPeople  int                `json:"numberOfPeople,omitempty" bson:"number_of_people,omitempty" binding:"gte=0"`
+	NumberOfLights  int                `json:"numberOfLights,omitempty" bson:"number_of_lights,omitempty" binding:"gte=0"`
+	NumberOfDhols   int                `json:"numberOfDhols,omitempty" bson:"number_of_dhols,omitempty" binding:"gte=0"`
+	GhodaBaggi      int                `json:"ghodaBaggi,omitempty" bson:"ghoda_baggi,omitempty" binding:"gte=0"`
 	GhodiForBaraat  bool               `json:"ghodiForBaraat" bson:"ghodi_for_baraat"`
 	Fireworks       bool               `json:"fireworks" bson:"fireworks"`
-	FireworksAmount int                `json:"fireworksAmount,omitempty" bson:"fireworks_amount,omitempty"`
+	FireworksAmount int                `json:"fireworksAmount,omitempty" bson:"fireworks_amount,omitempty" binding:"gte=0"`
 	FlowerCanon     bool               `json:"flowerCanon" bson:"flower_canon"`
 	DoliForVidai    bool               `json:"DoliForVidai" bson:"doli_for_vidai"`
-	Amount          int                `json:"amount" bson:"amount"`
-	AdvancePayment  int                `json:"advancePayment" bson:"advance_payment"`
+	Amount          int                `json:"amount" bson:"amount" binding:"gte=0"`
+	AdvancePayment  int                `json:"advancePayment" bson:"advance_payment"` // Recorded before the payment ledger existed
+	AmountPaid      int                `json:"amountPaid" bson:"-"`                   // Computed from AdvancePayment and the payment ledger
+	BalanceDue      int                `json:"balanceDue" bson:"-"`                   // Computed; negative when overpaid
+	Quote           *Quote             `json:"quote,omitempty" bson:"quote,omitempty"`
 	PhoneVerified   bool               `json:"phoneVerified" bson:"phone_verified"`
+	Status          string             `json:"status" bson:"status"`
+	StatusHistory   []StatusChange     `json:"statusHistory,omitempty" bson:"status_history,omitempty"`
+	Revision        int                `json:"revision" bson:"revision"`                          // Incremented on every edit
+	ArchivedAt      *time.Time         `json:"archivedAt,omitempty" bson:"archived_at,omitempty"` // Set once a past booking is archived
+	DeletedAt       *time.Time         `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"`   // Set while the booking is in the trash
+	DeletedBy       string             `json:"deletedBy,omitempty" bson:"deleted_by,omitempty"`
 	CreatedAt       time.Time          `json:"createdAt" bson:"created_at"`
 }
+
+// SlotName returns the time slot a booking occupies: its band time, or none for
+// a custom, missing or unrecognised band time, which counts only against the
+// day's capacity
+func (b *Booking) SlotName() string {
+	switch b.BandTime {
+	case BandTimeMorning, BandTimeAfternoon, BandTimeEvening, BandTimeNight:
+		return b.BandTime
+	}
+	return ""
+}
+
+// GenerateBookingID creates a unique 6-character alphanumeric booking ID
+func GenerateBookingID() (string, error) {
+	// Define character set (alphanumeric)
+	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	const length = 6
+	const charsetLen = byte(len(charset))
+
+	// Create a byte slice for the result
+	result := make([]byte, length)
+
+	for i := 0; i < length; i++ {
+		// We need a random number from 0 to len(charset)-1
+		// To avoid modulo bias, we get a random byte and check if it's in the valid range
+		max := 256 - (256 % int(charsetLen))
+		b := make([]byte, 1)
+		for {
+			_, err := rand.Read(b)
+			if err != nil {
+				return "", err
+			}
+			// Reject values that would create modulo bias
+			if int(b[0]) < max {
+				// Use modulo now that we've eliminated the bias
+				result[i] = charset[b[0]%charsetLen]
+				break
+			}
+		}
+	}
+
+	return string(result), nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BookingRevision records one edit of a booking. Revisions are append-only.
+type BookingRevision struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	BookingID string             `json:"bookingId" bson:"booking_id"`
+	Revision  int                `json:"revision" bson:"revision"` // The booking's revision after the edit
+	Changes   []FieldChange      `json:"changes" bson:"changes"`
+	ChangedBy string             `json:"changedBy" bson:"changed_by"`
+	ChangedAt time.Time          `json:"changedAt" bson:"changed_at"`
+}
+
+// FieldChange is the old and new value of one booking field, named by its JSON key
+type FieldChange struct {
+	Field string      `json:"field" bson:"field"`
+	From  interface{} `json:"from" bson:"from"`
+	To    interface{} `json:"to" bson:"to"`
+}
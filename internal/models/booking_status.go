@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Booking statuses
+const (
+	BookingStatusEnquiry   = "enquiry"
+	BookingStatusConfirmed = "confirmed"
+	BookingStatusCompleted = "completed"
+	BookingStatusCancelled = "cancelled"
+)
+
+// bookingTransitions lists the statuses each status may move to
+var bookingTransitions = map[string][]string{
+	BookingStatusEnquiry:   {BookingStatusConfirmed, BookingStatusCancelled},
+	BookingStatusConfirmed: {BookingStatusCompleted, BookingStatusCancelled},
+	BookingStatusCompleted: {},
+	BookingStatusCancelled: {},
+}
+
+// StatusChange records one transition in a booking's lifecycle
+type StatusChange struct {
+	From   string    `json:"from,omitempty" bson:"from,omitempty"` // Empty for the initial status
+	To     string    `json:"to" bson:"to"`
+	At     time.Time `json:"at" bson:"at"`
+	By     string    `json:"by" bson:"by"` // Username of the staff member, or "customer"
+	Reason string    `json:"reason,omitempty" bson:"reason,omitempty"`
+}
+
+// IsValidBookingStatus reports whether status is one of the known booking statuses
+func IsValidBookingStatus(status string) bool {
+	_, ok := bookingTransitions[status]
+	return ok
+}
+
+// CanTransition reports whether a booking may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, next := range bookingTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentStatus returns the booking's status. Bookings created before statuses
+// existed have none and are treated as confirmed.
+func (b *Booking) CurrentStatus() string {
+	if b.Status == "" {
+		return BookingStatusConfirmed
+	}
+	return b.Status
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Day availability statuses
+const (
+	AvailabilityFree    = "free"
+	AvailabilityLimited = "limited"
+	AvailabilityFull    = "full"
+)
+
+// CapacitySettings describes how many bookings the band can serve in parallel
+type CapacitySettings struct {
+	TeamsPerDay   int            `json:"teamsPerDay" bson:"teams_per_day"`    // 0 means unlimited
+	TeamsPerSlot  int            `json:"teamsPerSlot" bson:"teams_per_slot"`  // 0 means unlimited
+	DateOverrides map[string]int `json:"dateOverrides" bson:"date_overrides"` // Teams per day keyed by YYYY-MM-DD; 0 closes the date
+	UpdatedBy     string         `json:"updatedBy" bson:"updated_by"`
+	UpdatedAt     time.Time      `json:"updatedAt" bson:"updated_at"`
+}
+
+// SlotCount is the number of bookings on a day in one time slot
+type SlotCount struct {
+	Day   string `json:"day" bson:"day"` // YYYY-MM-DD
+	Slot  string `json:"slot" bson:"slot"`
+	Count int    `json:"count" bson:"count"`
+}
+
+// DayAvailability summarizes bookings and remaining capacity for a day
+type DayAvailability struct {
+	Date      string         `json:"date"`
+	Status    string         `json:"status"`
+	Booked    int            `json:"booked"`
+	Capacity  *int           `json:"capacity"`  // nil when unlimited
+	Remaining *int           `json:"remaining"` // nil when unlimited
+	Slots     map[string]int `json:"slots"`     // Bookings per time slot
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Crew roles an employee can be assigned to at an event
+const (
+	CrewRoleDholPlayer   = "dhol_player"
+	CrewRoleLightBearer  = "light_bearer"
+	CrewRoleBandMaster   = "band_master"
+	CrewRoleHorseHandler = "horse_handler"
+)
+
+// CrewAssignment links an employee to a booking in a role at an agreed wage. The
+// event date and time slot are copied from the booking to detect overlapping assignments.
+type CrewAssignment struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	BookingID  string             `json:"bookingId" bson:"booking_id"`
+	EmployeeID primitive.ObjectID `json:"employeeId" bson:"employee_id"`
+	Username   string             `json:"username" bson:"username"`
+	Role       string             `json:"role" bson:"role"`
+	Wage       float64            `json:"wage" bson:"wage"` // Accrued when the booking is completed
+	EventDate  time.Time          `json:"eventDate" bson:"event_date"`
+	TimeSlot   string             `json:"timeSlot,omitempty" bson:"time_slot,omitempty"`
+	AssignedBy string             `json:"assignedBy" bson:"assigned_by"`
+	AssignedAt time.Time          `json:"assignedAt" bson:"assigned_at"`
+	DeletedAt  *time.Time         `json:"-" bson:"deleted_at,omitempty"` // Set while the booking is in the trash
+}
+
+// Overlaps reports whether two assignments are for events at the same time: the
+// same day and time slot, or the same day when either time slot is unknown
+func (a *CrewAssignment) Overlaps(other *CrewAssignment) bool {
+	if a.EventDate.UTC().Format("2006-01-02") != other.EventDate.UTC().Format("2006-01-02") {
+		return false
+	}
+	return a.TimeSlot == "" || other.TimeSlot == "" || a.TimeSlot == other.TimeSlot
+}
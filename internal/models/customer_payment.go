@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Customer payment methods
+const (
+	PaymentMethodCash = "cash"
+	PaymentMethodUPI  = "upi"
+	PaymentMethodBank = "bank"
+)
+
+// CustomerPayment represents money received from a customer against a booking.
+// Payments are never deleted; a mistaken entry is voided instead.
+type CustomerPayment struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	BookingID  string             `json:"bookingId" bson:"booking_id"`
+	Amount     int                `json:"amount" bson:"amount"`
+	Method     string             `json:"method" bson:"method"`
+	Reference  string             `json:"reference,omitempty" bson:"reference,omitempty"` // UPI transaction ID, bank reference or receipt number
+	ReceivedBy string             `json:"receivedBy" bson:"received_by"`
+	PaidAt     time.Time          `json:"paidAt" bson:"paid_at"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+	VoidedAt   *time.Time         `json:"voidedAt,omitempty" bson:"voided_at,omitempty"`
+	VoidedBy   string             `json:"voidedBy,omitempty" bson:"voided_by,omitempty"`
+	VoidReason string             `json:"voidReason,omitempty" bson:"void_reason,omitempty"`
+}
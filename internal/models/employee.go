@@ -20,5 +20,7 @@ type Employee struct {
 	Password                 string             `json:"-" bson:"password"` // Password is not exposed in JSON responses
 	CreatedAt                time.Time          `json:"createdAt" bson:"created_at"`
 	UpdatedAt                time.Time          `json:"updatedAt" bson:"updated_at"`
+	DeletedAt                *time.Time         `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"` // Set while the employee is in the trash
+	DeletedBy                string             `json:"deletedBy,omitempty" bson:"deleted_by,omitempty"`
 	Payments                 []Payment          `json:"payments,omitempty" bson:"payments,omitempty"`
 }
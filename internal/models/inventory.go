@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Inventory item codes, one per kind of equipment a booking can request
+const (
+	ItemLights     = "lights"
+	ItemDhols      = "dhols"
+	ItemGhodaBaggi = "ghoda_baggi"
+	ItemGhodi      = "ghodi"
+)
+
+// InventoryItem records how many units of an item the band owns. Items without a
+// record are not tracked and never limit bookings.
+type InventoryItem struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Code            string             `json:"code" bson:"code"`
+	Name            string             `json:"name" bson:"name"`
+	QuantityOwned   int                `json:"quantityOwned" bson:"quantity_owned"`
+	InMaintenance   int                `json:"inMaintenance" bson:"in_maintenance"` // Units out of service
+	MaintenanceNote string             `json:"maintenanceNote,omitempty" bson:"maintenance_note,omitempty"`
+	UpdatedBy       string             `json:"updatedBy" bson:"updated_by"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// Available returns the units that can be sent to events
+func (i *InventoryItem) Available() int {
+	if i.InMaintenance >= i.QuantityOwned {
+		return 0
+	}
+	return i.QuantityOwned - i.InMaintenance
+}
+
+// Equipment returns the quantity of each inventory item a booking requests.
+// Items the booking does not use are absent.
+func (b *Booking) Equipment() map[string]int {
+	equipment := map[string]int{}
+	if b.NumberOfLights > 0 {
+		equipment[ItemLights] = b.NumberOfLights
+	}
+	if b.NumberOfDhols > 0 {
+		equipment[ItemDhols] = b.NumberOfDhols
+	}
+	if b.GhodaBaggi > 0 {
+		equipment[ItemGhodaBaggi] = b.GhodaBaggi
+	}
+	if b.GhodiForBaraat {
+		equipment[ItemGhodi] = 1
+	}
+	return equipment
+}
+
+// EquipmentReservation is the quantity of an item reserved by bookings on a day
+type EquipmentReservation struct {
+	Day      string `json:"day" bson:"day"` // YYYY-MM-DD
+	Item     string `json:"item" bson:"item"`
+	Quantity int    `json:"quantity" bson:"quantity"`
+}
+
+// ItemAvailability is the stock of an item left on a day
+type ItemAvailability struct {
+	Available int `json:"available"` // Units owned and not in maintenance
+	Reserved  int `json:"reserved"`
+	Remaining int `json:"remaining"` // Negative when stock was reduced below existing reservations
+}
+
+// DayInventory is the stock of every tracked item on a day
+type DayInventory struct {
+	Date  string                      `json:"date"`
+	Items map[string]ItemAvailability `json:"items"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// What started a job run
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// Job run statuses
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// JobState is the schedule and lock of a background job, shared by every running
+// instance of the service. An instance runs the job only while it holds the lock.
+type JobState struct {
+	Name        string     `json:"name" bson:"_id"`
+	Schedule    string     `json:"schedule" bson:"schedule"` // Cron expression in Indian time
+	Enabled     bool       `json:"enabled" bson:"enabled"`
+	NextRunAt   time.Time  `json:"nextRunAt" bson:"next_run_at"`
+	LockedBy    string     `json:"lockedBy,omitempty" bson:"locked_by,omitempty"` // Instance running the job
+	LockedUntil *time.Time `json:"lockedUntil,omitempty" bson:"locked_until,omitempty"`
+	LastRun     *JobRun    `json:"lastRun,omitempty" bson:"last_run,omitempty"`
+	UpdatedBy   string     `json:"updatedBy,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt" bson:"updated_at"`
+}
+
+// JobRun records one run of a background job
+type JobRun struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Job         string             `json:"job" bson:"job"`
+	Trigger     string             `json:"trigger" bson:"trigger"`
+	TriggeredBy string             `json:"triggeredBy,omitempty" bson:"triggered_by,omitempty"` // Admin who ran the job by hand
+	Instance    string             `json:"instance" bson:"instance"`
+	Status      string             `json:"status" bson:"status"`
+	Result      string             `json:"result,omitempty" bson:"result,omitempty"` // Summary of what the run did
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`
+	StartedAt   time.Time          `json:"startedAt" bson:"started_at"`
+	FinishedAt  *time.Time         `json:"finishedAt,omitempty" bson:"finished_at,omitempty"`
+}
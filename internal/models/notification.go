@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification channels
+const (
+	ChannelSMS      = "sms"
+	ChannelEmail    = "email"
+	ChannelWhatsApp = "whatsapp"
+)
+
+// Notification delivery statuses
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed" // Retries were exhausted
+)
+
+// Notification is a rendered message in the outbox. It stays pending until a
+// provider accepts it and is retried with backoff when sending fails.
+type Notification struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Event         string             `json:"event" bson:"event"`
+	Channel       string             `json:"channel" bson:"channel"`
+	To            string             `json:"to" bson:"to"` // Phone number or email address
+	Subject       string             `json:"subject,omitempty" bson:"subject,omitempty"`
+	Body          string             `json:"body" bson:"body"`
+	BookingID     string             `json:"bookingId,omitempty" bson:"booking_id,omitempty"`
+	Key           string             `json:"key,omitempty" bson:"key,omitempty"` // Unique when set, so a message is queued at most once
+	Status        string             `json:"status" bson:"status"`
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	LastError     string             `json:"lastError,omitempty" bson:"last_error,omitempty"`
+	NextAttemptAt time.Time          `json:"nextAttemptAt" bson:"next_attempt_at"`
+	SentAt        *time.Time         `json:"sentAt,omitempty" bson:"sent_at,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// IsNotificationStatus reports whether status is one of the delivery statuses
+func IsNotificationStatus(status string) bool {
+	return status == NotificationPending || status == NotificationSent || status == NotificationFailed
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OTPVerification tracks the one-time password issued to a phone number and
+// whether that number has been verified. There is at most one record per phone.
+type OTPVerification struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Phone         string             `json:"phone" bson:"phone"`
+	CodeHash      string             `json:"-" bson:"code_hash,omitempty"`
+	CodeExpiresAt time.Time          `json:"codeExpiresAt" bson:"code_expires_at"`
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	LastSentAt    time.Time          `json:"lastSentAt" bson:"last_sent_at"`
+	VerifiedAt    *time.Time         `json:"verifiedAt,omitempty" bson:"verified_at,omitempty"`
+	ExpiresAt     time.Time          `json:"expiresAt" bson:"expires_at"` // Document is removed by a TTL index after this time
+	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// OTP event kinds counted against a phone's rolling limits
+const (
+	OTPEventSent   = "sent"
+	OTPEventFailed = "failed"
+)
+
+// OTPEvent records a code sent to a phone or a wrong code entered for it. Events
+// are kept for the length of the rolling window they are counted in.
+type OTPEvent struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Phone     string             `json:"phone" bson:"phone"`
+	Kind      string             `json:"kind" bson:"kind"`
+	At        time.Time          `json:"at" bson:"at"`
+	ExpiresAt time.Time          `json:"expiresAt" bson:"expires_at"` // Document is removed by a TTL index after this time
+}
+
+// NormalizePhone trims a contact number so lookups match what was verified
+func NormalizePhone(phone string) string {
+	return strings.ReplaceAll(strings.TrimSpace(phone), " ", "")
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Package is a named band package customers can book. Bookings refer to it by Code
+// through their PackageType.
+type Package struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Code        string             `json:"code" bson:"code"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Musicians   int                `json:"musicians" bson:"musicians"` // Included musicians
+	Lights      int                `json:"lights" bson:"lights"`       // Included lights
+	Dhols       int                `json:"dhols" bson:"dhols"`         // Included dhols
+	Hours       int                `json:"hours" bson:"hours"`         // Included performance hours
+	Price       int                `json:"price" bson:"price"`
+	ActiveFrom  *time.Time         `json:"activeFrom,omitempty" bson:"active_from,omitempty"` // Bookable for events on or after this date
+	ActiveTo    *time.Time         `json:"activeTo,omitempty" bson:"active_to,omitempty"`     // Bookable for events on or before this date
+	CreatedAt   time.Time          `json:"createdAt" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updated_at"`
+}
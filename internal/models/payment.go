@@ -13,4 +13,12 @@ type Payment struct {
 	Date       time.Time          `json:"date" bson:"date"`
 	EmployeeID primitive.ObjectID `json:"employeeId" bson:"employee_id"`
 	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+	DeletedAt  *time.Time         `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"` // Set while the payment is in the trash
+	DeletedBy  string             `json:"deletedBy,omitempty" bson:"deleted_by,omitempty"`
+}
+
+// PaymentTotal is the sum and number of an employee's payments
+type PaymentTotal struct {
+	Amount float64 `json:"amount" bson:"amount"`
+	Count  int     `json:"count" bson:"count"`
 }
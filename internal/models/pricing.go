@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RateCard holds the add-on prices and surcharges used to compute booking quotes;
+// package prices come from the package catalog. The most recently created rate
+// card is the one in effect; older ones are kept as history.
+type RateCard struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	LightPrice       int                `json:"lightPrice" bson:"light_price"`            // Per light
+	DholPrice        int                `json:"dholPrice" bson:"dhol_price"`              // Per dhol
+	GhodaBaggiPrice  int                `json:"ghodaBaggiPrice" bson:"ghoda_baggi_price"` // Per ghoda baggi
+	GhodiPrice       int                `json:"ghodiPrice" bson:"ghodi_price"`
+	FlowerCanonPrice int                `json:"flowerCanonPrice" bson:"flower_canon_price"`
+	DoliPrice        int                `json:"doliPrice" bson:"doli_price"`
+	CitySurcharges   map[string]int     `json:"citySurcharges" bson:"city_surcharges"` // Flat amount keyed by lower-case city
+	DateSurcharges   []DateSurcharge    `json:"dateSurcharges" bson:"date_surcharges"`
+	CreatedBy        string             `json:"createdBy" bson:"created_by"`
+	CreatedAt        time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// DateSurcharge raises the price of events within a date range, e.g. peak wedding dates
+type DateSurcharge struct {
+	Name    string    `json:"name" bson:"name"`
+	From    time.Time `json:"from" bson:"from"`                           // Inclusive
+	To      time.Time `json:"to" bson:"to"`                               // Inclusive
+	Percent int       `json:"percent,omitempty" bson:"percent,omitempty"` // Percentage of the subtotal
+	Amount  int       `json:"amount,omitempty" bson:"amount,omitempty"`   // Flat amount
+}
+
+// QuoteItem is a single line of a booking quote
+type QuoteItem struct {
+	Code        string `json:"code" bson:"code"`
+	Description string `json:"description" bson:"description"`
+	Quantity    int    `json:"quantity" bson:"quantity"`
+	UnitPrice   int    `json:"unitPrice" bson:"unit_price"`
+	Amount      int    `json:"amount" bson:"amount"`
+}
+
+// Quote is the itemized server-side price of a booking
+type Quote struct {
+	Items      []QuoteItem        `json:"items" bson:"items"`
+	Subtotal   int                `json:"subtotal" bson:"subtotal"` // Package and add-ons before surcharges
+	Total      int                `json:"total" bson:"total"`
+	RateCardID primitive.ObjectID `json:"rateCardId" bson:"rate_card_id"`
+}
@@ -35,9 +35,176 @@ type CreateAdminUserRequest struct {
 	Email        string `json:"email" binding:"required,email"`
 	Username     string `json:"username" binding:"required"`
 	Password     string `json:"password" binding:"required"`
+	Role         string `json:"role" binding:"omitempty,oneof=owner manager accountant"`
 }
 
 // DeletePaymentRequest represents the request for deleting a payment
 type DeletePaymentRequest struct {
 	PaymentID primitive.ObjectID `json:"paymentId" binding:"required"`
 }
+
+// SendOTPRequest represents the request for sending an OTP to a phone number
+type SendOTPRequest struct {
+	ContactNumber string `json:"contact_number" binding:"required"`
+}
+
+// VerifyOTPRequest represents the request for verifying an OTP
+type VerifyOTPRequest struct {
+	ContactNumber string `json:"contact_number" binding:"required"`
+	OTP           string `json:"otp" binding:"required,len=6,numeric"`
+}
+
+// UpdateRateCardRequest represents the request for publishing a new rate card
+type UpdateRateCardRequest struct {
+	LightPrice       int                    `json:"lightPrice" binding:"gte=0"`
+	DholPrice        int                    `json:"dholPrice" binding:"gte=0"`
+	GhodaBaggiPrice  int                    `json:"ghodaBaggiPrice" binding:"gte=0"`
+	GhodiPrice       int                    `json:"ghodiPrice" binding:"gte=0"`
+	FlowerCanonPrice int                    `json:"flowerCanonPrice" binding:"gte=0"`
+	DoliPrice        int                    `json:"doliPrice" binding:"gte=0"`
+	CitySurcharges   map[string]int         `json:"citySurcharges" binding:"dive,gte=0"`
+	DateSurcharges   []DateSurchargeRequest `json:"dateSurcharges" binding:"dive"`
+}
+
+// DateSurchargeRequest represents a date surcharge in a rate card request
+type DateSurchargeRequest struct {
+	Name    string `json:"name" binding:"required"`
+	From    string `json:"from" binding:"required"` // Will be parsed to time.Time
+	To      string `json:"to" binding:"required"`   // Will be parsed to time.Time
+	Percent int    `json:"percent" binding:"gte=0,lte=100"`
+	Amount  int    `json:"amount" binding:"gte=0"`
+}
+
+// PackageRequest represents the request for creating or updating a catalog package
+type PackageRequest struct {
+	Code        string `json:"code" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Musicians   int    `json:"musicians" binding:"gte=0"`
+	Lights      int    `json:"lights" binding:"gte=0"`
+	Dhols       int    `json:"dhols" binding:"gte=0"`
+	Hours       int    `json:"hours" binding:"gte=0"`
+	Price       int    `json:"price" binding:"gte=0"`
+	ActiveFrom  string `json:"activeFrom"` // Optional, YYYY-MM-DD
+	ActiveTo    string `json:"activeTo"`   // Optional, YYYY-MM-DD
+}
+
+// UpdateCapacityRequest represents the request for changing booking capacity
+type UpdateCapacityRequest struct {
+	TeamsPerDay   int            `json:"teamsPerDay" binding:"gte=0"`
+	TeamsPerSlot  int            `json:"teamsPerSlot" binding:"gte=0"`
+	DateOverrides map[string]int `json:"dateOverrides" binding:"dive,gte=0"`
+}
+
+// BookingStatusRequest represents the optional body of a booking status change
+type BookingStatusRequest struct {
+	Reason string `json:"reason"`
+}
+
+// UpdateBookingRequest represents the request for editing a booking. Only the
+// fields present in the request are changed.
+type UpdateBookingRequest struct {
+	Name            *string `json:"name" binding:"omitempty,min=1"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	AdditionalPhone *string `json:"additionalPhone"`
+	PackageType     *string `json:"packageType" binding:"omitempty,min=1"`
+	EventDate       *string `json:"date"` // YYYY-MM-DD
+	Venue           *string `json:"venue"`
+	City            *string `json:"city"`
+	Customization   *string `json:"customization"`
+	BandTime        *string `json:"bandTime" binding:"omitempty,oneof=morning afternoon evening night custom"`
+	CustomTimeSlot  *string `json:"customTimeSlot"`
+	NumberOfPeople  *int    `json:"numberOfPeople" binding:"omitempty,gte=0"`
+	NumberOfLights  *int    `json:"numberOfLights" binding:"omitempty,gte=0"`
+	NumberOfDhols   *int    `json:"numberOfDhols" binding:"omitempty,gte=0"`
+	GhodaBaggi      *int    `json:"ghodaBaggi" binding:"omitempty,gte=0"`
+	GhodiForBaraat  *bool   `json:"ghodiForBaraat"`
+	Fireworks       *bool   `json:"fireworks"`
+	FireworksAmount *int    `json:"fireworksAmount" binding:"omitempty,gte=0"`
+	FlowerCanon     *bool   `json:"flowerCanon"`
+	DoliForVidai    *bool   `json:"DoliForVidai"`
+}
+
+// RecordCustomerPaymentRequest represents the request for recording a customer payment
+type RecordCustomerPaymentRequest struct {
+	Amount    int    `json:"amount" binding:"required,gt=0"`
+	Method    string `json:"method" binding:"required,oneof=cash upi bank"`
+	Reference string `json:"reference"`
+	Date      string `json:"date"` // Optional, YYYY-MM-DD; defaults to today
+}
+
+// VoidCustomerPaymentRequest represents the request for voiding a customer payment
+type VoidCustomerPaymentRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// AssignCrewRequest represents the request for assigning an employee to a booking
+type AssignCrewRequest struct {
+	Username string   `json:"username" binding:"required"`
+	Role     string   `json:"role" binding:"required,oneof=dhol_player light_bearer band_master horse_handler"`
+	Wage     *float64 `json:"wage" binding:"omitempty,gte=0"` // Defaults to the wage rate of the role
+}
+
+// UpdateWageRatesRequest represents the request for changing the wage rates per crew role
+type UpdateWageRatesRequest struct {
+	Rates map[string]float64 `json:"rates" binding:"required,dive,keys,oneof=dhol_player light_bearer band_master horse_handler,endkeys,gte=0"`
+}
+
+// PutInventoryItemRequest represents the request for setting the stock of an inventory item
+type PutInventoryItemRequest struct {
+	Name            string `json:"name" binding:"required"`
+	QuantityOwned   int    `json:"quantityOwned" binding:"gte=0"`
+	InMaintenance   int    `json:"inMaintenance" binding:"gte=0,ltefield=QuantityOwned"`
+	MaintenanceNote string `json:"maintenanceNote"`
+}
+
+// UpdateTaxSettingsRequest represents the request for replacing the GST settings
+type UpdateTaxSettingsRequest struct {
+	GSTIN      string            `json:"gstin" binding:"required,len=15,alphanum,uppercase"`
+	LegalName  string            `json:"legalName" binding:"required"`
+	Address    string            `json:"address" binding:"required"`
+	State      string            `json:"state" binding:"required"`
+	SAC        string            `json:"sac" binding:"omitempty,numeric"`
+	Rate       float64           `json:"rate" binding:"gte=0,lte=28"`
+	CityStates map[string]string `json:"cityStates" binding:"required,dive,keys,required,endkeys,required"`
+}
+
+// IssueTaxInvoiceRequest represents the request for issuing a booking's GST invoice
+type IssueTaxInvoiceRequest struct {
+	GSTIN string `json:"gstin" binding:"omitempty,len=15,alphanum,uppercase"` // The customer's, for business customers
+}
+
+// IssueCreditNoteRequest represents the request for crediting a cancelled booking's invoice
+type IssueCreditNoteRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ImportBookingRequest represents one row of a bookings CSV import. Historical
+// bookings keep their recorded amount and advance instead of being re-priced.
+type ImportBookingRequest struct {
+	BookingID       string `json:"bookingId" binding:"omitempty,len=6,alphanum,uppercase"` // Generated if empty
+	Name            string `json:"name" binding:"required"`
+	Email           string `json:"email" binding:"omitempty,email"`
+	Phone           string `json:"phone" binding:"required"`
+	AdditionalPhone string `json:"additionalPhone"`
+	PackageType     string `json:"packageType" binding:"required"`
+	EventDate       string `json:"date" binding:"required,datetime=2006-01-02"`
+	Venue           string `json:"venue"`
+	City            string `json:"city" binding:"required"`
+	Customization   string `json:"customization"`
+	BandTime        string `json:"bandTime" binding:"omitempty,oneof=morning afternoon evening night custom"`
+	CustomTimeSlot  string `json:"customTimeSlot"`
+	NumberOfPeople  int    `json:"numberOfPeople" binding:"gte=0"`
+	NumberOfLights  int    `json:"numberOfLights" binding:"gte=0"`
+	NumberOfDhols   int    `json:"numberOfDhols" binding:"gte=0"`
+	GhodaBaggi      int    `json:"ghodaBaggi" binding:"gte=0"`
+	GhodiForBaraat  bool   `json:"ghodiForBaraat"`
+	Fireworks       bool   `json:"fireworks"`
+	FireworksAmount int    `json:"fireworksAmount" binding:"gte=0"`
+	FlowerCanon     bool   `json:"flowerCanon"`
+	DoliForVidai    bool   `json:"DoliForVidai"`
+	Amount          int    `json:"amount" binding:"gte=0"`
+	AdvancePayment  int    `json:"advancePayment" binding:"gte=0,ltefield=Amount"`
+	Status          string `json:"status" binding:"omitempty,oneof=enquiry confirmed completed cancelled"` // Defaults to confirmed
+	CreatedAt       string `json:"createdAt" binding:"omitempty,datetime=2006-01-02"`                      // Defaults to the import time
+}
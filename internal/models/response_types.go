@@ -23,9 +23,44 @@ type EmployeeResponse struct {
 	Username                 string             `json:"username"`
 	IsEmployee               bool               `json:"isEmployee"`
 	Payments                 []Payment          `json:"payments,omitempty"`
+	Balance                  *EmployeeBalance   `json:"balance,omitempty"`
+}
+
+// EmployeeBalance reconciles what an employee is owed with what they have been paid
+type EmployeeBalance struct {
+	OpeningBalance   float64 `json:"openingBalance"`   // TotalAmountToBePaid
+	EarnedFromEvents float64 `json:"earnedFromEvents"` // Sum of wages accrued for completed events
+	AmountOwed       float64 `json:"amountOwed"`
+	PaidInAdvance    float64 `json:"paidInAdvance"`    // TotalAmountPaidInAdvance
+	PaidFromPayments float64 `json:"paidFromPayments"` // Sum of payment records
+	PaidToDate       float64 `json:"paidToDate"`
+	RemainingDue     float64 `json:"remainingDue"`
+	Overpaid         float64 `json:"overpaid"`
+}
+
+// PayrollEntry is one employee's line in a payroll summary
+type PayrollEntry struct {
+	EmployeeID       primitive.ObjectID `json:"employeeId"`
+	Username         string             `json:"username"`
+	Name             string             `json:"name"`
+	EarnedInPeriod   float64            `json:"earnedInPeriod"`
+	EventsInPeriod   int                `json:"eventsInPeriod"`
+	PaidInPeriod     float64            `json:"paidInPeriod"`
+	PaymentsInPeriod int                `json:"paymentsInPeriod"`
+	Balance          EmployeeBalance    `json:"balance"` // As of the end of the period
 }
 
 // ErrorResponse represents error message in API responses
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
+
+// AssignmentResponse is a crew assignment with the event details a crew member needs
+type AssignmentResponse struct {
+	CrewAssignment
+	Venue         string `json:"venue"`
+	City          string `json:"city"`
+	BandTime      string `json:"bandTime,omitempty"`
+	PackageType   string `json:"packageType"`
+	BookingStatus string `json:"bookingStatus"`
+}
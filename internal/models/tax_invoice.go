@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tax document kinds, each numbered in its own series per financial year
+const (
+	TaxDocumentInvoice    = "invoice"
+	TaxDocumentCreditNote = "credit_note"
+)
+
+// taxDocumentPrefixes start the numbers of each kind of tax document
+var taxDocumentPrefixes = map[string]string{
+	TaxDocumentInvoice:    "INV",
+	TaxDocumentCreditNote: "CN",
+}
+
+// TaxSettings configures the GST charged on bookings. Booking amounts include tax.
+type TaxSettings struct {
+	GSTIN      string            `json:"gstin" bson:"gstin"`
+	LegalName  string            `json:"legalName" bson:"legal_name"`
+	Address    string            `json:"address" bson:"address"`
+	State      string            `json:"state" bson:"state"`            // State of registration
+	SAC        string            `json:"sac" bson:"sac"`                // Services accounting code printed on invoices
+	Rate       float64           `json:"rate" bson:"rate"`              // GST percentage: CGST and SGST are half each, IGST all of it
+	CityStates map[string]string `json:"cityStates" bson:"city_states"` // State of each event city, keyed by lower-case city
+	UpdatedBy  string            `json:"updatedBy" bson:"updated_by"`
+	UpdatedAt  time.Time         `json:"updatedAt" bson:"updated_at"`
+}
+
+// TaxParty identifies the supplier or recipient on a tax document
+type TaxParty struct {
+	Name    string `json:"name" bson:"name"`
+	GSTIN   string `json:"gstin,omitempty" bson:"gstin,omitempty"`
+	Address string `json:"address,omitempty" bson:"address,omitempty"`
+	State   string `json:"state" bson:"state"`
+	Phone   string `json:"phone,omitempty" bson:"phone,omitempty"`
+	Email   string `json:"email,omitempty" bson:"email,omitempty"`
+}
+
+// TaxInvoiceLine is a charge on a tax document, split into its taxable value and tax
+type TaxInvoiceLine struct {
+	Description  string  `json:"description" bson:"description"`
+	Quantity     int     `json:"quantity" bson:"quantity"`
+	TaxableValue float64 `json:"taxableValue" bson:"taxable_value"`
+	Amount       int     `json:"amount" bson:"amount"` // Including tax
+}
+
+// TaxInvoice is an issued GST invoice or credit note. It is a snapshot of the
+// booking and tax settings at issue time and is never changed afterwards; a
+// cancelled invoice is reversed by a credit note.
+type TaxInvoice struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Number         string             `json:"number" bson:"number"` // e.g. INV2627-00001
+	Kind           string             `json:"kind" bson:"kind"`
+	FinancialYear  string             `json:"financialYear" bson:"financial_year"` // e.g. 2026-27
+	Sequence       int                `json:"sequence" bson:"sequence"`            // Position in the series of its kind and financial year
+	BookingID      string             `json:"bookingId" bson:"booking_id"`
+	OriginalNumber string             `json:"originalNumber,omitempty" bson:"original_number,omitempty"` // Invoice reversed by a credit note
+	Reason         string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	Supplier       TaxParty           `json:"supplier" bson:"supplier"`
+	Recipient      TaxParty           `json:"recipient" bson:"recipient"`
+	PlaceOfSupply  string             `json:"placeOfSupply" bson:"place_of_supply"`
+	SAC            string             `json:"sac,omitempty" bson:"sac,omitempty"`
+	EventDate      time.Time          `json:"eventDate" bson:"event_date"`
+	Lines          []TaxInvoiceLine   `json:"lines" bson:"lines"`
+	TaxableValue   float64            `json:"taxableValue" bson:"taxable_value"`
+	CGSTRate       float64            `json:"cgstRate" bson:"cgst_rate"`
+	CGST           float64            `json:"cgst" bson:"cgst"`
+	SGSTRate       float64            `json:"sgstRate" bson:"sgst_rate"`
+	SGST           float64            `json:"sgst" bson:"sgst"`
+	IGSTRate       float64            `json:"igstRate" bson:"igst_rate"`
+	IGST           float64            `json:"igst" bson:"igst"`
+	Total          int                `json:"total" bson:"total"`
+	IssuedBy       string             `json:"issuedBy" bson:"issued_by"`
+	IssuedAt       time.Time          `json:"issuedAt" bson:"issued_at"`
+}
+
+// Series names the numbering series the document belongs to
+func (d *TaxInvoice) Series() string {
+	return d.Kind + ":" + d.FinancialYear
+}
+
+// SetSequence numbers the document as the given position in its series
+func (d *TaxInvoice) SetSequence(sequence int) {
+	d.Sequence = sequence
+	d.Number = fmt.Sprintf("%s%s-%05d", taxDocumentPrefixes[d.Kind], strings.ReplaceAll(d.FinancialYear[2:], "-", ""), sequence)
+}
+
+// IsTaxDocumentKind reports whether kind is one of the tax document kinds
+func IsTaxDocumentKind(kind string) bool {
+	_, ok := taxDocumentPrefixes[kind]
+	return ok
+}
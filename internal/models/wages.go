@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WageRates sets what a crew member earns per event in each role
+type WageRates struct {
+	Rates     map[string]float64 `json:"rates" bson:"rates"` // Keyed by crew role
+	UpdatedBy string             `json:"updatedBy" bson:"updated_by"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// Earning is the wage an employee accrued for working a completed event. Earnings
+// are kept when the booking is deleted so the employee's statement stays intact.
+type Earning struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	EmployeeID primitive.ObjectID `json:"employeeId" bson:"employee_id"`
+	BookingID  string             `json:"bookingId" bson:"booking_id"`
+	Role       string             `json:"role" bson:"role"`
+	EventDate  time.Time          `json:"eventDate" bson:"event_date"`
+	Amount     float64            `json:"amount" bson:"amount"`
+	AccruedAt  time.Time          `json:"accruedAt" bson:"accrued_at"`
+}
+
+// NewEarning accrues the wage agreed for an assignment
+func NewEarning(assignment *CrewAssignment, at time.Time) Earning {
+	return Earning{
+		EmployeeID: assignment.EmployeeID,
+		BookingID:  assignment.BookingID,
+		Role:       assignment.Role,
+		EventDate:  assignment.EventDate,
+		Amount:     assignment.Wage,
+		AccruedAt:  at,
+	}
+}
+
+// Statement entry types
+const (
+	StatementEarning = "earning"
+	StatementPayment = "payment"
+)
+
+// StatementEntry is one line of an employee statement. Earned amounts increase
+// the balance owed to the employee and paid amounts reduce it.
+type StatementEntry struct {
+	Date      time.Time `json:"date"`
+	Type      string    `json:"type"`
+	BookingID string    `json:"bookingId,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	Earned    float64   `json:"earned"`
+	Paid      float64   `json:"paid"`
+	Balance   float64   `json:"balance"` // Running amount owed to the employee
+}
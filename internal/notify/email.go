@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// EmailProvider delivers messages as plain-text email through an SMTP server
+type EmailProvider struct {
+	Addr     string // host:port
+	Username string // Authentication is skipped when empty
+	Password string
+	From     string
+}
+
+// emailProviderFromEnv configures email from the SMTPHost, SMTPPort (587 by default),
+// SMTPUsername, SMTPPassword and SMTPFrom environment variables, logging messages
+// instead when SMTPHost is not set
+func emailProviderFromEnv() (Provider, error) {
+	host := os.Getenv("SMTPHost")
+	if host == "" {
+		return LogProvider{}, nil
+	}
+	port := os.Getenv("SMTPPort")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTPFrom")
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("SMTPFrom must be an email address: %w", err)
+	}
+	return EmailProvider{
+		Addr:     net.JoinHostPort(host, port),
+		Username: os.Getenv("SMTPUsername"),
+		Password: os.Getenv("SMTPPassword"),
+		From:     from,
+	}, nil
+}
+
+// Send emails the message. The SMTP client does not take a context, so ctx is
+// not honoured once the connection is made.
+func (p EmailProvider) Send(ctx context.Context, message Message) error {
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid email address %q: %w", message.To, err)
+	}
+	from, err := mail.ParseAddress(p.From)
+	if err != nil {
+		return err
+	}
+	if strings.ContainsAny(message.Subject, "\r\n") {
+		return errors.New("email subject contains a line break")
+	}
+
+	var msg strings.Builder
+	msg.WriteString("From: " + from.String() + "\r\n")
+	msg.WriteString("To: " + to.String() + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n") + "\r\n")
+
+	var auth smtp.Auth
+	if p.Username != "" {
+		host, _, _ := net.SplitHostPort(p.Addr)
+		auth = smtp.PlainAuth("", p.Username, p.Password, host)
+	}
+	return smtp.SendMail(p.Addr, auth, from.Address, []string{to.Address}, []byte(msg.String()))
+}
@@ -0,0 +1,210 @@
+// Package notify sends templated booking notifications over SMS, email and
+// WhatsApp. Messages are rendered when an event happens and queued in an outbox;
+// a dispatcher delivers them through the configured providers and retries failed
+// sends with backoff, so a provider outage delays messages instead of losing them.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+const (
+	// batchSize is the number of notifications claimed from the outbox at a time
+	batchSize = 20
+	// lease is how long a claimed notification is reserved for its dispatcher. If the
+	// dispatcher stops before recording the outcome, the notification is sent again
+	// after the lease.
+	lease = 5 * time.Minute
+	// sendTimeout bounds a single delivery attempt
+	sendTimeout = 30 * time.Second
+	// maxAttempts is the number of attempts after which a notification is marked failed
+	maxAttempts = 8
+	// maxBackoff caps the delay between attempts
+	maxBackoff = 6 * time.Hour
+)
+
+// channels lists every channel, in the order notifications are queued
+var channels = []string{models.ChannelSMS, models.ChannelEmail, models.ChannelWhatsApp}
+
+// Message is a rendered notification ready to be delivered
+type Message struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Subject string `json:"subject,omitempty"` // Used by email only
+	Body    string `json:"body"`
+}
+
+// Provider delivers messages on one channel
+type Provider interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Recipient holds the addresses a notification can be sent to. Channels whose
+// address is empty are skipped.
+type Recipient struct {
+	Phone string
+	Email string
+}
+
+// address returns the recipient's address on a channel
+func (r Recipient) address(channel string) string {
+	if channel == models.ChannelEmail {
+		return r.Email
+	}
+	return r.Phone
+}
+
+// Notifier queues notifications and delivers them through its providers
+type Notifier struct {
+	outbox    storage.NotificationRepository
+	band      string
+	providers map[string]Provider // Keyed by channel; channels without a provider are not used
+	wake      chan struct{}
+}
+
+// New returns a Notifier that queues notifications in outbox and signs them with
+// the band's name
+func New(outbox storage.NotificationRepository, band string, providers map[string]Provider) *Notifier {
+	return &Notifier{
+		outbox:    outbox,
+		band:      band,
+		providers: providers,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Notify renders the event's template and queues a message to the recipient on
+// every channel that has a provider and an address. Delivery happens in Run.
+func (n *Notifier) Notify(ctx context.Context, event string, to Recipient, data Data) error {
+	return n.NotifyOnce(ctx, "", event, to, data)
+}
+
+// NotifyOnce is Notify for messages that must not be repeated: the message on
+// each channel is queued only if none was queued before under the same key, even
+// by another process. An empty key queues the message unconditionally.
+func (n *Notifier) NotifyOnce(ctx context.Context, key, event string, to Recipient, data Data) error {
+	data.Band = n.band
+	subject, body, err := render(event, data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var queued []models.Notification
+	for _, channel := range channels {
+		if _, ok := n.providers[channel]; !ok || to.address(channel) == "" {
+			continue
+		}
+		notification := models.Notification{
+			Event:         event,
+			Channel:       channel,
+			To:            to.address(channel),
+			Subject:       subject,
+			Body:          body,
+			BookingID:     data.BookingID,
+			Status:        models.NotificationPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if key != "" {
+			notification.Key = key + ":" + channel
+		}
+		queued = append(queued, notification)
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+	if err := n.outbox.Enqueue(ctx, queued); err != nil {
+		return err
+	}
+
+	// Deliver promptly rather than at the next poll
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers queued notifications until ctx is cancelled, checking the outbox
+// every interval and whenever a notification is queued
+func (n *Notifier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n.Dispatch(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-n.wake:
+		}
+	}
+}
+
+// Dispatch attempts every notification that is due
+func (n *Notifier) Dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now()
+		batch, err := n.outbox.Claim(ctx, now, now.Add(lease), batchSize)
+		if err != nil {
+			log.Printf("Failed to read notification outbox: %v", err)
+			return
+		}
+		for i := range batch {
+			n.send(ctx, &batch[i])
+		}
+		if len(batch) < batchSize {
+			return
+		}
+	}
+}
+
+// send makes one delivery attempt of a claimed notification and records the outcome
+func (n *Notifier) send(ctx context.Context, notification *models.Notification) {
+	err := fmt.Errorf("no provider is configured for %s", notification.Channel)
+	if provider, ok := n.providers[notification.Channel]; ok {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = provider.Send(sendCtx, Message{
+			Channel: notification.Channel,
+			To:      notification.To,
+			Subject: notification.Subject,
+			Body:    notification.Body,
+		})
+		cancel()
+	}
+
+	now := time.Now()
+	if err == nil {
+		if err := n.outbox.MarkSent(ctx, notification.ID, now); err != nil {
+			log.Printf("Failed to record delivery of notification %s: %v", notification.ID.Hex(), err)
+		}
+		return
+	}
+
+	var retryAt time.Time
+	if notification.Attempts < maxAttempts {
+		retryAt = now.Add(backoff(notification.Attempts))
+	}
+	log.Printf("Failed to send %s notification %s to %s (attempt %d): %v",
+		notification.Channel, notification.ID.Hex(), notification.To, notification.Attempts, err)
+	if err := n.outbox.MarkFailed(ctx, notification.ID, err.Error(), retryAt); err != nil {
+		log.Printf("Failed to record failure of notification %s: %v", notification.ID.Hex(), err)
+	}
+}
+
+// backoff returns the delay before retrying after the given number of attempts:
+// a minute after the first, doubling each time up to maxBackoff
+func backoff(attempts int) time.Duration {
+	if attempts > 16 {
+		return maxBackoff
+	}
+	return min(time.Minute<<max(attempts-1, 0), maxBackoff)
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/sms"
+)
+
+// SMSProvider delivers messages through an SMS sender
+type SMSProvider struct {
+	Sender sms.Sender
+}
+
+// Send texts the message body
+func (p SMSProvider) Send(ctx context.Context, message Message) error {
+	return p.Sender.Send(ctx, message.To, message.Body)
+}
+
+// LogProvider only writes messages to the log, for development and channels
+// without a configured provider
+type LogProvider struct{}
+
+// Send logs the message instead of delivering it
+func (LogProvider) Send(ctx context.Context, message Message) error {
+	log.Printf("%s to %s: %s", strings.ToUpper(message.Channel), message.To, message.Body)
+	return nil
+}
+
+// FileProvider appends messages to a file as JSON lines instead of delivering
+// them, for local use
+type FileProvider struct {
+	Path string
+	mu   *sync.Mutex
+}
+
+// NewFileProvider returns a FileProvider writing to path
+func NewFileProvider(path string) FileProvider {
+	return FileProvider{Path: path, mu: &sync.Mutex{}}
+}
+
+// Send appends the message to the file
+func (p FileProvider) Send(ctx context.Context, message Message) error {
+	line, err := json.Marshal(struct {
+		Time time.Time `json:"time"`
+		Message
+	}{time.Now(), message})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f, err := os.OpenFile(p.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ProvidersFromEnv returns a provider for each channel named by the NotifyChannels
+// environment variable (a comma-separated list, sms by default). If NotifyFile is
+// set every channel writes to that file. Otherwise SMS goes through sender, email
+// through the SMTP server in SMTPHost and WhatsApp through the Cloud API account
+// in WhatsAppToken; email and WhatsApp are logged if their provider is not set up.
+func ProvidersFromEnv(sender sms.Sender) (map[string]Provider, error) {
+	names := os.Getenv("NotifyChannels")
+	if names == "" {
+		names = models.ChannelSMS
+	}
+	file := os.Getenv("NotifyFile")
+
+	var fileProvider Provider
+	if file != "" {
+		fileProvider = NewFileProvider(file)
+	}
+
+	providers := make(map[string]Provider)
+	for _, channel := range strings.Split(names, ",") {
+		channel = strings.ToLower(strings.TrimSpace(channel))
+		if channel == "" {
+			continue
+		}
+
+		var provider Provider
+		var err error
+		switch {
+		case channel != models.ChannelSMS && channel != models.ChannelEmail && channel != models.ChannelWhatsApp:
+			return nil, fmt.Errorf("unknown notification channel %q in NotifyChannels, expected sms, email or whatsapp", channel)
+		case fileProvider != nil:
+			provider = fileProvider
+		case channel == models.ChannelSMS:
+			provider = SMSProvider{Sender: sender}
+		case channel == models.ChannelEmail:
+			provider, err = emailProviderFromEnv()
+		case channel == models.ChannelWhatsApp:
+			provider, err = whatsAppProviderFromEnv()
+		}
+		if err != nil {
+			return nil, err
+		}
+		providers[channel] = provider
+	}
+	return providers, nil
+}
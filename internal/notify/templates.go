@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/modernband/booking/internal/invoice"
+	"github.com/modernband/booking/internal/models"
+)
+
+// Booking events customers and crew are notified of
+const (
+	EventBookingReceived  = "booking_received"
+	EventBookingConfirmed = "booking_confirmed"
+	EventPaymentReceived  = "payment_received"
+	EventReminder         = "reminder"
+	EventBookingCancelled = "booking_cancelled"
+	EventCrewReminder     = "crew_reminder"
+)
+
+// Data is what message templates are rendered with
+type Data struct {
+	Band       string // Set by the Notifier
+	Name       string
+	BookingID  string
+	EventDate  string
+	Venue      string
+	TimeSlot   string
+	Amount     string
+	BalanceDue string
+	Payment    string // Amount of the payment received
+	Method     string // How the payment was made
+	Reason     string // Why the booking was cancelled
+	Days       int    // Days left until the event
+	Role       string // Crew member's role at the event
+}
+
+// BookingData returns the template data describing a booking. Its balance must
+// have been computed.
+func BookingData(booking *models.Booking) Data {
+	venue := booking.City
+	if booking.Venue != "" {
+		venue = booking.Venue + ", " + booking.City
+	}
+	timeSlot := booking.BandTime
+	if timeSlot == "" || strings.EqualFold(timeSlot, "custom") {
+		timeSlot = booking.CustomTimeSlot
+	}
+	return Data{
+		Name:       booking.Name,
+		BookingID:  booking.BookingID,
+		EventDate:  booking.EventDate.UTC().Format("02 Jan 2006"),
+		Venue:      venue,
+		TimeSlot:   timeSlot,
+		Amount:     invoice.FormatAmount(booking.Amount),
+		BalanceDue: invoice.FormatAmount(max(booking.BalanceDue, 0)),
+	}
+}
+
+// PaymentData returns the template data describing a payment towards a booking
+func PaymentData(booking *models.Booking, payment *models.CustomerPayment) Data {
+	data := BookingData(booking)
+	data.Payment = invoice.FormatAmount(payment.Amount)
+	data.Method = paymentMethods[payment.Method]
+	return data
+}
+
+// CrewData returns the template data describing a crew member's assignment to a booking
+func CrewData(booking *models.Booking, employee *models.Employee, assignment *models.CrewAssignment) Data {
+	data := BookingData(booking)
+	data.Name = employee.Name
+	data.Role = strings.ReplaceAll(assignment.Role, "_", " ")
+	return data
+}
+
+// paymentMethods names payment methods in messages
+var paymentMethods = map[string]string{
+	models.PaymentMethodCash: "cash",
+	models.PaymentMethodUPI:  "UPI",
+	models.PaymentMethodBank: "bank transfer",
+}
+
+// messageTemplate renders the subject and body of one event's messages. SMS and
+// WhatsApp messages carry the body only.
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// newTemplate parses an event's message template
+func newTemplate(event, subject, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New(event + ".subject").Parse(subject)),
+		body:    template.Must(template.New(event + ".body").Parse(body)),
+	}
+}
+
+// templates holds the message template of each event
+var templates = map[string]messageTemplate{
+	EventBookingReceived: newTemplate(EventBookingReceived,
+		`Booking request {{.BookingID}} received`,
+		`Dear {{.Name}}, we have received your booking request with {{.Band}} (ID: {{.BookingID}}) for {{.EventDate}}. `+
+			`We will contact you shortly to confirm it.`),
+	EventBookingConfirmed: newTemplate(EventBookingConfirmed,
+		`Booking {{.BookingID}} confirmed`,
+		`Dear {{.Name}}, your booking with {{.Band}} (ID: {{.BookingID}}) for {{.EventDate}} at {{.Venue}} has been confirmed! `+
+			`We look forward to making your event special.`),
+	EventPaymentReceived: newTemplate(EventPaymentReceived,
+		`Payment received for booking {{.BookingID}}`,
+		`Dear {{.Name}}, we have received {{.Payment}}{{if .Method}} by {{.Method}}{{end}} for your booking {{.BookingID}}. `+
+			`Balance due: {{.BalanceDue}}. Thank you!`),
+	EventReminder: newTemplate(EventReminder,
+		`Reminder: your event on {{.EventDate}}`,
+		`Dear {{.Name}}, a reminder that {{.Band}} will be with you {{if eq .Days 1}}tomorrow{{else}}in {{.Days}} days{{end}}, `+
+			`{{.EventDate}}{{if .TimeSlot}} ({{.TimeSlot}}){{end}}, at {{.Venue}} (booking {{.BookingID}}).`+
+			`{{if ne .BalanceDue "Rs. 0"}} Balance due: {{.BalanceDue}}.{{end}}`),
+	EventBookingCancelled: newTemplate(EventBookingCancelled,
+		`Booking {{.BookingID}} cancelled`,
+		`Dear {{.Name}}, your booking with {{.Band}} (ID: {{.BookingID}}) for {{.EventDate}} has been cancelled.`+
+			`{{if .Reason}} Reason: {{.Reason}}.{{end}} Please contact us if you have any questions.`),
+	EventCrewReminder: newTemplate(EventCrewReminder,
+		`Duty tomorrow: booking {{.BookingID}}`,
+		`Hi {{.Name}}, you are on duty as {{.Role}} tomorrow, {{.EventDate}}{{if .TimeSlot}} ({{.TimeSlot}}){{end}}, `+
+			`at {{.Venue}} for booking {{.BookingID}}. - {{.Band}}`),
+}
+
+// render renders the subject and body of an event's message
+func render(event string, data Data) (string, string, error) {
+	t, ok := templates[event]
+	if !ok {
+		return "", "", fmt.Errorf("unknown notification event %q", event)
+	}
+	var subject, body strings.Builder
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+	return subject.String(), body.String(), nil
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// whatsAppAPI is the WhatsApp Business Cloud API endpoint messages are posted to
+const whatsAppAPI = "https://graph.facebook.com/v19.0"
+
+// WhatsAppProvider delivers messages as WhatsApp text messages through the
+// WhatsApp Business Cloud API
+type WhatsAppProvider struct {
+	Token         string
+	PhoneNumberID string // The business number messages are sent from
+	CountryCode   string // Prefixed to numbers given without one
+	Client        *http.Client
+}
+
+// whatsAppProviderFromEnv configures WhatsApp from the WhatsAppToken,
+// WhatsAppPhoneNumberID and WhatsAppCountryCode (91 by default) environment
+// variables, logging messages instead when WhatsAppToken is not set
+func whatsAppProviderFromEnv() (Provider, error) {
+	token := os.Getenv("WhatsAppToken")
+	if token == "" {
+		return LogProvider{}, nil
+	}
+	phoneNumberID := os.Getenv("WhatsAppPhoneNumberID")
+	if phoneNumberID == "" {
+		return nil, errors.New("WhatsAppPhoneNumberID must be set with WhatsAppToken")
+	}
+	countryCode := os.Getenv("WhatsAppCountryCode")
+	if countryCode == "" {
+		countryCode = "91"
+	}
+	return WhatsAppProvider{
+		Token:         token,
+		PhoneNumberID: phoneNumberID,
+		CountryCode:   countryCode,
+		Client:        http.DefaultClient,
+	}, nil
+}
+
+// internationalNumber converts a phone number to the digits-only international
+// form the API expects
+func (p WhatsAppProvider) internationalNumber(phone string) string {
+	international := strings.HasPrefix(strings.TrimSpace(phone), "+")
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if !international && len(digits) == 10 {
+		digits = p.CountryCode + digits
+	}
+	return digits
+}
+
+// Send posts the message body as a text message
+func (p WhatsAppProvider) Send(ctx context.Context, message Message) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                p.internationalNumber(message.To),
+		"type":              "text",
+		"text":              map[string]string{"body": message.Body},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, whatsAppAPI+"/"+p.PhoneNumberID+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("WhatsApp API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package pricing computes itemized booking quotes from the rate card in effect.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+)
+
+var (
+	// ErrUnknownPackage is returned when the booking's package type is not in the catalog
+	ErrUnknownPackage = errors.New("unknown package type")
+	// ErrPackageUnavailable is returned when the package cannot be booked for the event date
+	ErrPackageUnavailable = errors.New("package is not available on the event date")
+	// ErrNegativeQuantity is returned when an add-on quantity or the fireworks amount is negative
+	ErrNegativeQuantity = errors.New("quantities and amounts must not be negative")
+)
+
+// dateLayout is used to compare event dates with surcharge ranges by calendar day
+const dateLayout = "2006-01-02"
+
+// IsAvailable reports whether a package can be booked for an event on the given date
+func IsAvailable(pkg *models.Package, date time.Time) bool {
+	day := date.Format(dateLayout)
+	if pkg.ActiveFrom != nil && day < pkg.ActiveFrom.Format(dateLayout) {
+		return false
+	}
+	if pkg.ActiveTo != nil && day > pkg.ActiveTo.Format(dateLayout) {
+		return false
+	}
+	return true
+}
+
+// CalculateQuote prices a booking for a catalog package against a rate card. Lights
+// and dhols included in the package are not charged again as add-ons.
+func CalculateQuote(card *models.RateCard, pkg *models.Package, booking *models.Booking) (*models.Quote, error) {
+	if booking.NumberOfLights < 0 || booking.NumberOfDhols < 0 || booking.GhodaBaggi < 0 || booking.FireworksAmount < 0 {
+		return nil, ErrNegativeQuantity
+	}
+	if !IsAvailable(pkg, booking.EventDate) {
+		return nil, fmt.Errorf("%w: %q", ErrPackageUnavailable, pkg.Code)
+	}
+
+	quote := &models.Quote{RateCardID: card.ID}
+	add := func(code, description string, quantity, unitPrice int) {
+		if quantity <= 0 {
+			return
+		}
+		quote.Items = append(quote.Items, models.QuoteItem{
+			Code:        code,
+			Description: description,
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			Amount:      quantity * unitPrice,
+		})
+	}
+
+	// Package and add-ons
+	add("package", "Package: "+pkg.Name, 1, pkg.Price)
+	add("lights", "Extra lights", booking.NumberOfLights-pkg.Lights, card.LightPrice)
+	add("dhols", "Extra dhols", booking.NumberOfDhols-pkg.Dhols, card.DholPrice)
+	add("ghoda_baggi", "Ghoda baggi", booking.GhodaBaggi, card.GhodaBaggiPrice)
+	add("ghodi", "Ghodi for baraat", boolQuantity(booking.GhodiForBaraat), card.GhodiPrice)
+	add("flower_canon", "Flower canon", boolQuantity(booking.FlowerCanon), card.FlowerCanonPrice)
+	add("doli", "Doli for vidai", boolQuantity(booking.DoliForVidai), card.DoliPrice)
+	if booking.Fireworks {
+		// Fireworks are priced by the amount the customer chooses to spend
+		add("fireworks", "Fireworks", 1, booking.FireworksAmount)
+	}
+
+	for _, item := range quote.Items {
+		quote.Subtotal += item.Amount
+	}
+	quote.Total = quote.Subtotal
+
+	// City surcharge
+	if surcharge := card.CitySurcharges[strings.ToLower(strings.TrimSpace(booking.City))]; surcharge > 0 {
+		add("city_surcharge", "City surcharge: "+booking.City, 1, surcharge)
+		quote.Total += surcharge
+	}
+
+	// Date surcharges
+	eventDay := booking.EventDate.Format(dateLayout)
+	for _, ds := range card.DateSurcharges {
+		if eventDay < ds.From.Format(dateLayout) || eventDay > ds.To.Format(dateLayout) {
+			continue
+		}
+		amount := ds.Amount + quote.Subtotal*ds.Percent/100
+		add("date_surcharge", "Date surcharge: "+ds.Name, 1, amount)
+		quote.Total += amount
+	}
+
+	return quote, nil
+}
+
+// boolQuantity converts an add-on flag to a quantity
+func boolQuantity(selected bool) int {
+	if selected {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,145 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+)
+
+func TestCalculateQuote(t *testing.T) {
+	card := &models.RateCard{
+		LightPrice:       100,
+		DholPrice:        500,
+		GhodaBaggiPrice:  3000,
+		GhodiPrice:       2000,
+		FlowerCanonPrice: 1500,
+		DoliPrice:        2500,
+		CitySurcharges:   map[string]int{"nashik": 1000},
+		DateSurcharges: []models.DateSurcharge{{
+			Name:    "Peak",
+			From:    time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+			To:      time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+			Percent: 10,
+			Amount:  200,
+		}},
+	}
+	pkg := &models.Package{Code: "basic", Name: "Basic", Price: 10000, Lights: 4, Dhols: 2}
+	offPeak := time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		booking  models.Booking
+		subtotal int
+		total    int
+		err      error
+	}{
+		{
+			name:     "package only",
+			booking:  models.Booking{EventDate: offPeak, City: "Pune"},
+			subtotal: 10000,
+			total:    10000,
+		},
+		{
+			name:     "zero add-ons",
+			booking:  models.Booking{EventDate: offPeak, NumberOfLights: 0, NumberOfDhols: 0, GhodaBaggi: 0, Fireworks: true},
+			subtotal: 10000,
+			total:    10000,
+		},
+		{
+			name:     "included lights and dhols are not charged",
+			booking:  models.Booking{EventDate: offPeak, NumberOfLights: 4, NumberOfDhols: 2},
+			subtotal: 10000,
+			total:    10000,
+		},
+		{
+			name: "extras and add-ons",
+			booking: models.Booking{
+				EventDate: offPeak, NumberOfLights: 6, NumberOfDhols: 3, GhodaBaggi: 1,
+				GhodiForBaraat: true, FlowerCanon: true, DoliForVidai: true,
+				Fireworks: true, FireworksAmount: 5000,
+			},
+			subtotal: 10000 + 2*100 + 500 + 3000 + 2000 + 1500 + 2500 + 5000,
+			total:    10000 + 2*100 + 500 + 3000 + 2000 + 1500 + 2500 + 5000,
+		},
+		{
+			name:     "fireworks amount without fireworks",
+			booking:  models.Booking{EventDate: offPeak, FireworksAmount: 5000},
+			subtotal: 10000,
+			total:    10000,
+		},
+		{
+			name:     "city surcharge",
+			booking:  models.Booking{EventDate: offPeak, City: " Nashik "},
+			subtotal: 10000,
+			total:    11000,
+		},
+		{
+			name:     "date surcharge on the subtotal",
+			booking:  models.Booking{EventDate: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), NumberOfDhols: 4},
+			subtotal: 11000,
+			total:    11000 + 200 + 1100,
+		},
+		{
+			name:    "negative lights",
+			booking: models.Booking{EventDate: offPeak, NumberOfLights: -1},
+			err:     ErrNegativeQuantity,
+		},
+		{
+			name:    "negative dhols",
+			booking: models.Booking{EventDate: offPeak, NumberOfDhols: -5},
+			err:     ErrNegativeQuantity,
+		},
+		{
+			name:    "negative ghoda baggi",
+			booking: models.Booking{EventDate: offPeak, GhodaBaggi: -1},
+			err:     ErrNegativeQuantity,
+		},
+		{
+			name:    "negative fireworks amount",
+			booking: models.Booking{EventDate: offPeak, Fireworks: true, FireworksAmount: -100},
+			err:     ErrNegativeQuantity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := CalculateQuote(card, pkg, &tt.booking)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if quote.Subtotal != tt.subtotal || quote.Total != tt.total {
+				t.Errorf("subtotal, total = %d, %d, want %d, %d", quote.Subtotal, quote.Total, tt.subtotal, tt.total)
+			}
+			sum := 0
+			for _, item := range quote.Items {
+				if item.Quantity <= 0 || item.Amount != item.Quantity*item.UnitPrice {
+					t.Errorf("item %+v is not a positive quantity at its unit price", item)
+				}
+				sum += item.Amount
+			}
+			if sum != quote.Total {
+				t.Errorf("items add up to %d, want the total %d", sum, quote.Total)
+			}
+		})
+	}
+}
+
+func TestCalculateQuoteUnavailablePackage(t *testing.T) {
+	from := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	pkg := &models.Package{Code: "new", Price: 100, ActiveFrom: &from}
+
+	booking := models.Booking{EventDate: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)}
+	if _, err := CalculateQuote(&models.RateCard{}, pkg, &booking); !errors.Is(err, ErrPackageUnavailable) {
+		t.Fatalf("error = %v, want %v", err, ErrPackageUnavailable)
+	}
+
+	booking.EventDate = from
+	if _, err := CalculateQuote(&models.RateCard{}, pkg, &booking); err != nil {
+		t.Fatalf("error = %v on the first available day", err)
+	}
+}
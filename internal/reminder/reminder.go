@@ -0,0 +1,167 @@
+// Package reminder reminds customers of their upcoming events and crew of the
+// events they are on duty at. Reminders are queued through the notifier under a
+// key naming the booking, its event day and the reminder, so each one is queued
+// once however often, and in however many processes, the check runs. A booking
+// moved to another day is reminded afresh.
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modernband/booking/internal/clock"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/notify"
+	"github.com/modernband/booking/internal/storage"
+)
+
+const (
+	// customerHour is the local hour customers are reminded at
+	customerHour = 10
+	// crewHour is the local hour, the evening before the event, crew are reminded at
+	crewHour = 18
+	// dayLayout formats event days in reminder keys
+	dayLayout = "2006-01-02"
+)
+
+// Scheduler queues reminders for confirmed bookings as they fall due. Times of
+// day are reckoned in Indian time; event days are the calendar days of the event dates.
+type Scheduler struct {
+	store    *storage.Store
+	notifier *notify.Notifier
+	days     []int // Days before the event customers are reminded, furthest first
+}
+
+// New returns a Scheduler reminding customers the given numbers of days before
+// their event
+func New(store *storage.Store, notifier *notify.Notifier, days []int) *Scheduler {
+	days = slices.Clone(days)
+	slices.Sort(days)
+	slices.Reverse(days)
+	return &Scheduler{store: store, notifier: notifier, days: slices.Compact(days)}
+}
+
+// DaysFromEnv reads the days before an event customers are reminded from the
+// ReminderDays environment variable, a comma-separated list that defaults to 7,1
+func DaysFromEnv() ([]int, error) {
+	value := os.Getenv("ReminderDays")
+	if value == "" {
+		return []int{7, 1}, nil
+	}
+	var days []int
+	for _, field := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n < 1 || n > 60 {
+			return nil, fmt.Errorf("invalid ReminderDays %q: each entry must be a number of days from 1 to 60", value)
+		}
+		days = append(days, n)
+	}
+	return days, nil
+}
+
+// SendDue queues the reminders due at now that have not been queued yet and
+// returns the number of upcoming bookings checked. A customer whose reminders
+// were missed, e.g. while the service was down, gets only the latest one due.
+// Failures for one booking do not stop the others.
+func (s *Scheduler) SendDue(ctx context.Context, now time.Time) (int, error) {
+	local := now.In(clock.IndianTime)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	horizon := 1
+	if len(s.days) > 0 {
+		horizon = max(horizon, s.days[0])
+	}
+
+	// Events from tomorrow up to the furthest reminder
+	bookings, _, err := s.store.Bookings.List(ctx, storage.BookingFilter{
+		Status: models.BookingStatusConfirmed,
+		From:   today.AddDate(0, 0, 1),
+		Before: today.AddDate(0, 0, horizon+1),
+	}, storage.BookingPage{Sort: storage.SortEventDate})
+	if err != nil {
+		return 0, err
+	}
+	pointers := make([]*models.Booking, len(bookings))
+	for i := range bookings {
+		pointers[i] = &bookings[i]
+	}
+	if err := s.store.AttachBalances(ctx, pointers...); err != nil {
+		return 0, err
+	}
+
+	for i := range bookings {
+		booking := &bookings[i]
+		if err := s.remindCustomer(ctx, booking, today, now); err != nil {
+			log.Printf("Failed to queue reminder for booking %s: %v", booking.BookingID, err)
+		}
+		if err := s.remindCrew(ctx, booking, now); err != nil {
+			log.Printf("Failed to queue crew reminders for booking %s: %v", booking.BookingID, err)
+		}
+	}
+	return len(bookings), nil
+}
+
+// eventDay returns midnight Indian time at the start of a booking's event day
+func eventDay(booking *models.Booking) time.Time {
+	date := booking.EventDate.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, clock.IndianTime)
+}
+
+// remindCustomer queues the latest customer reminder due at now, if any. Reminders
+// due before the booking was made are skipped.
+func (s *Scheduler) remindCustomer(ctx context.Context, booking *models.Booking, today, now time.Time) error {
+	day := eventDay(booking)
+	due := 0
+	for _, days := range s.days {
+		sendAt := day.AddDate(0, 0, -days).Add(customerHour * time.Hour)
+		if !sendAt.After(now) && !sendAt.Before(booking.CreatedAt) {
+			due = days
+		}
+	}
+	if due == 0 {
+		return nil
+	}
+
+	data := notify.BookingData(booking)
+	data.Days = int(booking.EventDate.UTC().Truncate(24*time.Hour).Sub(today) / (24 * time.Hour))
+	key := fmt.Sprintf("reminder:%s:%s:%dd", booking.BookingID, day.Format(dayLayout), due)
+	to := notify.Recipient{Phone: booking.Phone, Email: booking.Email}
+	return s.notifier.NotifyOnce(ctx, key, notify.EventReminder, to, data)
+}
+
+// remindCrew queues a reminder to each crew member of a booking from the evening
+// before its event
+func (s *Scheduler) remindCrew(ctx context.Context, booking *models.Booking, now time.Time) error {
+	day := eventDay(booking)
+	if now.Before(day.AddDate(0, 0, -1).Add(crewHour * time.Hour)) {
+		return nil
+	}
+
+	assignments, err := s.store.Assignments.ListByBooking(ctx, booking.BookingID)
+	if err != nil {
+		return err
+	}
+	for i := range assignments {
+		assignment := &assignments[i]
+		employee, err := s.store.Employees.FindByUsername(ctx, assignment.Username)
+		if err == storage.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf("crew-reminder:%s:%s:%s", booking.BookingID, day.Format(dayLayout), assignment.EmployeeID.Hex())
+		to := notify.Recipient{Phone: employee.MobileNumber, Email: employee.Email}
+		data := notify.CrewData(booking, employee, assignment)
+		if err := s.notifier.NotifyOnce(ctx, key, notify.EventCrewReminder, to, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package sms
+
+import (
+	"context"
+	"log"
+)
+
+// Sender delivers a text message to a phone number
+type Sender interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// LogSender is a Sender that only writes messages to the log, for development and
+// deployments without an SMS provider
+type LogSender struct{}
+
+// Send logs the message instead of delivering it
+func (LogSender) Send(ctx context.Context, to, message string) error {
+	log.Printf("SMS to %s: %s", to, message)
+	return nil
+}
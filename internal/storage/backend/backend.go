@@ -0,0 +1,38 @@
+// Package backend opens the storage backend selected by the environment, for the
+// commands that share the API's data.
+package backend
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/modernband/booking/internal/database"
+	"github.com/modernband/booking/internal/storage"
+	"github.com/modernband/booking/internal/storage/memory"
+	"github.com/modernband/booking/internal/storage/sqlite"
+)
+
+// Open returns the store named by the StorageBackend environment variable
+// (mongodb, the default, sqlite or memory) and a function that closes it
+func Open() (*storage.Store, func(), error) {
+	switch backend := os.Getenv("StorageBackend"); backend {
+	case "", "mongodb":
+		return database.NewMongoStore(database.GetDB()), database.Close, nil // Initialize MongoDB connection
+	case "sqlite":
+		path := os.Getenv("SQLitePath")
+		if path == "" {
+			path = "./data/bookings.db"
+		}
+		db, err := sqlite.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open SQLite database: %w", err)
+		}
+		return sqlite.NewStore(db), func() { db.Close() }, nil
+	case "memory":
+		log.Println("Warning: using in-memory storage, data will be lost on restart")
+		return memory.NewStore(), func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown StorageBackend %q, expected mongodb, sqlite or memory", backend)
+	}
+}
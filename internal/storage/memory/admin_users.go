@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// adminRepository implements storage.AdminRepository in memory
+type adminRepository struct {
+	*db
+}
+
+func (r *adminRepository) Create(ctx context.Context, admin *models.AdminUser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range r.admins {
+		if a.Username == admin.Username {
+			return storage.ErrDuplicate
+		}
+	}
+	if admin.ID.IsZero() {
+		admin.ID = primitive.NewObjectID()
+	}
+	r.admins = append(r.admins, *admin)
+	return nil
+}
+
+func (r *adminRepository) CreateFirst(ctx context.Context, admin *models.AdminUser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.admins) > 0 {
+		return storage.ErrConflict
+	}
+	if admin.ID.IsZero() {
+		admin.ID = primitive.NewObjectID()
+	}
+	r.admins = append(r.admins, *admin)
+	return nil
+}
+
+func (r *adminRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.admins)), nil
+}
+
+func (r *adminRepository) Exists(ctx context.Context, username string) (bool, error) {
+	_, err := r.FindByUsername(ctx, username)
+	if err == storage.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *adminRepository) FindByUsername(ctx context.Context, username string) (*models.AdminUser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, a := range r.admins {
+		if a.Username == username {
+			return &a, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *adminRepository) List(ctx context.Context) ([]models.AdminUser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	admins := append([]models.AdminUser{}, r.admins...)
+	sort.SliceStable(admins, func(i, j int) bool {
+		return admins[i].CreatedAt.After(admins[j].CreatedAt)
+	})
+	return admins, nil
+}
+
+func (r *adminRepository) Update(ctx context.Context, admin *models.AdminUser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.admins {
+		if r.admins[i].ID == admin.ID {
+			a := &r.admins[i]
+			if !admin.IsOwner() && r.lastOwner(a) {
+				return storage.ErrLastOwner
+			}
+			a.Name = admin.Name
+			a.MobileNumber = admin.MobileNumber
+			a.Email = admin.Email
+			a.Password = admin.Password
+			a.Role = admin.Role
+			a.UpdatedAt = admin.UpdatedAt
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (r *adminRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, a := range r.admins {
+		if a.ID == id {
+			if r.lastOwner(&a) {
+				return storage.ErrLastOwner
+			}
+			r.admins = append(r.admins[:i], r.admins[i+1:]...)
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+// lastOwner reports whether admin is the only owner. The caller must hold the lock.
+func (r *adminRepository) lastOwner(admin *models.AdminUser) bool {
+	if !admin.IsOwner() {
+		return false
+	}
+	for i := range r.admins {
+		if r.admins[i].ID != admin.ID && r.admins[i].IsOwner() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// assignmentRepository implements storage.AssignmentRepository in memory
+type assignmentRepository struct {
+	*db
+}
+
+func (r *assignmentRepository) Create(ctx context.Context, assignment *models.CrewAssignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.assignments {
+		existing := &r.assignments[i]
+		if existing.EmployeeID != assignment.EmployeeID || existing.DeletedAt != nil {
+			continue
+		}
+		if existing.BookingID == assignment.BookingID {
+			return storage.ErrDuplicate
+		}
+		if existing.Overlaps(assignment) {
+			return storage.ErrConflict
+		}
+	}
+
+	assignment.ID = primitive.NewObjectID()
+	r.assignments = append(r.assignments, *assignment)
+	return nil
+}
+
+func (r *assignmentRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.CrewAssignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Assignments are appended in order, so they are already in assignment order
+	assignments := []models.CrewAssignment{}
+	for _, a := range r.assignments {
+		if a.BookingID == bookingID && a.DeletedAt == nil {
+			assignments = append(assignments, a)
+		}
+	}
+	return assignments, nil
+}
+
+func (r *assignmentRepository) ListByEmployee(ctx context.Context, employeeID primitive.ObjectID, from time.Time) ([]models.CrewAssignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assignments := []models.CrewAssignment{}
+	for _, a := range r.assignments {
+		if a.EmployeeID == employeeID && a.DeletedAt == nil && !a.EventDate.Before(from) {
+			assignments = append(assignments, a)
+		}
+	}
+	sort.SliceStable(assignments, func(i, j int) bool {
+		if !assignments[i].EventDate.Equal(assignments[j].EventDate) {
+			return assignments[i].EventDate.Before(assignments[j].EventDate)
+		}
+		return assignments[i].TimeSlot < assignments[j].TimeSlot
+	})
+	return assignments, nil
+}
+
+func (r *assignmentRepository) Delete(ctx context.Context, bookingID string, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, a := range r.assignments {
+		if a.ID == id && a.BookingID == bookingID && a.DeletedAt == nil {
+			r.assignments = append(r.assignments[:i], r.assignments[i+1:]...)
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (r *assignmentRepository) DeleteByBooking(ctx context.Context, bookingID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleteAssignments(func(a *models.CrewAssignment) bool { return a.BookingID == bookingID })
+	return nil
+}
+
+// restoreCrew brings back the crew of a booking taken out of the trash. The
+// assignments of employees now in the trash, or since assigned to another event
+// at the same time, are removed instead. The caller must hold the lock.
+func (d *db) restoreCrew(bookingID string) {
+	available := func(a *models.CrewAssignment) bool {
+		employee := false
+		for i := range d.employees {
+			if d.employees[i].ID == a.EmployeeID {
+				employee = d.employees[i].DeletedAt == nil
+			}
+		}
+		if !employee {
+			return false
+		}
+		for i := range d.assignments {
+			other := &d.assignments[i]
+			if other.EmployeeID == a.EmployeeID && other.DeletedAt == nil && other.Overlaps(a) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := range d.assignments {
+		a := &d.assignments[i]
+		if a.BookingID == bookingID && a.DeletedAt != nil && available(a) {
+			a.DeletedAt = nil
+		}
+	}
+	d.deleteAssignments(func(a *models.CrewAssignment) bool { return a.BookingID == bookingID && a.DeletedAt != nil })
+}
+
+// deleteAssignments removes the assignments matching the predicate. The caller
+// must hold the lock.
+func (d *db) deleteAssignments(match func(*models.CrewAssignment) bool) {
+	kept := d.assignments[:0]
+	for i := range d.assignments {
+		if !match(&d.assignments[i]) {
+			kept = append(kept, d.assignments[i])
+		}
+	}
+	d.assignments = kept
+}
@@ -0,0 +1,479 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// bookingRepository implements storage.BookingRepository in memory
+type bookingRepository struct {
+	*db
+}
+
+func (r *bookingRepository) Create(ctx context.Context, booking *models.Booking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, b := range r.bookings {
+		if b.BookingID == booking.BookingID {
+			return storage.ErrDuplicate
+		}
+	}
+	booking.ID = primitive.NewObjectID()
+	r.bookings = append(r.bookings, *booking)
+	return nil
+}
+
+func (r *bookingRepository) CreateMany(ctx context.Context, bookings []models.Booking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	taken := map[string]bool{}
+	for _, b := range r.bookings {
+		taken[b.BookingID] = true
+	}
+	for _, b := range bookings {
+		if taken[b.BookingID] {
+			return storage.ErrDuplicate
+		}
+		taken[b.BookingID] = true
+	}
+
+	for i := range bookings {
+		bookings[i].ID = primitive.NewObjectID()
+		r.bookings = append(r.bookings, bookings[i])
+	}
+	return nil
+}
+
+func (r *bookingRepository) CreateWithinCapacity(ctx context.Context, booking *models.Booking, limits storage.CapacityLimits, verifiedSince time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, b := range r.bookings {
+		if b.BookingID == booking.BookingID {
+			return storage.ErrDuplicate
+		}
+	}
+	if err := r.checkCapacity(booking, limits); err != nil {
+		return err
+	}
+
+	// Use up the phone's verification only together with the insert
+	record, ok := r.otps[booking.Phone]
+	if !ok || !record.ExpiresAt.After(time.Now()) || record.VerifiedAt == nil || !record.VerifiedAt.After(verifiedSince) {
+		return storage.ErrNotVerified
+	}
+	delete(r.otps, booking.Phone)
+
+	booking.ID = primitive.NewObjectID()
+	r.bookings = append(r.bookings, *booking)
+	return nil
+}
+
+// checkCapacity returns ErrCapacityExceeded if the other bookings on the booking's
+// day and time slot already reach limits, and ErrInsufficientStock if the equipment
+// they reserve leaves too little for the booking. The caller must hold the lock.
+func (r *bookingRepository) checkCapacity(booking *models.Booking, limits storage.CapacityLimits) error {
+	day := booking.EventDate.UTC().Format(dayLayout)
+	dayCount, slotCount := 0, 0
+	reserved := map[string]int{}
+	for i := range r.bookings {
+		b := &r.bookings[i]
+		if b.BookingID == booking.BookingID || b.Status == models.BookingStatusCancelled ||
+			b.DeletedAt != nil || b.EventDate.UTC().Format(dayLayout) != day {
+			continue
+		}
+		dayCount++
+		if b.TimeSlot == booking.TimeSlot {
+			slotCount++
+		}
+		for item, quantity := range b.Equipment() {
+			reserved[item] += quantity
+		}
+	}
+
+	if limits.PerDay > 0 && dayCount >= limits.PerDay {
+		return storage.ErrCapacityExceeded
+	}
+	if limits.PerSlot > 0 && booking.TimeSlot != "" && slotCount >= limits.PerSlot {
+		return storage.ErrCapacityExceeded
+	}
+	if limits.StockExceeded(reserved, booking.Equipment()) {
+		return storage.ErrInsufficientStock
+	}
+	return nil
+}
+
+func (r *bookingRepository) CountBySlot(ctx context.Context, from, to time.Time) ([]models.SlotCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fromDay, toDay := from.UTC().Format(dayLayout), to.UTC().Format(dayLayout)
+	index := map[models.SlotCount]int{}
+	counts := []models.SlotCount{}
+	for _, b := range r.bookings {
+		day := b.EventDate.UTC().Format(dayLayout)
+		if b.Status == models.BookingStatusCancelled || b.DeletedAt != nil || day < fromDay || day > toDay {
+			continue
+		}
+		key := models.SlotCount{Day: day, Slot: b.TimeSlot}
+		if i, ok := index[key]; ok {
+			counts[i].Count++
+			continue
+		}
+		index[key] = len(counts)
+		key.Count = 1
+		counts = append(counts, key)
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Day != counts[j].Day {
+			return counts[i].Day < counts[j].Day
+		}
+		return counts[i].Slot < counts[j].Slot
+	})
+	return counts, nil
+}
+
+func (r *bookingRepository) ReservedEquipment(ctx context.Context, from, to time.Time) ([]models.EquipmentReservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fromDay, toDay := from.UTC().Format(dayLayout), to.UTC().Format(dayLayout)
+	index := map[models.EquipmentReservation]int{}
+	reservations := []models.EquipmentReservation{}
+	for i := range r.bookings {
+		b := &r.bookings[i]
+		day := b.EventDate.UTC().Format(dayLayout)
+		if b.Status == models.BookingStatusCancelled || b.DeletedAt != nil || day < fromDay || day > toDay {
+			continue
+		}
+		for item, quantity := range b.Equipment() {
+			key := models.EquipmentReservation{Day: day, Item: item}
+			if i, ok := index[key]; ok {
+				reservations[i].Quantity += quantity
+				continue
+			}
+			index[key] = len(reservations)
+			key.Quantity = quantity
+			reservations = append(reservations, key)
+		}
+	}
+
+	sort.Slice(reservations, func(i, j int) bool {
+		if reservations[i].Day != reservations[j].Day {
+			return reservations[i].Day < reservations[j].Day
+		}
+		return reservations[i].Item < reservations[j].Item
+	})
+	return reservations, nil
+}
+
+func (r *bookingRepository) Exists(ctx context.Context, bookingID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, b := range r.bookings {
+		if b.BookingID == bookingID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *bookingRepository) FindByBookingID(ctx context.Context, bookingID string) (*models.Booking, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, b := range r.bookings {
+		if b.BookingID == bookingID && b.DeletedAt == nil {
+			return &b, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *bookingRepository) FindByPhone(ctx context.Context, phone string) ([]models.Booking, error) {
+	return r.find(func(b *models.Booking) bool { return b.Phone == phone }), nil
+}
+
+func (r *bookingRepository) List(ctx context.Context, filter storage.BookingFilter, page storage.BookingPage) ([]models.Booking, int64, error) {
+	bookings := r.find(func(b *models.Booking) bool { return matchBooking(b, filter) })
+
+	// Order by the sort field, then by booking ID so equal values page consistently
+	compare := func(a, b *models.Booking) int {
+		switch page.Sort {
+		case storage.SortEventDate:
+			return a.EventDate.Compare(b.EventDate)
+		case storage.SortAmount:
+			return a.Amount - b.Amount
+		case storage.SortName:
+			return strings.Compare(a.Name, b.Name)
+		default:
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+	}
+	sort.SliceStable(bookings, func(i, j int) bool {
+		cmp := compare(&bookings[i], &bookings[j])
+		if page.Desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+		return bookings[i].BookingID < bookings[j].BookingID
+	})
+
+	total := int64(len(bookings))
+	if page.Offset >= len(bookings) {
+		return []models.Booking{}, total, nil
+	}
+	bookings = bookings[page.Offset:]
+	if page.Limit > 0 && page.Limit < len(bookings) {
+		bookings = bookings[:page.Limit]
+	}
+	return bookings, total, nil
+}
+
+func (r *bookingRepository) Search(ctx context.Context, query string, limit int) ([]models.Booking, error) {
+	terms := storage.SearchTerms(query)
+	bookings := r.find(func(b *models.Booking) bool { return searchScore(bookingFields(b), terms) > 0 })
+
+	// Best match first; newer bookings win ties
+	scores := make([]int, len(bookings))
+	for i := range bookings {
+		scores[i] = searchScore(bookingFields(&bookings[i]), terms)
+	}
+	return rank(bookings, scores, limit), nil
+}
+
+func (r *bookingRepository) Batches(ctx context.Context, filter storage.BookingFilter, page storage.BookingPage, size int, fn func([]models.Booking) error) error {
+	bookings, _, err := r.List(ctx, filter, page)
+	if err != nil {
+		return err
+	}
+	return inBatches(bookings, size, fn)
+}
+
+// matchBooking reports whether a booking matches filter
+func matchBooking(b *models.Booking, filter storage.BookingFilter) bool {
+	switch {
+	case filter.Status != "" && b.CurrentStatus() != filter.Status:
+		return false
+	case !filter.From.IsZero() && b.EventDate.Before(filter.From):
+		return false
+	case !filter.Before.IsZero() && !b.EventDate.Before(filter.Before):
+		return false
+	case filter.City != "" && !strings.EqualFold(b.City, filter.City):
+		return false
+	case filter.PackageType != "" && b.PackageType != filter.PackageType:
+		return false
+	case filter.Phone != "" && b.Phone != filter.Phone:
+		return false
+	case filter.Archived == storage.ArchivedExclude && b.ArchivedAt != nil:
+		return false
+	case filter.Archived == storage.ArchivedOnly && b.ArchivedAt == nil:
+		return false
+	}
+	return true
+}
+
+func (r *bookingRepository) UpdateStatus(ctx context.Context, bookingID, from string, change models.StatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.bookings {
+		b := &r.bookings[i]
+		if b.BookingID != bookingID || b.DeletedAt != nil {
+			continue
+		}
+		if b.CurrentStatus() != from {
+			return storage.ErrConflict
+		}
+		// Copy the history so bookings returned earlier are not affected
+		history := make([]models.StatusChange, 0, len(b.StatusHistory)+1)
+		b.StatusHistory = append(append(history, b.StatusHistory...), change)
+		b.Status = change.To
+		if change.To == models.BookingStatusCompleted {
+			r.accrueEarnings(bookingID, change)
+		}
+		return nil
+	}
+	return storage.ErrNotFound
+}
+
+// find returns copies of the bookings outside the trash matching the predicate,
+// newest first
+func (r *bookingRepository) find(match func(*models.Booking) bool) []models.Booking {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bookings := []models.Booking{}
+	for i := range r.bookings {
+		if r.bookings[i].DeletedAt == nil && match(&r.bookings[i]) {
+			bookings = append(bookings, r.bookings[i])
+		}
+	}
+	sort.SliceStable(bookings, func(i, j int) bool {
+		return bookings[i].CreatedAt.After(bookings[j].CreatedAt)
+	})
+	return bookings
+}
+
+func (r *bookingRepository) Update(ctx context.Context, booking *models.Booking, revision *models.BookingRevision, limits *storage.CapacityLimits) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.bookings {
+		stored := &r.bookings[i]
+		if stored.BookingID != booking.BookingID || stored.DeletedAt != nil {
+			continue
+		}
+		if stored.Revision != booking.Revision-1 || stored.CurrentStatus() != booking.CurrentStatus() {
+			return storage.ErrConflict
+		}
+		if limits != nil {
+			if err := r.checkCapacity(booking, *limits); err != nil {
+				return err
+			}
+		}
+
+		// Status changes go through UpdateStatus only
+		updated := *booking
+		updated.Status, updated.StatusHistory = stored.Status, stored.StatusHistory
+		*stored = updated
+
+		revision.ID = primitive.NewObjectID()
+		r.revisions = append(r.revisions, *revision)
+		return nil
+	}
+	return storage.ErrNotFound
+}
+
+func (r *bookingRepository) ListRevisions(ctx context.Context, bookingID string) ([]models.BookingRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Revisions are appended in order, so they are already oldest first
+	revisions := []models.BookingRevision{}
+	for _, rev := range r.revisions {
+		if rev.BookingID == bookingID {
+			revisions = append(revisions, rev)
+		}
+	}
+	return revisions, nil
+}
+
+func (r *bookingRepository) Delete(ctx context.Context, bookingID, by string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.bookings {
+		b := &r.bookings[i]
+		if b.BookingID == bookingID && b.DeletedAt == nil {
+			b.DeletedAt, b.DeletedBy = &at, by
+			for j := range r.assignments {
+				if r.assignments[j].BookingID == bookingID && r.assignments[j].DeletedAt == nil {
+					r.assignments[j].DeletedAt = &at
+				}
+			}
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (r *bookingRepository) FindDeleted(ctx context.Context, bookingID string) (*models.Booking, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, b := range r.bookings {
+		if b.BookingID == bookingID && b.DeletedAt != nil {
+			return &b, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *bookingRepository) Restore(ctx context.Context, bookingID string, limits *storage.CapacityLimits) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.bookings {
+		b := &r.bookings[i]
+		if b.BookingID != bookingID || b.DeletedAt == nil {
+			continue
+		}
+		if limits != nil {
+			if err := r.checkCapacity(b, *limits); err != nil {
+				return err
+			}
+		}
+		b.DeletedAt, b.DeletedBy = nil, ""
+		r.restoreCrew(bookingID)
+		return nil
+	}
+	return storage.ErrNotFound
+}
+
+func (r *bookingRepository) ListDeleted(ctx context.Context, limit int) ([]models.Booking, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bookings := []models.Booking{}
+	for _, b := range r.bookings {
+		if b.DeletedAt != nil {
+			bookings = append(bookings, b)
+		}
+	}
+	sort.SliceStable(bookings, func(i, j int) bool {
+		if !bookings[i].DeletedAt.Equal(*bookings[j].DeletedAt) {
+			return bookings[i].DeletedAt.After(*bookings[j].DeletedAt)
+		}
+		return bookings[i].BookingID < bookings[j].BookingID
+	})
+	if limit > 0 && limit < len(bookings) {
+		bookings = bookings[:limit]
+	}
+	return bookings, nil
+}
+
+func (r *bookingRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.bookings[:0]
+	purged := map[string]bool{}
+	for _, b := range r.bookings {
+		if b.DeletedAt == nil || !b.DeletedAt.Before(t) {
+			kept = append(kept, b)
+		} else {
+			purged[b.BookingID] = true
+		}
+	}
+	r.bookings = kept
+	r.deleteAssignments(func(a *models.CrewAssignment) bool { return purged[a.BookingID] })
+	return int64(len(purged)), nil
+}
+
+func (r *bookingRepository) ArchiveBefore(ctx context.Context, t, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var archived int64
+	for i := range r.bookings {
+		b := &r.bookings[i]
+		if b.ArchivedAt == nil && b.DeletedAt == nil && b.EventDate.Before(t) && b.CurrentStatus() != models.BookingStatusConfirmed {
+			b.ArchivedAt = &at
+			archived++
+		}
+	}
+	return archived, nil
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// customerPaymentRepository implements storage.CustomerPaymentRepository in memory
+type customerPaymentRepository struct {
+	*db
+}
+
+func (r *customerPaymentRepository) Create(ctx context.Context, payment *models.CustomerPayment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment.ID = primitive.NewObjectID()
+	r.customerPayments = append(r.customerPayments, *payment)
+	return nil
+}
+
+func (r *customerPaymentRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.CustomerPayment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	payments := []models.CustomerPayment{}
+	for _, p := range r.customerPayments {
+		if p.BookingID == bookingID {
+			payments = append(payments, p)
+		}
+	}
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].PaidAt.Before(payments[j].PaidAt)
+	})
+	return payments, nil
+}
+
+func (r *customerPaymentRepository) Void(ctx context.Context, bookingID string, paymentID primitive.ObjectID, by, reason string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.customerPayments {
+		p := &r.customerPayments[i]
+		if p.ID != paymentID || p.BookingID != bookingID {
+			continue
+		}
+		if p.VoidedAt != nil {
+			return storage.ErrConflict
+		}
+		p.VoidedAt, p.VoidedBy, p.VoidReason = &at, by, reason
+		return nil
+	}
+	return storage.ErrNotFound
+}
+
+func (r *customerPaymentRepository) TotalsByBooking(ctx context.Context, bookingIDs []string) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(bookingIDs))
+	for _, id := range bookingIDs {
+		wanted[id] = true
+	}
+
+	totals := map[string]int{}
+	for _, p := range r.customerPayments {
+		if p.VoidedAt == nil && wanted[p.BookingID] {
+			totals[p.BookingID] += p.Amount
+		}
+	}
+	return totals, nil
+}
+
+func (r *customerPaymentRepository) Batches(ctx context.Context, filter storage.CustomerPaymentFilter, size int, fn func([]models.CustomerPayment) error) error {
+	r.mu.RLock()
+	payments := []models.CustomerPayment{}
+	for _, p := range r.customerPayments {
+		if (filter.From.IsZero() || !p.PaidAt.Before(filter.From)) && (filter.Before.IsZero() || p.PaidAt.Before(filter.Before)) {
+			payments = append(payments, p)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].PaidAt.Before(payments[j].PaidAt)
+	})
+	return inBatches(payments, size, fn)
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// earningRepository implements storage.EarningRepository in memory
+type earningRepository struct {
+	*db
+}
+
+// matchEarning reports whether an earning matches filter
+func matchEarning(e *models.Earning, filter storage.EarningFilter) bool {
+	if !filter.EmployeeID.IsZero() && e.EmployeeID != filter.EmployeeID {
+		return false
+	}
+	if !filter.From.IsZero() && e.EventDate.Before(filter.From) {
+		return false
+	}
+	return filter.Before.IsZero() || e.EventDate.Before(filter.Before)
+}
+
+func (r *earningRepository) List(ctx context.Context, filter storage.EarningFilter) ([]models.Earning, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	earnings := []models.Earning{}
+	for i := range r.earnings {
+		if matchEarning(&r.earnings[i], filter) {
+			earnings = append(earnings, r.earnings[i])
+		}
+	}
+	sort.SliceStable(earnings, func(i, j int) bool {
+		return earnings[i].EventDate.Before(earnings[j].EventDate)
+	})
+	return earnings, nil
+}
+
+func (r *earningRepository) TotalsByEmployee(ctx context.Context, filter storage.EarningFilter) (map[primitive.ObjectID]models.PaymentTotal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := map[primitive.ObjectID]models.PaymentTotal{}
+	for i := range r.earnings {
+		e := &r.earnings[i]
+		if !matchEarning(e, filter) {
+			continue
+		}
+		total := totals[e.EmployeeID]
+		total.Amount += e.Amount
+		total.Count++
+		totals[e.EmployeeID] = total
+	}
+	return totals, nil
+}
+
+// accrueEarnings records an earning for each crew assignment of a booking. The
+// caller must hold the lock.
+func (d *db) accrueEarnings(bookingID string, change models.StatusChange) {
+	for i := range d.assignments {
+		if d.assignments[i].BookingID != bookingID || d.assignments[i].DeletedAt != nil {
+			continue
+		}
+		earning := models.NewEarning(&d.assignments[i], change.At)
+		earning.ID = primitive.NewObjectID()
+		d.earnings = append(d.earnings, earning)
+	}
+}
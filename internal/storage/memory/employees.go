@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// employeeRepository implements storage.EmployeeRepository in memory
+type employeeRepository struct {
+	*db
+}
+
+func (r *employeeRepository) Create(ctx context.Context, employee *models.Employee) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.employees {
+		if e.Username == employee.Username {
+			return storage.ErrDuplicate
+		}
+	}
+	employee.ID = primitive.NewObjectID()
+	r.employees = append(r.employees, *employee)
+	return nil
+}
+
+func (r *employeeRepository) CreateMany(ctx context.Context, employees []models.Employee) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	taken := map[string]bool{}
+	for _, e := range r.employees {
+		taken[e.Username] = true
+	}
+	for _, e := range employees {
+		if taken[e.Username] {
+			return storage.ErrDuplicate
+		}
+		taken[e.Username] = true
+	}
+
+	for i := range employees {
+		employees[i].ID = primitive.NewObjectID()
+		r.employees = append(r.employees, employees[i])
+	}
+	return nil
+}
+
+func (r *employeeRepository) Exists(ctx context.Context, username string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.employees {
+		if e.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *employeeRepository) FindByUsername(ctx context.Context, username string) (*models.Employee, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.employees {
+		if e.Username == username && e.DeletedAt == nil {
+			return &e, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *employeeRepository) List(ctx context.Context) ([]models.Employee, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	employees := []models.Employee{}
+	for _, e := range r.employees {
+		if e.DeletedAt == nil {
+			employees = append(employees, e)
+		}
+	}
+	sort.SliceStable(employees, func(i, j int) bool {
+		return employees[i].CreatedAt.After(employees[j].CreatedAt)
+	})
+	return employees, nil
+}
+
+func (r *employeeRepository) Search(ctx context.Context, query string, limit int) ([]models.Employee, error) {
+	employees, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Best match first; newer employees win ties
+	terms := storage.SearchTerms(query)
+	matched, scores := []models.Employee{}, []int{}
+	for i := range employees {
+		if score := searchScore(employeeFields(&employees[i]), terms); score > 0 {
+			matched = append(matched, employees[i])
+			scores = append(scores, score)
+		}
+	}
+	return rank(matched, scores, limit), nil
+}
+
+func (r *employeeRepository) Delete(ctx context.Context, id primitive.ObjectID, by string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.employees {
+		e := &r.employees[i]
+		if e.ID == id && e.DeletedAt == nil {
+			e.DeletedAt, e.DeletedBy = &at, by
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (r *employeeRepository) Restore(ctx context.Context, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.employees {
+		e := &r.employees[i]
+		if e.Username == username && e.DeletedAt != nil {
+			e.DeletedAt, e.DeletedBy = nil, ""
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (r *employeeRepository) ListDeleted(ctx context.Context, limit int) ([]models.Employee, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	employees := []models.Employee{}
+	for _, e := range r.employees {
+		if e.DeletedAt != nil {
+			employees = append(employees, e)
+		}
+	}
+	sort.SliceStable(employees, func(i, j int) bool {
+		if !employees[i].DeletedAt.Equal(*employees[j].DeletedAt) {
+			return employees[i].DeletedAt.After(*employees[j].DeletedAt)
+		}
+		return employees[i].Username < employees[j].Username
+	})
+	if limit > 0 && limit < len(employees) {
+		employees = employees[:limit]
+	}
+	return employees, nil
+}
+
+func (r *employeeRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := map[primitive.ObjectID]bool{}
+	kept := r.employees[:0]
+	for _, e := range r.employees {
+		if e.DeletedAt != nil && e.DeletedAt.Before(t) {
+			purged[e.ID] = true
+			continue
+		}
+		kept = append(kept, e)
+	}
+	r.employees = kept
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	// Remove the employees' payments, crew assignments and earnings with them
+	payments := r.payments[:0]
+	for _, p := range r.payments {
+		if !purged[p.EmployeeID] {
+			payments = append(payments, p)
+		}
+	}
+	r.payments = payments
+	r.deleteAssignments(func(a *models.CrewAssignment) bool { return purged[a.EmployeeID] })
+	earnings := r.earnings[:0]
+	for _, e := range r.earnings {
+		if !purged[e.EmployeeID] {
+			earnings = append(earnings, e)
+		}
+	}
+	r.earnings = earnings
+	return int64(len(purged)), nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// inventoryRepository implements storage.InventoryRepository in memory
+type inventoryRepository struct {
+	*db
+}
+
+func (r *inventoryRepository) List(ctx context.Context) ([]models.InventoryItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := []models.InventoryItem{}
+	for _, item := range r.inventory {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Code < items[j].Code
+	})
+	return items, nil
+}
+
+func (r *inventoryRepository) Put(ctx context.Context, item *models.InventoryItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.inventory[item.Code]; ok {
+		item.ID = existing.ID
+	} else {
+		item.ID = primitive.NewObjectID()
+	}
+	r.inventory[item.Code] = *item
+	return nil
+}
+
+func (r *inventoryRepository) Delete(ctx context.Context, code string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.inventory[code]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(r.inventory, code)
+	return nil
+}
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// jobRepository implements storage.JobRepository in memory
+type jobRepository struct {
+	*db
+}
+
+// lockable reports whether the job's lock, if any, has expired at now
+func lockable(j models.JobState, now time.Time) bool {
+	return j.LockedUntil == nil || !j.LockedUntil.After(now)
+}
+
+func (r *jobRepository) Ensure(ctx context.Context, state *models.JobState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.jobs[state.Name]; !ok {
+		r.jobs[state.Name] = *state
+	}
+	return nil
+}
+
+func (r *jobRepository) List(ctx context.Context) ([]models.JobState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobs := make([]models.JobState, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].Name < jobs[k].Name })
+	return jobs, nil
+}
+
+func (r *jobRepository) Find(ctx context.Context, name string) (*models.JobState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	j, ok := r.jobs[name]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &j, nil
+}
+
+func (r *jobRepository) UpdateSchedule(ctx context.Context, state *models.JobState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[state.Name]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	j.Schedule = state.Schedule
+	j.Enabled = state.Enabled
+	j.NextRunAt = state.NextRunAt
+	j.UpdatedBy = state.UpdatedBy
+	j.UpdatedAt = state.UpdatedAt
+	r.jobs[state.Name] = j
+	return nil
+}
+
+func (r *jobRepository) Lock(ctx context.Context, name, owner string, now, lockedUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[name]
+	if !ok || !lockable(j, now) {
+		return false, nil
+	}
+	j.LockedBy = owner
+	j.LockedUntil = &lockedUntil
+	r.jobs[name] = j
+	return true, nil
+}
+
+func (r *jobRepository) LockDue(ctx context.Context, name, owner string, now, lockedUntil, next time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[name]
+	if !ok || !j.Enabled || j.NextRunAt.After(now) || !lockable(j, now) {
+		return false, nil
+	}
+	j.LockedBy = owner
+	j.LockedUntil = &lockedUntil
+	j.NextRunAt = next
+	r.jobs[name] = j
+	return true, nil
+}
+
+func (r *jobRepository) Unlock(ctx context.Context, name, owner string, run *models.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[name]
+	if !ok {
+		return nil
+	}
+	lastRun := *run
+	j.LastRun = &lastRun
+	if j.LockedBy == owner {
+		j.LockedBy = ""
+		j.LockedUntil = nil
+	}
+	r.jobs[name] = j
+	return nil
+}
+
+func (r *jobRepository) CreateRun(ctx context.Context, run *models.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run.ID = primitive.NewObjectID()
+	r.jobRuns = append(r.jobRuns, *run)
+	return nil
+}
+
+func (r *jobRepository) FinishRun(ctx context.Context, run *models.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.jobRuns {
+		if r.jobRuns[i].ID == run.ID {
+			r.jobRuns[i].Status = run.Status
+			r.jobRuns[i].Result = run.Result
+			r.jobRuns[i].Error = run.Error
+			r.jobRuns[i].FinishedAt = run.FinishedAt
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (r *jobRepository) ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	runs := []models.JobRun{}
+	for i := len(r.jobRuns) - 1; i >= 0 && len(runs) < limit; i-- {
+		if r.jobRuns[i].Job == job {
+			runs = append(runs, r.jobRuns[i])
+		}
+	}
+	return runs, nil
+}
+
+func (r *jobRepository) DeleteRunsBefore(ctx context.Context, t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.jobRuns[:0]
+	for _, run := range r.jobRuns {
+		if !run.StartedAt.Before(t) {
+			kept = append(kept, run)
+		}
+	}
+	removed := int64(len(r.jobRuns) - len(kept))
+	r.jobRuns = kept
+	return removed, nil
+}
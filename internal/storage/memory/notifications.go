@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// notificationRepository implements storage.NotificationRepository in memory
+type notificationRepository struct {
+	*db
+}
+
+// notification returns the index of the notification with the ID, or -1. The
+// caller must hold the lock.
+func (r *notificationRepository) notification(id primitive.ObjectID) int {
+	for i := range r.notifications {
+		if r.notifications[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// queued reports whether a notification with the key exists. The caller must hold the lock.
+func (r *notificationRepository) queued(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, n := range r.notifications {
+		if n.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *notificationRepository) Enqueue(ctx context.Context, notifications []models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range notifications {
+		if r.queued(notifications[i].Key) {
+			continue
+		}
+		notifications[i].ID = primitive.NewObjectID()
+		r.notifications = append(r.notifications, notifications[i])
+	}
+	return nil
+}
+
+func (r *notificationRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []int
+	for i, n := range r.notifications {
+		if n.Status == models.NotificationPending && !n.NextAttemptAt.After(now) {
+			due = append(due, i)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return r.notifications[due[i]].NextAttemptAt.Before(r.notifications[due[j]].NextAttemptAt)
+	})
+
+	claimed := []models.Notification{}
+	for _, i := range due[:min(limit, len(due))] {
+		r.notifications[i].Attempts++
+		r.notifications[i].NextAttemptAt = leaseUntil
+		claimed = append(claimed, r.notifications[i])
+	}
+	return claimed, nil
+}
+
+func (r *notificationRepository) MarkSent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.notification(id)
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	r.notifications[i].Status = models.NotificationSent
+	r.notifications[i].SentAt = &at
+	r.notifications[i].LastError = ""
+	return nil
+}
+
+func (r *notificationRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, lastError string, retryAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.notification(id)
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	r.notifications[i].LastError = lastError
+	if retryAt.IsZero() {
+		r.notifications[i].Status = models.NotificationFailed
+	} else {
+		r.notifications[i].NextAttemptAt = retryAt
+	}
+	return nil
+}
+
+func (r *notificationRepository) Retry(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.notification(id)
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	if r.notifications[i].Status != models.NotificationFailed {
+		return storage.ErrConflict
+	}
+	r.notifications[i].Status = models.NotificationPending
+	r.notifications[i].Attempts = 0
+	r.notifications[i].NextAttemptAt = at
+	return nil
+}
+
+func (r *notificationRepository) List(ctx context.Context, filter storage.NotificationFilter, limit int) ([]models.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := []models.Notification{}
+	for i := len(r.notifications) - 1; i >= 0 && len(notifications) < limit; i-- {
+		n := r.notifications[i]
+		if (filter.Status == "" || n.Status == filter.Status) && (filter.BookingID == "" || n.BookingID == filter.BookingID) {
+			notifications = append(notifications, n)
+		}
+	}
+	return notifications, nil
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// otpRepository implements storage.OTPRepository in memory. Expired records are
+// treated as absent, mirroring the TTL index used by MongoDB.
+type otpRepository struct {
+	*db
+}
+
+// live returns the unexpired record for the phone. The caller must hold the lock.
+func (r *otpRepository) live(phone string, now time.Time) (models.OTPVerification, bool) {
+	record, ok := r.otps[phone]
+	if !ok || !record.ExpiresAt.After(now) {
+		return models.OTPVerification{}, false
+	}
+	return record, true
+}
+
+func (r *otpRepository) FindByPhone(ctx context.Context, phone string) (*models.OTPVerification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.live(phone, time.Now())
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &record, nil
+}
+
+func (r *otpRepository) Issue(ctx context.Context, phone, codeHash string, now, codeExpiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.live(phone, now)
+	if !ok {
+		record = models.OTPVerification{ID: primitive.NewObjectID(), Phone: phone, CreatedAt: now}
+	}
+	record.CodeHash = codeHash
+	record.CodeExpiresAt = codeExpiresAt
+	record.Attempts = 0
+	record.LastSentAt = now
+	record.ExpiresAt = codeExpiresAt
+	record.VerifiedAt = nil
+	r.otps[phone] = record
+	return nil
+}
+
+func (r *otpRepository) RecordAttempt(ctx context.Context, phone string, now time.Time, maxAttempts int) (*models.OTPVerification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.live(phone, now)
+	if !ok || record.CodeHash == "" || !record.CodeExpiresAt.After(now) || record.Attempts >= maxAttempts {
+		return nil, storage.ErrNotFound
+	}
+	record.Attempts++
+	r.otps[phone] = record
+	return &record, nil
+}
+
+func (r *otpRepository) MarkVerified(ctx context.Context, phone string, verifiedAt, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.otps[phone]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	record.CodeHash = ""
+	record.VerifiedAt = &verifiedAt
+	record.ExpiresAt = expiresAt
+	r.otps[phone] = record
+	return nil
+}
+
+func (r *otpRepository) LogEvent(ctx context.Context, event *models.OTPEvent, since time.Time, limit int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit > 0 && len(r.eventsSince(event.Phone, event.Kind, since)) >= limit {
+		return false, nil
+	}
+	event.ID = primitive.NewObjectID()
+	r.otpEvents = append(r.otpEvents, *event)
+	return true, nil
+}
+
+func (r *otpRepository) EventsSince(ctx context.Context, phone, kind string, since time.Time) ([]time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.eventsSince(phone, kind, since), nil
+}
+
+// eventsSince returns when the phone's events of the kind after since were
+// logged, oldest first. The caller must hold the lock.
+func (r *otpRepository) eventsSince(phone, kind string, since time.Time) []time.Time {
+	times := []time.Time{}
+	for _, e := range r.otpEvents {
+		if e.Phone == phone && e.Kind == kind && e.At.After(since) {
+			times = append(times, e.At)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
+
+func (r *otpRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for phone, record := range r.otps {
+		if !record.ExpiresAt.After(now) {
+			delete(r.otps, phone)
+			deleted++
+		}
+	}
+
+	kept := r.otpEvents[:0]
+	for _, e := range r.otpEvents {
+		if e.ExpiresAt.After(now) {
+			kept = append(kept, e)
+		}
+	}
+	r.otpEvents = kept
+	return deleted, nil
+}
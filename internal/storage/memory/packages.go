@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// packageRepository implements storage.PackageRepository in memory
+type packageRepository struct {
+	*db
+}
+
+func (r *packageRepository) Create(ctx context.Context, pkg *models.Package) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.packages[pkg.Code]; exists {
+		return storage.ErrDuplicate
+	}
+	pkg.ID = primitive.NewObjectID()
+	r.packages[pkg.Code] = *pkg
+	return nil
+}
+
+func (r *packageRepository) FindByCode(ctx context.Context, code string) (*models.Package, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pkg, ok := r.packages[code]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &pkg, nil
+}
+
+func (r *packageRepository) List(ctx context.Context) ([]models.Package, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	packages := []models.Package{}
+	for _, pkg := range r.packages {
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Price != packages[j].Price {
+			return packages[i].Price < packages[j].Price
+		}
+		return packages[i].Code < packages[j].Code
+	})
+	return packages, nil
+}
+
+func (r *packageRepository) Update(ctx context.Context, pkg *models.Package) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.packages[pkg.Code]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	updated := *pkg
+	updated.ID = existing.ID
+	updated.CreatedAt = existing.CreatedAt
+	r.packages[pkg.Code] = updated
+	return nil
+}
+
+func (r *packageRepository) Delete(ctx context.Context, code string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.packages[code]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(r.packages, code)
+	return nil
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// paymentRepository implements storage.PaymentRepository in memory
+type paymentRepository struct {
+	*db
+}
+
+func (r *paymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment.ID = primitive.NewObjectID()
+	r.payments = append(r.payments, *payment)
+	return nil
+}
+
+func (r *paymentRepository) ListByEmployee(ctx context.Context, employeeID primitive.ObjectID) ([]models.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	payments := []models.Payment{}
+	for _, p := range r.payments {
+		if p.EmployeeID == employeeID && p.DeletedAt == nil {
+			payments = append(payments, p)
+		}
+	}
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].Date.After(payments[j].Date)
+	})
+	return payments, nil
+}
+
+func (r *paymentRepository) Delete(ctx context.Context, employeeID, paymentID primitive.ObjectID, by string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.payments {
+		p := &r.payments[i]
+		if p.ID == paymentID && p.EmployeeID == employeeID && p.DeletedAt == nil {
+			p.DeletedAt, p.DeletedBy = &at, by
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (r *paymentRepository) Restore(ctx context.Context, employeeID, paymentID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.payments {
+		p := &r.payments[i]
+		if p.ID == paymentID && p.EmployeeID == employeeID && p.DeletedAt != nil {
+			p.DeletedAt, p.DeletedBy = nil, ""
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (r *paymentRepository) ListDeleted(ctx context.Context, limit int) ([]models.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	payments := []models.Payment{}
+	for _, p := range r.payments {
+		if p.DeletedAt != nil {
+			payments = append(payments, p)
+		}
+	}
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].DeletedAt.After(*payments[j].DeletedAt)
+	})
+	if limit > 0 && limit < len(payments) {
+		payments = payments[:limit]
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.payments[:0]
+	for _, p := range r.payments {
+		if p.DeletedAt == nil || !p.DeletedAt.Before(t) {
+			kept = append(kept, p)
+		}
+	}
+	purged := int64(len(r.payments) - len(kept))
+	r.payments = kept
+	return purged, nil
+}
+
+func (r *paymentRepository) TotalsByEmployee(ctx context.Context, filter storage.PaymentFilter) (map[primitive.ObjectID]models.PaymentTotal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := map[primitive.ObjectID]models.PaymentTotal{}
+	for _, p := range r.payments {
+		if !matchPayment(&p, filter) {
+			continue
+		}
+		total := totals[p.EmployeeID]
+		total.Amount += p.AmountPaid
+		total.Count++
+		totals[p.EmployeeID] = total
+	}
+	return totals, nil
+}
+
+func (r *paymentRepository) Batches(ctx context.Context, filter storage.PaymentFilter, size int, fn func([]models.Payment) error) error {
+	r.mu.RLock()
+	payments := []models.Payment{}
+	for _, p := range r.payments {
+		if matchPayment(&p, filter) {
+			payments = append(payments, p)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].Date.Before(payments[j].Date)
+	})
+	return inBatches(payments, size, fn)
+}
+
+// matchPayment reports whether a payment matches filter
+func matchPayment(p *models.Payment, filter storage.PaymentFilter) bool {
+	switch {
+	case p.DeletedAt != nil:
+		return false
+	case !filter.EmployeeID.IsZero() && p.EmployeeID != filter.EmployeeID:
+		return false
+	case !filter.From.IsZero() && p.Date.Before(filter.From):
+		return false
+	case !filter.Before.IsZero() && !p.Date.Before(filter.Before):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// rateCardRepository implements storage.RateCardRepository in memory
+type rateCardRepository struct {
+	*db
+}
+
+func (r *rateCardRepository) Current(ctx context.Context) (*models.RateCard, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.rateCards) == 0 {
+		return nil, storage.ErrNotFound
+	}
+	card := r.rateCards[len(r.rateCards)-1]
+	return &card, nil
+}
+
+func (r *rateCardRepository) Create(ctx context.Context, card *models.RateCard) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	card.ID = primitive.NewObjectID()
+	r.rateCards = append(r.rateCards, *card)
+	return nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/modernband/booking/internal/models"
+)
+
+// searchField is a searchable value and the weight of a match in it
+type searchField struct {
+	value  string
+	weight int
+}
+
+// bookingFields returns the searchable fields of a booking, weighted like the
+// MongoDB text index
+func bookingFields(b *models.Booking) []searchField {
+	return []searchField{
+		{b.BookingID, 10},
+		{b.Name, 5},
+		{b.Phone, 5},
+		{b.AdditionalPhone, 5},
+		{b.Email, 3},
+		{b.Venue, 2},
+		{b.City, 1},
+	}
+}
+
+// employeeFields returns the searchable fields of an employee
+func employeeFields(e *models.Employee) []searchField {
+	return []searchField{
+		{e.Username, 10},
+		{e.Name, 5},
+		{e.MobileNumber, 5},
+		{e.Email, 3},
+		{e.Address, 1},
+	}
+}
+
+// searchScore ranks fields against the search terms. Every term must occur in
+// some field; a whole-field match counts more than a prefix, which counts more
+// than a match elsewhere. It returns 0 if a term is not found.
+func searchScore(fields []searchField, terms []string) int {
+	total := 0
+	for _, term := range terms {
+		best := 0
+		for _, f := range fields {
+			value := strings.ToLower(f.value)
+			score := 0
+			switch {
+			case value == term:
+				score = 3 * f.weight
+			case strings.HasPrefix(value, term):
+				score = 2 * f.weight
+			case strings.Contains(value, term):
+				score = f.weight
+			}
+			if score > best {
+				best = score
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+// rank orders results by descending score, keeping the original order for ties,
+// and returns at most limit of them
+func rank[T any](results []T, scores []int, limit int) []T {
+	index := make([]int, len(results))
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(i, j int) bool { return scores[index[i]] > scores[index[j]] })
+	if limit > 0 && limit < len(index) {
+		index = index[:limit]
+	}
+
+	ranked := make([]T, 0, len(index))
+	for _, i := range index {
+		ranked = append(ranked, results[i])
+	}
+	return ranked
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/modernband/booking/internal/storage"
+)
+
+// settingsRepository implements storage.SettingsRepository in memory. Documents
+// are kept as JSON so callers never share state with the store.
+type settingsRepository struct {
+	*db
+}
+
+func (r *settingsRepository) Get(ctx context.Context, key string, v interface{}) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data, ok := r.settings[key]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (r *settingsRepository) Put(ctx context.Context, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settings[key] = data
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package memory provides a thread-safe in-memory storage backend for tests and local demos.
+// Data is lost when the process exits.
+package memory
+
+import (
+	"sync"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+)
+
+// dayLayout formats event dates as calendar days
+const dayLayout = "2006-01-02"
+
+// db holds every record of an in-memory store behind a single lock so that
+// operations spanning several collections stay consistent
+type db struct {
+	mu               sync.RWMutex
+	bookings         []models.Booking
+	revisions        []models.BookingRevision
+	employees        []models.Employee
+	payments         []models.Payment
+	customerPayments []models.CustomerPayment
+	assignments      []models.CrewAssignment
+	earnings         []models.Earning
+	admins           []models.AdminUser
+	otps             map[string]models.OTPVerification
+	otpEvents        []models.OTPEvent // In the order they were logged
+	rateCards        []models.RateCard // In creation order; the last one is current
+	packages         map[string]models.Package
+	settings         map[string][]byte // JSON documents keyed by setting name
+	inventory        map[string]models.InventoryItem
+	taxInvoices      []models.TaxInvoice   // In the order they were issued
+	taxInvoiceSeries map[string]int        // Last sequence used in each series
+	notifications    []models.Notification // In the order they were queued
+	jobs             map[string]models.JobState
+	jobRuns          []models.JobRun // In the order they started
+}
+
+// NewStore returns an empty in-memory store
+func NewStore() *storage.Store {
+	d := &db{
+		otps:             make(map[string]models.OTPVerification),
+		packages:         make(map[string]models.Package),
+		settings:         make(map[string][]byte),
+		inventory:        make(map[string]models.InventoryItem),
+		taxInvoiceSeries: make(map[string]int),
+		jobs:             make(map[string]models.JobState),
+	}
+	return &storage.Store{
+		Bookings:         &bookingRepository{d},
+		Employees:        &employeeRepository{d},
+		Payments:         &paymentRepository{d},
+		CustomerPayments: &customerPaymentRepository{d},
+		Assignments:      &assignmentRepository{d},
+		Earnings:         &earningRepository{d},
+		Admins:           &adminRepository{d},
+		OTPs:             &otpRepository{d},
+		RateCards:        &rateCardRepository{d},
+		Packages:         &packageRepository{d},
+		Settings:         &settingsRepository{d},
+		Inventory:        &inventoryRepository{d},
+		TaxInvoices:      &taxInvoiceRepository{d},
+		Notifications:    &notificationRepository{d},
+		Jobs:             &jobRepository{d},
+	}
+}
+
+// inBatches calls fn with successive slices of at most size items, stopping at
+// the first error. The caller must not hold the lock, as fn may use the store.
+func inBatches[T any](items []T, size int, fn func([]T) error) error {
+	for len(items) > 0 {
+		n := min(size, len(items))
+		if err := fn(items[:n:n]); err != nil {
+			return err
+		}
+		items = items[n:]
+	}
+	return nil
+}
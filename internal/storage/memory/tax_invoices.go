@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// taxInvoiceRepository implements storage.TaxInvoiceRepository in memory
+type taxInvoiceRepository struct {
+	*db
+}
+
+func (r *taxInvoiceRepository) Issue(ctx context.Context, doc *models.TaxInvoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.taxInvoices {
+		if d.BookingID == doc.BookingID && d.Kind == doc.Kind {
+			return storage.ErrDuplicate
+		}
+	}
+	r.taxInvoiceSeries[doc.Series()]++
+	doc.ID = primitive.NewObjectID()
+	doc.SetSequence(r.taxInvoiceSeries[doc.Series()])
+	r.taxInvoices = append(r.taxInvoices, *doc)
+	return nil
+}
+
+func (r *taxInvoiceRepository) FindByNumber(ctx context.Context, number string) (*models.TaxInvoice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, d := range r.taxInvoices {
+		if d.Number == number {
+			return &d, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *taxInvoiceRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.TaxInvoice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	docs := []models.TaxInvoice{}
+	for _, d := range r.taxInvoices {
+		if d.BookingID == bookingID {
+			docs = append(docs, d)
+		}
+	}
+	return docs, nil
+}
+
+func (r *taxInvoiceRepository) List(ctx context.Context, financialYear, kind string) ([]models.TaxInvoice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	docs := []models.TaxInvoice{}
+	for _, d := range r.taxInvoices {
+		if d.FinancialYear == financialYear && (kind == "" || d.Kind == kind) {
+			docs = append(docs, d)
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,180 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/modernband/booking/internal/auth"
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// adminColumns lists the admin_users columns in the order scanAdmin reads them
+const adminColumns = `id, name, mobile_number, email, username, password, is_admin_user, role, created_at, updated_at`
+
+// adminRepository implements storage.AdminRepository on SQLite
+type adminRepository struct {
+	db *sql.DB
+}
+
+// scanAdmin reads a row selected with adminColumns
+func scanAdmin(row scanner) (*models.AdminUser, error) {
+	var a models.AdminUser
+	var id, createdAt, updatedAt string
+	err := row.Scan(&id, &a.Name, &a.MobileNumber, &a.Email, &a.Username, &a.Password, &a.IsAdminUser, &a.Role, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if a.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if a.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if a.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *adminRepository) Create(ctx context.Context, a *models.AdminUser) error {
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+	_, err := r.db.ExecContext(ctx, `INSERT INTO admin_users (`+adminColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID.Hex(), a.Name, a.MobileNumber, a.Email, a.Username, a.Password, a.IsAdminUser, a.Role,
+		formatTime(a.CreatedAt), formatTime(a.UpdatedAt))
+	if isUniqueViolation(err) {
+		return storage.ErrDuplicate
+	}
+	return err
+}
+
+func (r *adminRepository) CreateFirst(ctx context.Context, a *models.AdminUser) error {
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+	result, err := r.db.ExecContext(ctx, `INSERT INTO admin_users (`+adminColumns+`)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM admin_users)`,
+		a.ID.Hex(), a.Name, a.MobileNumber, a.Email, a.Username, a.Password, a.IsAdminUser, a.Role,
+		formatTime(a.CreatedAt), formatTime(a.UpdatedAt))
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrConflict
+	}
+	return nil
+}
+
+func (r *adminRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM admin_users`).Scan(&count)
+	return count, err
+}
+
+func (r *adminRepository) Exists(ctx context.Context, username string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM admin_users WHERE username = ?)`, username).Scan(&exists)
+	return exists, err
+}
+
+func (r *adminRepository) FindByUsername(ctx context.Context, username string) (*models.AdminUser, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+adminColumns+` FROM admin_users WHERE username = ?`, username)
+	admin, err := scanAdmin(row)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return admin, err
+}
+
+func (r *adminRepository) List(ctx context.Context) ([]models.AdminUser, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+adminColumns+` FROM admin_users ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	admins := []models.AdminUser{}
+	for rows.Next() {
+		admin, err := scanAdmin(rows)
+		if err != nil {
+			return nil, err
+		}
+		admins = append(admins, *admin)
+	}
+	return admins, rows.Err()
+}
+
+func (r *adminRepository) Update(ctx context.Context, a *models.AdminUser) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if !a.IsOwner() {
+		if err := keepOwner(ctx, tx, a.ID); err != nil {
+			return err
+		}
+	}
+	result, err := tx.ExecContext(ctx, `UPDATE admin_users
+		SET name = ?, mobile_number = ?, email = ?, password = ?, role = ?, updated_at = ?
+		WHERE id = ?`,
+		a.Name, a.MobileNumber, a.Email, a.Password, a.Role, formatTime(a.UpdatedAt), a.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return tx.Commit()
+}
+
+func (r *adminRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := keepOwner(ctx, tx, id); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM admin_users WHERE id = ?`, id.Hex())
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return tx.Commit()
+}
+
+// keepOwner returns ErrLastOwner if the admin user is an owner and no other
+// owner exists, so they may not stop being one
+func keepOwner(ctx context.Context, tx *sql.Tx, id primitive.ObjectID) error {
+	roles := auth.OwnerRoles()
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(roles)), ", ")
+	args := []interface{}{id.Hex()}
+	for _, role := range roles {
+		args = append(args, role)
+	}
+	args = append(args, args...)
+
+	var owner bool
+	var others int
+	err := tx.QueryRowContext(ctx, `SELECT
+		EXISTS (SELECT 1 FROM admin_users WHERE id = ? AND role IN (`+placeholders+`)),
+		(SELECT COUNT(*) FROM admin_users WHERE id != ? AND role IN (`+placeholders+`))`,
+		args...).Scan(&owner, &others)
+	if err != nil {
+		return err
+	}
+	if owner && others == 0 {
+		return storage.ErrLastOwner
+	}
+	return nil
+}
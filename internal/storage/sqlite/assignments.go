@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// assignmentColumns lists the crew_assignments columns in the order scanAssignment reads them
+const assignmentColumns = `id, booking_id, employee_id, username, role, wage, event_date, time_slot, assigned_by, assigned_at`
+
+// assignmentRepository implements storage.AssignmentRepository on SQLite
+type assignmentRepository struct {
+	db *sql.DB
+}
+
+// scanAssignment reads a row selected with assignmentColumns
+func scanAssignment(row scanner) (*models.CrewAssignment, error) {
+	var a models.CrewAssignment
+	var id, empID, eventDate, assignedAt string
+	err := row.Scan(&id, &a.BookingID, &empID, &a.Username, &a.Role, &a.Wage, &eventDate, &a.TimeSlot, &a.AssignedBy, &assignedAt)
+	if err != nil {
+		return nil, err
+	}
+	if a.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if a.EmployeeID, err = parseObjectID(empID); err != nil {
+		return nil, err
+	}
+	if a.EventDate, err = parseTime(eventDate); err != nil {
+		return nil, err
+	}
+	if a.AssignedAt, err = parseTime(assignedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *assignmentRepository) Create(ctx context.Context, a *models.CrewAssignment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Compare against the employee's other assignments on the same day
+	sameDay, err := sameDayAssignments(ctx, tx, a)
+	if err != nil {
+		return err
+	}
+	for i := range sameDay {
+		if sameDay[i].BookingID == a.BookingID {
+			return storage.ErrDuplicate
+		}
+		if sameDay[i].Overlaps(a) {
+			return storage.ErrConflict
+		}
+	}
+
+	id := primitive.NewObjectID()
+	_, err = tx.ExecContext(ctx, `INSERT INTO crew_assignments (`+assignmentColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(), a.BookingID, a.EmployeeID.Hex(), a.Username, a.Role, a.Wage, formatTime(a.EventDate), a.TimeSlot,
+		a.AssignedBy, formatTime(a.AssignedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrDuplicate
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	a.ID = id
+	return nil
+}
+
+// sameDayAssignments returns the employee's assignments outside the trash on the
+// day of a
+func sameDayAssignments(ctx context.Context, tx *sql.Tx, a *models.CrewAssignment) ([]models.CrewAssignment, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT `+assignmentColumns+` FROM crew_assignments
+		WHERE employee_id = ? AND substr(event_date, 1, 10) = ? AND deleted_at IS NULL`,
+		a.EmployeeID.Hex(), formatTime(a.EventDate)[:10])
+	if err != nil {
+		return nil, err
+	}
+	return collectAssignments(rows)
+}
+
+// restoreCrew brings back the crew of a booking taken out of the trash. The
+// assignments of employees now in the trash, or since assigned to another event
+// at the same time, are removed instead.
+func restoreCrew(ctx context.Context, tx *sql.Tx, bookingID string) error {
+	rows, err := tx.QueryContext(ctx, `SELECT `+assignmentColumns+` FROM crew_assignments
+		WHERE booking_id = ? AND deleted_at IS NOT NULL`, bookingID)
+	if err != nil {
+		return err
+	}
+	trashed, err := collectAssignments(rows)
+	if err != nil {
+		return err
+	}
+
+	for i := range trashed {
+		a := &trashed[i]
+		var free bool
+		err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM employees WHERE id = ? AND deleted_at IS NULL)`,
+			a.EmployeeID.Hex()).Scan(&free)
+		if err != nil {
+			return err
+		}
+		if free {
+			sameDay, err := sameDayAssignments(ctx, tx, a)
+			if err != nil {
+				return err
+			}
+			for j := range sameDay {
+				free = free && !sameDay[j].Overlaps(a)
+			}
+		}
+
+		query := `UPDATE crew_assignments SET deleted_at = NULL WHERE id = ?`
+		if !free {
+			query = `DELETE FROM crew_assignments WHERE id = ?`
+		}
+		if _, err := tx.ExecContext(ctx, query, a.ID.Hex()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *assignmentRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.CrewAssignment, error) {
+	return r.query(ctx, `SELECT `+assignmentColumns+` FROM crew_assignments
+		WHERE booking_id = ? AND deleted_at IS NULL ORDER BY assigned_at`, bookingID)
+}
+
+func (r *assignmentRepository) ListByEmployee(ctx context.Context, employeeID primitive.ObjectID, from time.Time) ([]models.CrewAssignment, error) {
+	return r.query(ctx, `SELECT `+assignmentColumns+` FROM crew_assignments
+		WHERE employee_id = ? AND event_date >= ? AND deleted_at IS NULL ORDER BY event_date, time_slot`,
+		employeeID.Hex(), formatTime(from))
+}
+
+// query runs a SELECT of assignmentColumns and collects the rows
+func (r *assignmentRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.CrewAssignment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return collectAssignments(rows)
+}
+
+// collectAssignments scans and closes rows selected with assignmentColumns
+func collectAssignments(rows *sql.Rows) ([]models.CrewAssignment, error) {
+	defer rows.Close()
+
+	assignments := []models.CrewAssignment{}
+	for rows.Next() {
+		a, err := scanAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, *a)
+	}
+	return assignments, rows.Err()
+}
+
+func (r *assignmentRepository) Delete(ctx context.Context, bookingID string, id primitive.ObjectID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM crew_assignments WHERE id = ? AND booking_id = ? AND deleted_at IS NULL`,
+		id.Hex(), bookingID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *assignmentRepository) DeleteByBooking(ctx context.Context, bookingID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM crew_assignments WHERE booking_id = ?`, bookingID)
+	return err
+}
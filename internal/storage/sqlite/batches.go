@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+)
+
+// batches runs query, which must end with LIMIT ? OFFSET ?, for successive pages
+// of at most size rows from offset, stopping after limit rows if limit is positive,
+// and calls fn with each page. Each page is read and its rows closed before fn
+// runs, so a slow consumer does not hold the store's only connection.
+func batches[T any](ctx context.Context, db *sql.DB, query string, args []interface{}, offset, limit, size int,
+	scan func(scanner) (*T, error), fn func([]T) error) error {
+	for {
+		n := size
+		if limit > 0 {
+			n = min(n, limit)
+		}
+		page, err := readPage(ctx, db, query, append(args[:len(args):len(args)], n, offset), scan)
+		if err != nil {
+			return err
+		}
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		if len(page) < n {
+			return nil
+		}
+		offset += n
+		if limit > 0 {
+			if limit -= n; limit == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// sqlLimit returns limit as a LIMIT value, mapping 0 to -1, which SQLite reads as no limit
+func sqlLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+// readPage runs query and scans every row
+func readPage[T any](ctx context.Context, db *sql.DB, query string, args []interface{}, scan func(scanner) (*T, error)) ([]T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := []T{}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, *item)
+	}
+	return page, rows.Err()
+}
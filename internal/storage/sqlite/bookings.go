@@ -0,0 +1,594 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// bookingColumns lists the bookings columns in the order scanBooking reads them
+const bookingColumns = `id, booking_id, name, email, phone, additional_phone, package_type, event_date,
+	venue, city, customization, band_time, custom_time_slot, number_of_people, number_of_lights,
+	number_of_dhols, ghoda_baggi, ghodi_for_baraat, fireworks, fireworks_amount, flower_canon,
+	doli_for_vidai, amount, advance_payment, phone_verified, created_at, quote, time_slot,
+	status, status_history, revision, archived_at, deleted_at, deleted_by`
+
+// bookingRepository implements storage.BookingRepository on SQLite
+type bookingRepository struct {
+	db *sql.DB
+}
+
+// scanBooking reads a row selected with bookingColumns
+func scanBooking(row scanner) (*models.Booking, error) {
+	var b models.Booking
+	var id, eventDate, createdAt string
+	var quote, history, archivedAt, deletedAt sql.NullString
+	err := row.Scan(&id, &b.BookingID, &b.Name, &b.Email, &b.Phone, &b.AdditionalPhone, &b.PackageType, &eventDate,
+		&b.Venue, &b.City, &b.Customization, &b.BandTime, &b.CustomTimeSlot, &b.NumberOfPeople, &b.NumberOfLights,
+		&b.NumberOfDhols, &b.GhodaBaggi, &b.GhodiForBaraat, &b.Fireworks, &b.FireworksAmount, &b.FlowerCanon,
+		&b.DoliForVidai, &b.Amount, &b.AdvancePayment, &b.PhoneVerified, &createdAt, &quote, &b.TimeSlot,
+		&b.Status, &history, &b.Revision, &archivedAt, &deletedAt, &b.DeletedBy)
+	if err != nil {
+		return nil, err
+	}
+	if b.ArchivedAt, err = parseNullTime(archivedAt); err != nil {
+		return nil, err
+	}
+	if b.DeletedAt, err = parseNullTime(deletedAt); err != nil {
+		return nil, err
+	}
+	if err = unmarshalJSON(quote, &b.Quote); err != nil {
+		return nil, err
+	}
+	if err = unmarshalJSON(history, &b.StatusHistory); err != nil {
+		return nil, err
+	}
+	if b.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if b.EventDate, err = parseTime(eventDate); err != nil {
+		return nil, err
+	}
+	if b.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertBooking inserts a booking and sets its ID
+func insertBooking(ctx context.Context, db execer, b *models.Booking) error {
+	quote, err := marshalJSON(b.Quote)
+	if err != nil {
+		return err
+	}
+	history, err := marshalJSON(b.StatusHistory)
+	if err != nil {
+		return err
+	}
+
+	id := primitive.NewObjectID()
+	_, err = db.ExecContext(ctx, `INSERT INTO bookings (`+bookingColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(), b.BookingID, b.Name, b.Email, b.Phone, b.AdditionalPhone, b.PackageType, formatTime(b.EventDate),
+		b.Venue, b.City, b.Customization, b.BandTime, b.CustomTimeSlot, b.NumberOfPeople, b.NumberOfLights,
+		b.NumberOfDhols, b.GhodaBaggi, b.GhodiForBaraat, b.Fireworks, b.FireworksAmount, b.FlowerCanon,
+		b.DoliForVidai, b.Amount, b.AdvancePayment, b.PhoneVerified, formatTime(b.CreatedAt), quote, b.TimeSlot,
+		b.Status, history, b.Revision, formatNullTime(b.ArchivedAt), formatNullTime(b.DeletedAt), b.DeletedBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrDuplicate
+		}
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+func (r *bookingRepository) Create(ctx context.Context, b *models.Booking) error {
+	return insertBooking(ctx, r.db, b)
+}
+
+func (r *bookingRepository) CreateMany(ctx context.Context, bookings []models.Booking) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range bookings {
+		if err := insertBooking(ctx, tx, &bookings[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *bookingRepository) CreateWithinCapacity(ctx context.Context, b *models.Booking, limits storage.CapacityLimits, verifiedSince time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkCapacity(ctx, tx, b, limits); err != nil {
+		return err
+	}
+	if err := insertBooking(ctx, tx, b); err != nil {
+		return err
+	}
+
+	// Use up the phone's verification only together with the insert
+	result, err := tx.ExecContext(ctx, `DELETE FROM otps WHERE phone = ? AND verified_at > ? AND expires_at > ?`,
+		b.Phone, formatTime(verifiedSince), formatTime(time.Now()))
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return storage.ErrNotVerified
+	}
+	return tx.Commit()
+}
+
+// checkCapacity returns ErrCapacityExceeded if the other bookings on the booking's
+// day and time slot already reach limits
+func checkCapacity(ctx context.Context, tx *sql.Tx, b *models.Booking, limits storage.CapacityLimits) error {
+	// Compare by calendar day; event_date is stored as sortable UTC text
+	day := formatTime(b.EventDate)[:10]
+	var dayCount, slotCount int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*), COUNT(CASE WHEN time_slot = ? THEN 1 END)
+		FROM bookings WHERE substr(event_date, 1, 10) = ? AND status != ? AND booking_id != ? AND deleted_at IS NULL`,
+		b.TimeSlot, day, models.BookingStatusCancelled, b.BookingID).Scan(&dayCount, &slotCount)
+	if err != nil {
+		return err
+	}
+
+	if limits.PerDay > 0 && dayCount >= limits.PerDay {
+		return storage.ErrCapacityExceeded
+	}
+	if limits.PerSlot > 0 && b.TimeSlot != "" && slotCount >= limits.PerSlot {
+		return storage.ErrCapacityExceeded
+	}
+
+	if len(limits.Stock) > 0 {
+		reserved := map[string]int{}
+		var lights, dhols, ghodaBaggi, ghodi int
+		err := tx.QueryRowContext(ctx, `SELECT `+equipmentSums+`
+			FROM bookings WHERE substr(event_date, 1, 10) = ? AND status != ? AND booking_id != ? AND deleted_at IS NULL`,
+			day, models.BookingStatusCancelled, b.BookingID).Scan(&lights, &dhols, &ghodaBaggi, &ghodi)
+		if err != nil {
+			return err
+		}
+		reserved[models.ItemLights], reserved[models.ItemDhols] = lights, dhols
+		reserved[models.ItemGhodaBaggi], reserved[models.ItemGhodi] = ghodaBaggi, ghodi
+		if limits.StockExceeded(reserved, b.Equipment()) {
+			return storage.ErrInsufficientStock
+		}
+	}
+	return nil
+}
+
+// equipmentSums selects the total of each inventory item requested by the matched
+// bookings, in the order lights, dhols, ghoda baggi, ghodi. Negative counts are
+// read as 0 so they cannot free equipment other bookings are using.
+const equipmentSums = `COALESCE(SUM(MAX(number_of_lights, 0)), 0), COALESCE(SUM(MAX(number_of_dhols, 0)), 0),
+	COALESCE(SUM(MAX(ghoda_baggi, 0)), 0), COALESCE(SUM(ghodi_for_baraat), 0)`
+
+func (r *bookingRepository) ReservedEquipment(ctx context.Context, from, to time.Time) ([]models.EquipmentReservation, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT substr(event_date, 1, 10) AS day, `+equipmentSums+`
+		FROM bookings WHERE substr(event_date, 1, 10) BETWEEN ? AND ? AND status != ? AND deleted_at IS NULL
+		GROUP BY day ORDER BY day`,
+		formatTime(from)[:10], formatTime(to)[:10], models.BookingStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := []models.EquipmentReservation{}
+	for rows.Next() {
+		var day string
+		var lights, dhols, ghodaBaggi, ghodi int
+		if err := rows.Scan(&day, &lights, &dhols, &ghodaBaggi, &ghodi); err != nil {
+			return nil, err
+		}
+		// Items in code order, skipping those no booking requested
+		for _, item := range []models.EquipmentReservation{
+			{Day: day, Item: models.ItemDhols, Quantity: dhols},
+			{Day: day, Item: models.ItemGhodaBaggi, Quantity: ghodaBaggi},
+			{Day: day, Item: models.ItemGhodi, Quantity: ghodi},
+			{Day: day, Item: models.ItemLights, Quantity: lights},
+		} {
+			if item.Quantity > 0 {
+				reservations = append(reservations, item)
+			}
+		}
+	}
+	return reservations, rows.Err()
+}
+
+func (r *bookingRepository) CountBySlot(ctx context.Context, from, to time.Time) ([]models.SlotCount, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT substr(event_date, 1, 10) AS day, time_slot, COUNT(*)
+		FROM bookings WHERE substr(event_date, 1, 10) BETWEEN ? AND ? AND status != ? AND deleted_at IS NULL
+		GROUP BY day, time_slot ORDER BY day, time_slot`,
+		formatTime(from)[:10], formatTime(to)[:10], models.BookingStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.SlotCount{}
+	for rows.Next() {
+		var sc models.SlotCount
+		if err := rows.Scan(&sc.Day, &sc.Slot, &sc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, sc)
+	}
+	return counts, rows.Err()
+}
+
+func (r *bookingRepository) Exists(ctx context.Context, bookingID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM bookings WHERE booking_id = ?)`, bookingID).Scan(&exists)
+	return exists, err
+}
+
+func (r *bookingRepository) FindByBookingID(ctx context.Context, bookingID string) (*models.Booking, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+bookingColumns+` FROM bookings WHERE booking_id = ? AND deleted_at IS NULL`, bookingID)
+	booking, err := scanBooking(row)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return booking, err
+}
+
+func (r *bookingRepository) FindByPhone(ctx context.Context, phone string) ([]models.Booking, error) {
+	return r.query(ctx, `SELECT `+bookingColumns+` FROM bookings WHERE phone = ? AND deleted_at IS NULL ORDER BY created_at DESC`, phone)
+}
+
+func (r *bookingRepository) Search(ctx context.Context, query string, limit int) ([]models.Booking, error) {
+	terms := storage.SearchTerms(query)
+	if len(terms) == 0 {
+		return []models.Booking{}, nil
+	}
+	matches, args := bookingsFTS.matches(terms)
+	return r.query(ctx, `SELECT `+bookingColumns+` FROM bookings JOIN (`+matches+`) ON id = fts_id
+		WHERE deleted_at IS NULL ORDER BY fts_rank, created_at DESC LIMIT ?`, append(args, limit)...)
+}
+
+// bookingSortColumns maps listing sort fields to columns
+var bookingSortColumns = map[string]string{
+	storage.SortCreatedAt: "created_at",
+	storage.SortEventDate: "event_date",
+	storage.SortAmount:    "amount",
+	storage.SortName:      "name",
+}
+
+// bookingWhere returns the WHERE clause and arguments selecting the bookings that match filter
+func bookingWhere(filter storage.BookingFilter) (string, []interface{}) {
+	where := ` WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	if filter.Status != "" {
+		where += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	if !filter.From.IsZero() {
+		where += ` AND event_date >= ?`
+		args = append(args, formatTime(filter.From))
+	}
+	if !filter.Before.IsZero() {
+		where += ` AND event_date < ?`
+		args = append(args, formatTime(filter.Before))
+	}
+	if filter.City != "" {
+		where += ` AND city = ? COLLATE NOCASE`
+		args = append(args, filter.City)
+	}
+	if filter.PackageType != "" {
+		where += ` AND package_type = ?`
+		args = append(args, filter.PackageType)
+	}
+	if filter.Phone != "" {
+		where += ` AND phone = ?`
+		args = append(args, filter.Phone)
+	}
+	switch filter.Archived {
+	case storage.ArchivedExclude:
+		where += ` AND archived_at IS NULL`
+	case storage.ArchivedOnly:
+		where += ` AND archived_at IS NOT NULL`
+	}
+	return where, args
+}
+
+// bookingOrder returns the ORDER BY clause for page's sort order
+func bookingOrder(page storage.BookingPage) string {
+	column, ok := bookingSortColumns[page.Sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "ASC"
+	if page.Desc {
+		direction = "DESC"
+	}
+	return ` ORDER BY ` + column + ` ` + direction + `, booking_id`
+}
+
+func (r *bookingRepository) List(ctx context.Context, filter storage.BookingFilter, page storage.BookingPage) ([]models.Booking, int64, error) {
+	where, args := bookingWhere(filter)
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM bookings`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// SQLite treats a negative LIMIT as no limit
+	limit := page.Limit
+	if limit == 0 {
+		limit = -1
+	}
+	bookings, err := r.query(ctx, `SELECT `+bookingColumns+` FROM bookings`+where+bookingOrder(page)+` LIMIT ? OFFSET ?`,
+		append(args, limit, page.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bookings, total, nil
+}
+
+func (r *bookingRepository) Batches(ctx context.Context, filter storage.BookingFilter, page storage.BookingPage, size int, fn func([]models.Booking) error) error {
+	where, args := bookingWhere(filter)
+	return batches(ctx, r.db, `SELECT `+bookingColumns+` FROM bookings`+where+bookingOrder(page)+` LIMIT ? OFFSET ?`,
+		args, page.Offset, page.Limit, size, scanBooking, fn)
+}
+
+func (r *bookingRepository) UpdateStatus(ctx context.Context, bookingID, from string, change models.StatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var history sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT status, status_history FROM bookings WHERE booking_id = ? AND deleted_at IS NULL`, bookingID).
+		Scan(&status, &history)
+	if err == sql.ErrNoRows {
+		return storage.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != from {
+		return storage.ErrConflict
+	}
+
+	var changes []models.StatusChange
+	if err := unmarshalJSON(history, &changes); err != nil {
+		return err
+	}
+	if history, err = marshalJSON(append(changes, change)); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE bookings SET status = ?, status_history = ? WHERE booking_id = ?`,
+		change.To, history, bookingID)
+	if err != nil {
+		return err
+	}
+	if change.To == models.BookingStatusCompleted {
+		if err := accrueEarnings(ctx, tx, bookingID, change.At); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// query runs a SELECT of bookingColumns and collects the rows
+func (r *bookingRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.Booking, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookings := []models.Booking{}
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, *booking)
+	}
+	return bookings, rows.Err()
+}
+
+func (r *bookingRepository) Update(ctx context.Context, b *models.Booking, revision *models.BookingRevision, limits *storage.CapacityLimits) error {
+	quote, err := marshalJSON(b.Quote)
+	if err != nil {
+		return err
+	}
+	changes, err := marshalJSON(revision.Changes)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if limits != nil {
+		if err := checkCapacity(ctx, tx, b, *limits); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE bookings SET name = ?, email = ?, additional_phone = ?,
+		package_type = ?, event_date = ?, venue = ?, city = ?, customization = ?, band_time = ?,
+		custom_time_slot = ?, time_slot = ?, number_of_people = ?, number_of_lights = ?, number_of_dhols = ?,
+		ghoda_baggi = ?, ghodi_for_baraat = ?, fireworks = ?, fireworks_amount = ?, flower_canon = ?,
+		doli_for_vidai = ?, amount = ?, quote = ?, revision = ?
+		WHERE booking_id = ? AND revision = ? AND status = ? AND deleted_at IS NULL`,
+		b.Name, b.Email, b.AdditionalPhone, b.PackageType, formatTime(b.EventDate), b.Venue, b.City,
+		b.Customization, b.BandTime, b.CustomTimeSlot, b.TimeSlot, b.NumberOfPeople, b.NumberOfLights,
+		b.NumberOfDhols, b.GhodaBaggi, b.GhodiForBaraat, b.Fireworks, b.FireworksAmount, b.FlowerCanon,
+		b.DoliForVidai, b.Amount, quote, b.Revision,
+		b.BookingID, b.Revision-1, b.CurrentStatus())
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		var exists bool
+		err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM bookings WHERE booking_id = ? AND deleted_at IS NULL)`,
+			b.BookingID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return storage.ErrNotFound
+		}
+		return storage.ErrConflict
+	}
+
+	id := primitive.NewObjectID()
+	_, err = tx.ExecContext(ctx, `INSERT INTO booking_revisions (id, booking_id, revision, changes, changed_by, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		id.Hex(), revision.BookingID, revision.Revision, changes, revision.ChangedBy, formatTime(revision.ChangedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrConflict
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	revision.ID = id
+	return nil
+}
+
+func (r *bookingRepository) ListRevisions(ctx context.Context, bookingID string) ([]models.BookingRevision, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, booking_id, revision, changes, changed_by, changed_at
+		FROM booking_revisions WHERE booking_id = ? ORDER BY revision`, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.BookingRevision{}
+	for rows.Next() {
+		var rev models.BookingRevision
+		var id, changedAt string
+		var changes sql.NullString
+		if err := rows.Scan(&id, &rev.BookingID, &rev.Revision, &changes, &rev.ChangedBy, &changedAt); err != nil {
+			return nil, err
+		}
+		if err := unmarshalJSON(changes, &rev.Changes); err != nil {
+			return nil, err
+		}
+		if rev.ID, err = parseObjectID(id); err != nil {
+			return nil, err
+		}
+		if rev.ChangedAt, err = parseTime(changedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *bookingRepository) Delete(ctx context.Context, bookingID, by string, at time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE bookings SET deleted_at = ?, deleted_by = ?
+		WHERE booking_id = ? AND deleted_at IS NULL`, formatTime(at), by, bookingID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE crew_assignments SET deleted_at = ?
+		WHERE booking_id = ? AND deleted_at IS NULL`,
+		formatTime(at), bookingID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *bookingRepository) FindDeleted(ctx context.Context, bookingID string) (*models.Booking, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+bookingColumns+` FROM bookings WHERE booking_id = ? AND deleted_at IS NOT NULL`, bookingID)
+	booking, err := scanBooking(row)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return booking, err
+}
+
+func (r *bookingRepository) Restore(ctx context.Context, bookingID string, limits *storage.CapacityLimits) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	b, err := scanBooking(tx.QueryRowContext(ctx, `SELECT `+bookingColumns+` FROM bookings
+		WHERE booking_id = ? AND deleted_at IS NOT NULL`, bookingID))
+	if err == sql.ErrNoRows {
+		return storage.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if limits != nil {
+		if err := checkCapacity(ctx, tx, b, *limits); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE bookings SET deleted_at = NULL, deleted_by = '' WHERE booking_id = ?`, bookingID); err != nil {
+		return err
+	}
+	if err := restoreCrew(ctx, tx, bookingID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *bookingRepository) ListDeleted(ctx context.Context, limit int) ([]models.Booking, error) {
+	return r.query(ctx, `SELECT `+bookingColumns+` FROM bookings WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, booking_id LIMIT ?`, sqlLimit(limit))
+}
+
+func (r *bookingRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM bookings WHERE deleted_at < ?`, formatTime(t))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *bookingRepository) ArchiveBefore(ctx context.Context, t, at time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE bookings SET archived_at = ?
+		WHERE event_date < ? AND status IN (?, ?, ?) AND archived_at IS NULL AND deleted_at IS NULL`,
+		formatTime(at), formatTime(t), models.BookingStatusEnquiry, models.BookingStatusCompleted, models.BookingStatusCancelled)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// customerPaymentRepository implements storage.CustomerPaymentRepository on SQLite
+type customerPaymentRepository struct {
+	db *sql.DB
+}
+
+// customerPaymentColumns lists the customer_payments columns in the order scanCustomerPayment reads them
+const customerPaymentColumns = `id, booking_id, amount, method, reference, received_by,
+	paid_at, created_at, voided_at, voided_by, void_reason`
+
+// scanCustomerPayment reads a row selected with customerPaymentColumns
+func scanCustomerPayment(row scanner) (*models.CustomerPayment, error) {
+	var p models.CustomerPayment
+	var id, paidAt, createdAt string
+	var voidedAt sql.NullString
+	err := row.Scan(&id, &p.BookingID, &p.Amount, &p.Method, &p.Reference, &p.ReceivedBy,
+		&paidAt, &createdAt, &voidedAt, &p.VoidedBy, &p.VoidReason)
+	if err != nil {
+		return nil, err
+	}
+	if p.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if p.PaidAt, err = parseTime(paidAt); err != nil {
+		return nil, err
+	}
+	if p.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if p.VoidedAt, err = parseNullTime(voidedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *customerPaymentRepository) Create(ctx context.Context, p *models.CustomerPayment) error {
+	id := primitive.NewObjectID()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO customer_payments
+		(id, booking_id, amount, method, reference, received_by, paid_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(), p.BookingID, p.Amount, p.Method, p.Reference, p.ReceivedBy, formatTime(p.PaidAt), formatTime(p.CreatedAt))
+	if err != nil {
+		return err
+	}
+	p.ID = id
+	return nil
+}
+
+func (r *customerPaymentRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.CustomerPayment, error) {
+	return readPage(ctx, r.db, `SELECT `+customerPaymentColumns+`
+		FROM customer_payments WHERE booking_id = ? ORDER BY paid_at, created_at`, []interface{}{bookingID}, scanCustomerPayment)
+}
+
+func (r *customerPaymentRepository) Void(ctx context.Context, bookingID string, paymentID primitive.ObjectID, by, reason string, at time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE customer_payments SET voided_at = ?, voided_by = ?, void_reason = ?
+		WHERE id = ? AND booking_id = ? AND voided_at IS NULL`,
+		formatTime(at), by, reason, paymentID.Hex(), bookingID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		var exists bool
+		err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM customer_payments WHERE id = ? AND booking_id = ?)`,
+			paymentID.Hex(), bookingID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return storage.ErrNotFound
+		}
+		return storage.ErrConflict
+	}
+	return nil
+}
+
+func (r *customerPaymentRepository) TotalsByBooking(ctx context.Context, bookingIDs []string) (map[string]int, error) {
+	totals := map[string]int{}
+	if len(bookingIDs) == 0 {
+		return totals, nil
+	}
+
+	args := make([]interface{}, len(bookingIDs))
+	for i, id := range bookingIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(bookingIDs)), ", ")
+
+	rows, err := r.db.QueryContext(ctx, `SELECT booking_id, SUM(amount) FROM customer_payments
+		WHERE booking_id IN (`+placeholders+`) AND voided_at IS NULL GROUP BY booking_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookingID string
+		var total int
+		if err := rows.Scan(&bookingID, &total); err != nil {
+			return nil, err
+		}
+		totals[bookingID] = total
+	}
+	return totals, rows.Err()
+}
+
+func (r *customerPaymentRepository) Batches(ctx context.Context, filter storage.CustomerPaymentFilter, size int, fn func([]models.CustomerPayment) error) error {
+	where := ` WHERE 1 = 1`
+	args := []interface{}{}
+	if !filter.From.IsZero() {
+		where += ` AND paid_at >= ?`
+		args = append(args, formatTime(filter.From))
+	}
+	if !filter.Before.IsZero() {
+		where += ` AND paid_at < ?`
+		args = append(args, formatTime(filter.Before))
+	}
+	return batches(ctx, r.db, `SELECT `+customerPaymentColumns+` FROM customer_payments`+where+
+		` ORDER BY paid_at, created_at, id LIMIT ? OFFSET ?`, args, 0, 0, size, scanCustomerPayment, fn)
+}
@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// earningRepository implements storage.EarningRepository on SQLite
+type earningRepository struct {
+	db *sql.DB
+}
+
+// earningWhere builds the WHERE clause and arguments selecting the earnings matching filter
+func earningWhere(filter storage.EarningFilter) (string, []interface{}) {
+	where := ` WHERE 1 = 1`
+	args := []interface{}{}
+	if !filter.EmployeeID.IsZero() {
+		where += ` AND employee_id = ?`
+		args = append(args, filter.EmployeeID.Hex())
+	}
+	if !filter.From.IsZero() {
+		where += ` AND event_date >= ?`
+		args = append(args, formatTime(filter.From))
+	}
+	if !filter.Before.IsZero() {
+		where += ` AND event_date < ?`
+		args = append(args, formatTime(filter.Before))
+	}
+	return where, args
+}
+
+func (r *earningRepository) List(ctx context.Context, filter storage.EarningFilter) ([]models.Earning, error) {
+	where, args := earningWhere(filter)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, employee_id, booking_id, role, event_date, amount, accrued_at
+		FROM earnings`+where+` ORDER BY event_date`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	earnings := []models.Earning{}
+	for rows.Next() {
+		var e models.Earning
+		var id, empID, eventDate, accruedAt string
+		if err := rows.Scan(&id, &empID, &e.BookingID, &e.Role, &eventDate, &e.Amount, &accruedAt); err != nil {
+			return nil, err
+		}
+		if e.ID, err = parseObjectID(id); err != nil {
+			return nil, err
+		}
+		if e.EmployeeID, err = parseObjectID(empID); err != nil {
+			return nil, err
+		}
+		if e.EventDate, err = parseTime(eventDate); err != nil {
+			return nil, err
+		}
+		if e.AccruedAt, err = parseTime(accruedAt); err != nil {
+			return nil, err
+		}
+		earnings = append(earnings, e)
+	}
+	return earnings, rows.Err()
+}
+
+func (r *earningRepository) TotalsByEmployee(ctx context.Context, filter storage.EarningFilter) (map[primitive.ObjectID]models.PaymentTotal, error) {
+	where, args := earningWhere(filter)
+	rows, err := r.db.QueryContext(ctx, `SELECT employee_id, SUM(amount), COUNT(*) FROM earnings`+where+`
+		GROUP BY employee_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := map[primitive.ObjectID]models.PaymentTotal{}
+	for rows.Next() {
+		var empID string
+		var total models.PaymentTotal
+		if err := rows.Scan(&empID, &total.Amount, &total.Count); err != nil {
+			return nil, err
+		}
+		id, err := parseObjectID(empID)
+		if err != nil {
+			return nil, err
+		}
+		totals[id] = total
+	}
+	return totals, rows.Err()
+}
+
+// accrueEarnings records an earning for each crew assignment of a booking within tx
+func accrueEarnings(ctx context.Context, tx *sql.Tx, bookingID string, at time.Time) error {
+	rows, err := tx.QueryContext(ctx, `SELECT `+assignmentColumns+` FROM crew_assignments
+		WHERE booking_id = ? AND deleted_at IS NULL`, bookingID)
+	if err != nil {
+		return err
+	}
+	earnings := []models.Earning{}
+	for rows.Next() {
+		a, err := scanAssignment(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		earnings = append(earnings, models.NewEarning(a, at))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, e := range earnings {
+		_, err := tx.ExecContext(ctx, `INSERT INTO earnings (id, employee_id, booking_id, role, event_date, amount, accrued_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			primitive.NewObjectID().Hex(), e.EmployeeID.Hex(), e.BookingID, e.Role, formatTime(e.EventDate), e.Amount, formatTime(e.AccruedAt))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
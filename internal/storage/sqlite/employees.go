@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// employeeColumns lists the employees columns in the order scanEmployee reads them
+const employeeColumns = `id, name, mobile_number, email, address, is_employee, total_amount_to_be_paid,
+	total_amount_paid_in_advance, username, password, created_at, updated_at, deleted_at, deleted_by`
+
+// employeeRepository implements storage.EmployeeRepository on SQLite
+type employeeRepository struct {
+	db *sql.DB
+}
+
+// scanEmployee reads a row selected with employeeColumns
+func scanEmployee(row scanner) (*models.Employee, error) {
+	var e models.Employee
+	var id, createdAt, updatedAt string
+	var deletedAt sql.NullString
+	err := row.Scan(&id, &e.Name, &e.MobileNumber, &e.Email, &e.Address, &e.IsEmployee, &e.TotalAmountToBePaid,
+		&e.TotalAmountPaidInAdvance, &e.Username, &e.Password, &createdAt, &updatedAt, &deletedAt, &e.DeletedBy)
+	if err != nil {
+		return nil, err
+	}
+	if e.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if e.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if e.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	if e.DeletedAt, err = parseNullTime(deletedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *employeeRepository) Create(ctx context.Context, e *models.Employee) error {
+	return insertEmployee(ctx, r.db, e)
+}
+
+func (r *employeeRepository) CreateMany(ctx context.Context, employees []models.Employee) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range employees {
+		if err := insertEmployee(ctx, tx, &employees[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// insertEmployee inserts an employee and sets its ID, returning ErrDuplicate if the username is taken
+func insertEmployee(ctx context.Context, db execer, e *models.Employee) error {
+	id := primitive.NewObjectID()
+	_, err := db.ExecContext(ctx, `INSERT INTO employees (`+employeeColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(), e.Name, e.MobileNumber, e.Email, e.Address, e.IsEmployee, e.TotalAmountToBePaid,
+		e.TotalAmountPaidInAdvance, e.Username, e.Password, formatTime(e.CreatedAt), formatTime(e.UpdatedAt),
+		formatNullTime(e.DeletedAt), e.DeletedBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrDuplicate
+		}
+		return err
+	}
+	e.ID = id
+	return nil
+}
+
+func (r *employeeRepository) Exists(ctx context.Context, username string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM employees WHERE username = ?)`, username).Scan(&exists)
+	return exists, err
+}
+
+func (r *employeeRepository) FindByUsername(ctx context.Context, username string) (*models.Employee, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+employeeColumns+` FROM employees WHERE username = ? AND deleted_at IS NULL`, username)
+	employee, err := scanEmployee(row)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return employee, err
+}
+
+func (r *employeeRepository) List(ctx context.Context) ([]models.Employee, error) {
+	return r.query(ctx, `SELECT `+employeeColumns+` FROM employees WHERE deleted_at IS NULL ORDER BY created_at DESC`)
+}
+
+func (r *employeeRepository) Search(ctx context.Context, query string, limit int) ([]models.Employee, error) {
+	terms := storage.SearchTerms(query)
+	if len(terms) == 0 {
+		return []models.Employee{}, nil
+	}
+	matches, args := employeesFTS.matches(terms)
+	return r.query(ctx, `SELECT `+employeeColumns+` FROM employees JOIN (`+matches+`) ON id = fts_id
+		WHERE deleted_at IS NULL ORDER BY fts_rank, created_at DESC LIMIT ?`, append(args, limit)...)
+}
+
+// query runs a SELECT of employeeColumns and scans every row
+func (r *employeeRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.Employee, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	employees := []models.Employee{}
+	for rows.Next() {
+		employee, err := scanEmployee(rows)
+		if err != nil {
+			return nil, err
+		}
+		employees = append(employees, *employee)
+	}
+	return employees, rows.Err()
+}
+
+func (r *employeeRepository) Delete(ctx context.Context, id primitive.ObjectID, by string, at time.Time) error {
+	return r.update(ctx, `UPDATE employees SET deleted_at = ?, deleted_by = ? WHERE id = ? AND deleted_at IS NULL`,
+		formatTime(at), by, id.Hex())
+}
+
+func (r *employeeRepository) Restore(ctx context.Context, username string) error {
+	return r.update(ctx, `UPDATE employees SET deleted_at = NULL, deleted_by = '' WHERE username = ? AND deleted_at IS NOT NULL`,
+		username)
+}
+
+// update runs a statement changing one employee, returning ErrNotFound if none matched
+func (r *employeeRepository) update(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *employeeRepository) ListDeleted(ctx context.Context, limit int) ([]models.Employee, error) {
+	return r.query(ctx, `SELECT `+employeeColumns+` FROM employees WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, username LIMIT ?`, sqlLimit(limit))
+}
+
+func (r *employeeRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	// Payments, crew assignments and earnings are removed by ON DELETE CASCADE foreign keys
+	result, err := r.db.ExecContext(ctx, `DELETE FROM employees WHERE deleted_at < ?`, formatTime(t))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
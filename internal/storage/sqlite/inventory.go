@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// inventoryRepository implements storage.InventoryRepository on SQLite
+type inventoryRepository struct {
+	db *sql.DB
+}
+
+func (r *inventoryRepository) List(ctx context.Context) ([]models.InventoryItem, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, code, name, quantity_owned, in_maintenance, maintenance_note, updated_by, updated_at
+		FROM inventory ORDER BY code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.InventoryItem{}
+	for rows.Next() {
+		var item models.InventoryItem
+		var id, updatedAt string
+		err := rows.Scan(&id, &item.Code, &item.Name, &item.QuantityOwned, &item.InMaintenance, &item.MaintenanceNote,
+			&item.UpdatedBy, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if item.ID, err = parseObjectID(id); err != nil {
+			return nil, err
+		}
+		if item.UpdatedAt, err = parseTime(updatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *inventoryRepository) Put(ctx context.Context, item *models.InventoryItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO inventory (id, code, name, quantity_owned, in_maintenance, maintenance_note, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (code) DO UPDATE SET name = excluded.name, quantity_owned = excluded.quantity_owned,
+			in_maintenance = excluded.in_maintenance, maintenance_note = excluded.maintenance_note,
+			updated_by = excluded.updated_by, updated_at = excluded.updated_at`,
+		primitive.NewObjectID().Hex(), item.Code, item.Name, item.QuantityOwned, item.InMaintenance, item.MaintenanceNote,
+		item.UpdatedBy, formatTime(item.UpdatedAt))
+	if err != nil {
+		return err
+	}
+
+	// An existing item keeps its ID
+	var id string
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM inventory WHERE code = ?`, item.Code).Scan(&id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	item.ID, err = parseObjectID(id)
+	return err
+}
+
+func (r *inventoryRepository) Delete(ctx context.Context, code string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM inventory WHERE code = ?`, code)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// jobColumns lists the jobs columns in the order scanJob reads them
+const jobColumns = `name, schedule, enabled, next_run_at, locked_by, locked_until, last_run, updated_by, updated_at`
+
+// jobRunColumns lists the job_runs columns in the order scanJobRun reads them
+const jobRunColumns = `id, job, trigger, triggered_by, instance, status, result, error, started_at, finished_at`
+
+// jobRepository implements storage.JobRepository on SQLite
+type jobRepository struct {
+	db *sql.DB
+}
+
+// scanJob reads a row selected with jobColumns
+func scanJob(row scanner) (*models.JobState, error) {
+	var j models.JobState
+	var nextRunAt, updatedAt string
+	var lockedUntil, lastRun sql.NullString
+	err := row.Scan(&j.Name, &j.Schedule, &j.Enabled, &nextRunAt, &j.LockedBy, &lockedUntil, &lastRun, &j.UpdatedBy, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if j.NextRunAt, err = parseTime(nextRunAt); err != nil {
+		return nil, err
+	}
+	if j.LockedUntil, err = parseNullTime(lockedUntil); err != nil {
+		return nil, err
+	}
+	if lastRun.Valid {
+		j.LastRun = &models.JobRun{}
+		if err := unmarshalJSON(lastRun, j.LastRun); err != nil {
+			return nil, err
+		}
+	}
+	if j.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// scanJobRun reads a row selected with jobRunColumns
+func scanJobRun(row scanner) (*models.JobRun, error) {
+	var run models.JobRun
+	var id, startedAt string
+	var finishedAt sql.NullString
+	err := row.Scan(&id, &run.Job, &run.Trigger, &run.TriggeredBy, &run.Instance, &run.Status, &run.Result, &run.Error,
+		&startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if run.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if run.StartedAt, err = parseTime(startedAt); err != nil {
+		return nil, err
+	}
+	if run.FinishedAt, err = parseNullTime(finishedAt); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *jobRepository) Ensure(ctx context.Context, state *models.JobState) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO jobs (name, schedule, enabled, next_run_at, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO NOTHING`,
+		state.Name, state.Schedule, state.Enabled, formatTime(state.NextRunAt), state.UpdatedBy, formatTime(state.UpdatedAt))
+	return err
+}
+
+func (r *jobRepository) List(ctx context.Context) ([]models.JobState, error) {
+	return readPage(ctx, r.db, `SELECT `+jobColumns+` FROM jobs ORDER BY name`, nil, scanJob)
+}
+
+func (r *jobRepository) Find(ctx context.Context, name string) (*models.JobState, error) {
+	j, err := scanJob(r.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return j, err
+}
+
+func (r *jobRepository) UpdateSchedule(ctx context.Context, state *models.JobState) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE jobs SET schedule = ?, enabled = ?, next_run_at = ?, updated_by = ?, updated_at = ?
+		WHERE name = ?`,
+		state.Schedule, state.Enabled, formatTime(state.NextRunAt), state.UpdatedBy, formatTime(state.UpdatedAt), state.Name)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *jobRepository) Lock(ctx context.Context, name, owner string, now, lockedUntil time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE jobs SET locked_by = ?, locked_until = ?
+		WHERE name = ? AND (locked_until IS NULL OR locked_until <= ?)`,
+		owner, formatTime(lockedUntil), name, formatTime(now))
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *jobRepository) LockDue(ctx context.Context, name, owner string, now, lockedUntil, next time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE jobs SET locked_by = ?, locked_until = ?, next_run_at = ?
+		WHERE name = ? AND enabled AND next_run_at <= ? AND (locked_until IS NULL OR locked_until <= ?)`,
+		owner, formatTime(lockedUntil), formatTime(next), name, formatTime(now), formatTime(now))
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *jobRepository) Unlock(ctx context.Context, name, owner string, run *models.JobRun) error {
+	lastRun, err := marshalJSON(run)
+	if err != nil {
+		return err
+	}
+	// The last run is recorded even if the lock expired and passed to another owner
+	if _, err := r.db.ExecContext(ctx, `UPDATE jobs SET last_run = ? WHERE name = ?`, lastRun, name); err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `UPDATE jobs SET locked_by = '', locked_until = NULL WHERE name = ? AND locked_by = ?`,
+		name, owner)
+	return err
+}
+
+func (r *jobRepository) CreateRun(ctx context.Context, run *models.JobRun) error {
+	run.ID = primitive.NewObjectID()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO job_runs (`+jobRunColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID.Hex(), run.Job, run.Trigger, run.TriggeredBy, run.Instance, run.Status, run.Result, run.Error,
+		formatTime(run.StartedAt), formatNullTime(run.FinishedAt))
+	return err
+}
+
+func (r *jobRepository) FinishRun(ctx context.Context, run *models.JobRun) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE job_runs SET status = ?, result = ?, error = ?, finished_at = ? WHERE id = ?`,
+		run.Status, run.Result, run.Error, formatNullTime(run.FinishedAt), run.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *jobRepository) ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error) {
+	return readPage(ctx, r.db, `SELECT `+jobRunColumns+` FROM job_runs WHERE job = ? ORDER BY started_at DESC, id DESC LIMIT ?`,
+		[]interface{}{job, limit}, scanJobRun)
+}
+
+func (r *jobRepository) DeleteRunsBefore(ctx context.Context, t time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM job_runs WHERE started_at < ?`, formatTime(t))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
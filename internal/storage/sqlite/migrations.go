@@ -0,0 +1,396 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// migrations are applied in order and recorded in schema_migrations. Never edit
+// a migration that has shipped; append a new one instead.
+var migrations = []string{
+	// 1: initial schema
+	`CREATE TABLE bookings (
+		id                TEXT PRIMARY KEY,
+		booking_id        TEXT NOT NULL UNIQUE,
+		name              TEXT NOT NULL DEFAULT '',
+		email             TEXT NOT NULL DEFAULT '',
+		phone             TEXT NOT NULL DEFAULT '',
+		additional_phone  TEXT NOT NULL DEFAULT '',
+		package_type      TEXT NOT NULL DEFAULT '',
+		event_date        TEXT NOT NULL,
+		venue             TEXT NOT NULL DEFAULT '',
+		city              TEXT NOT NULL DEFAULT '',
+		customization     TEXT NOT NULL DEFAULT '',
+		band_time         TEXT NOT NULL DEFAULT '',
+		custom_time_slot  TEXT NOT NULL DEFAULT '',
+		number_of_people  INTEGER NOT NULL DEFAULT 0,
+		number_of_lights  INTEGER NOT NULL DEFAULT 0,
+		number_of_dhols   INTEGER NOT NULL DEFAULT 0,
+		ghoda_baggi       INTEGER NOT NULL DEFAULT 0,
+		ghodi_for_baraat  INTEGER NOT NULL DEFAULT 0,
+		fireworks         INTEGER NOT NULL DEFAULT 0,
+		fireworks_amount  INTEGER NOT NULL DEFAULT 0,
+		flower_canon      INTEGER NOT NULL DEFAULT 0,
+		doli_for_vidai    INTEGER NOT NULL DEFAULT 0,
+		amount            INTEGER NOT NULL DEFAULT 0,
+		advance_payment   INTEGER NOT NULL DEFAULT 0,
+		phone_verified    INTEGER NOT NULL DEFAULT 0,
+		created_at        TEXT NOT NULL
+	);
+	CREATE INDEX idx_bookings_phone ON bookings (phone);
+	CREATE INDEX idx_bookings_event_date ON bookings (event_date);
+
+	CREATE TABLE employees (
+		id                           TEXT PRIMARY KEY,
+		name                         TEXT NOT NULL DEFAULT '',
+		mobile_number                TEXT NOT NULL DEFAULT '',
+		email                        TEXT NOT NULL DEFAULT '',
+		address                      TEXT NOT NULL DEFAULT '',
+		is_employee                  INTEGER NOT NULL DEFAULT 1,
+		total_amount_to_be_paid      REAL NOT NULL DEFAULT 0,
+		total_amount_paid_in_advance REAL NOT NULL DEFAULT 0,
+		username                     TEXT NOT NULL UNIQUE,
+		password                     TEXT NOT NULL,
+		created_at                   TEXT NOT NULL,
+		updated_at                   TEXT NOT NULL
+	);
+
+	CREATE TABLE payments (
+		id          TEXT PRIMARY KEY,
+		amount_paid REAL NOT NULL,
+		date        TEXT NOT NULL,
+		employee_id TEXT NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+		created_at  TEXT NOT NULL
+	);
+	CREATE INDEX idx_payments_employee_id ON payments (employee_id);
+	CREATE INDEX idx_payments_date ON payments (date);
+
+	CREATE TABLE admin_users (
+		id            TEXT PRIMARY KEY,
+		name          TEXT NOT NULL DEFAULT '',
+		mobile_number TEXT NOT NULL DEFAULT '',
+		email         TEXT NOT NULL DEFAULT '',
+		username      TEXT NOT NULL UNIQUE,
+		password      TEXT NOT NULL,
+		is_admin_user INTEGER NOT NULL DEFAULT 1,
+		role          TEXT NOT NULL DEFAULT '',
+		created_at    TEXT NOT NULL,
+		updated_at    TEXT NOT NULL
+	);
+
+	CREATE TABLE otps (
+		id              TEXT PRIMARY KEY,
+		phone           TEXT NOT NULL UNIQUE,
+		code_hash       TEXT NOT NULL DEFAULT '',
+		code_expires_at TEXT NOT NULL,
+		attempts        INTEGER NOT NULL DEFAULT 0,
+		last_sent_at    TEXT NOT NULL,
+		verified_at     TEXT,
+		expires_at      TEXT NOT NULL,
+		created_at      TEXT NOT NULL
+	);
+	CREATE INDEX idx_otps_expires_at ON otps (expires_at);`,
+
+	// 2: pricing
+	`CREATE TABLE rate_cards (
+		id         TEXT PRIMARY KEY,
+		data       TEXT NOT NULL,
+		created_by TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	);
+	CREATE INDEX idx_rate_cards_created_at ON rate_cards (created_at);
+
+	ALTER TABLE bookings ADD COLUMN quote TEXT;`,
+
+	// 3: package catalog
+	`CREATE TABLE packages (
+		id          TEXT PRIMARY KEY,
+		code        TEXT NOT NULL UNIQUE,
+		name        TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		musicians   INTEGER NOT NULL DEFAULT 0,
+		lights      INTEGER NOT NULL DEFAULT 0,
+		dhols       INTEGER NOT NULL DEFAULT 0,
+		hours       INTEGER NOT NULL DEFAULT 0,
+		price       INTEGER NOT NULL DEFAULT 0,
+		active_from TEXT,
+		active_to   TEXT,
+		created_at  TEXT NOT NULL,
+		updated_at  TEXT NOT NULL
+	);`,
+
+	// 4: availability
+	`ALTER TABLE bookings ADD COLUMN time_slot TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_bookings_event_date_time_slot ON bookings (event_date, time_slot);
+
+	CREATE TABLE settings (
+		key        TEXT PRIMARY KEY,
+		value      TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	);`,
+
+	// 5: booking status lifecycle; existing bookings are confirmed
+	`ALTER TABLE bookings ADD COLUMN status TEXT NOT NULL DEFAULT 'confirmed';
+	ALTER TABLE bookings ADD COLUMN status_history TEXT;
+	CREATE INDEX idx_bookings_status ON bookings (status);`,
+
+	// 6: booking edits and their revision log
+	`ALTER TABLE bookings ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
+
+	CREATE TABLE booking_revisions (
+		id         TEXT PRIMARY KEY,
+		booking_id TEXT NOT NULL,
+		revision   INTEGER NOT NULL,
+		changes    TEXT NOT NULL,
+		changed_by TEXT NOT NULL DEFAULT '',
+		changed_at TEXT NOT NULL,
+		UNIQUE (booking_id, revision)
+	);`,
+
+	// 7: customer payment ledger
+	`CREATE TABLE customer_payments (
+		id          TEXT PRIMARY KEY,
+		booking_id  TEXT NOT NULL,
+		amount      INTEGER NOT NULL,
+		method      TEXT NOT NULL,
+		reference   TEXT NOT NULL DEFAULT '',
+		received_by TEXT NOT NULL DEFAULT '',
+		paid_at     TEXT NOT NULL,
+		created_at  TEXT NOT NULL,
+		voided_at   TEXT,
+		voided_by   TEXT NOT NULL DEFAULT '',
+		void_reason TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_customer_payments_booking_id ON customer_payments (booking_id);`,
+
+	// 8: crew assignments, removed with their booking or employee
+	`CREATE TABLE crew_assignments (
+		id          TEXT PRIMARY KEY,
+		booking_id  TEXT NOT NULL REFERENCES bookings (booking_id) ON DELETE CASCADE,
+		employee_id TEXT NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+		username    TEXT NOT NULL,
+		role        TEXT NOT NULL,
+		event_date  TEXT NOT NULL,
+		time_slot   TEXT NOT NULL DEFAULT '',
+		assigned_by TEXT NOT NULL DEFAULT '',
+		assigned_at TEXT NOT NULL,
+		UNIQUE (booking_id, employee_id)
+	);
+	CREATE INDEX idx_crew_assignments_employee_event_date ON crew_assignments (employee_id, event_date);`,
+
+	// 9: crew wages and the earnings accrued for completed events, kept when the booking is deleted
+	`ALTER TABLE crew_assignments ADD COLUMN wage REAL NOT NULL DEFAULT 0;
+	CREATE TABLE earnings (
+		id          TEXT PRIMARY KEY,
+		employee_id TEXT NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+		booking_id  TEXT NOT NULL,
+		role        TEXT NOT NULL,
+		event_date  TEXT NOT NULL,
+		amount      REAL NOT NULL,
+		accrued_at  TEXT NOT NULL,
+		UNIQUE (booking_id, employee_id)
+	);
+	CREATE INDEX idx_earnings_employee_event_date ON earnings (employee_id, event_date);`,
+
+	// 10: equipment inventory
+	`CREATE TABLE inventory (
+		id               TEXT PRIMARY KEY,
+		code             TEXT NOT NULL UNIQUE,
+		name             TEXT NOT NULL,
+		quantity_owned   INTEGER NOT NULL DEFAULT 0,
+		in_maintenance   INTEGER NOT NULL DEFAULT 0,
+		maintenance_note TEXT NOT NULL DEFAULT '',
+		updated_by       TEXT NOT NULL DEFAULT '',
+		updated_at       TEXT NOT NULL
+	);`,
+
+	// 11: indexes for filtering and sorting booking listings
+	`CREATE INDEX idx_bookings_created_at ON bookings (created_at);
+	CREATE INDEX idx_bookings_city ON bookings (city COLLATE NOCASE, event_date);
+	CREATE INDEX idx_bookings_package_type ON bookings (package_type, event_date);`,
+
+	// 12: full-text search over bookings and employees. The trigram tokenizer
+	// matches fragments of names, emails and phone numbers; triggers keep the
+	// indexes in step with their tables.
+	`CREATE VIRTUAL TABLE bookings_fts USING fts5 (
+		id UNINDEXED, booking_id, name, phone, additional_phone, email, venue, city,
+		tokenize = 'trigram'
+	);
+	INSERT INTO bookings_fts (id, booking_id, name, phone, additional_phone, email, venue, city)
+		SELECT id, booking_id, name, phone, additional_phone, email, venue, city FROM bookings;
+	CREATE TRIGGER bookings_fts_insert AFTER INSERT ON bookings BEGIN
+		INSERT INTO bookings_fts (id, booking_id, name, phone, additional_phone, email, venue, city)
+			VALUES (new.id, new.booking_id, new.name, new.phone, new.additional_phone, new.email, new.venue, new.city);
+	END;
+	CREATE TRIGGER bookings_fts_update AFTER UPDATE OF booking_id, name, phone, additional_phone, email, venue, city ON bookings BEGIN
+		UPDATE bookings_fts SET booking_id = new.booking_id, name = new.name, phone = new.phone,
+			additional_phone = new.additional_phone, email = new.email, venue = new.venue, city = new.city
+			WHERE id = old.id;
+	END;
+	CREATE TRIGGER bookings_fts_delete AFTER DELETE ON bookings BEGIN
+		DELETE FROM bookings_fts WHERE id = old.id;
+	END;
+
+	CREATE VIRTUAL TABLE employees_fts USING fts5 (
+		id UNINDEXED, username, name, mobile_number, email, address,
+		tokenize = 'trigram'
+	);
+	INSERT INTO employees_fts (id, username, name, mobile_number, email, address)
+		SELECT id, username, name, mobile_number, email, address FROM employees;
+	CREATE TRIGGER employees_fts_insert AFTER INSERT ON employees BEGIN
+		INSERT INTO employees_fts (id, username, name, mobile_number, email, address)
+			VALUES (new.id, new.username, new.name, new.mobile_number, new.email, new.address);
+	END;
+	CREATE TRIGGER employees_fts_update AFTER UPDATE OF username, name, mobile_number, email, address ON employees BEGIN
+		UPDATE employees_fts SET username = new.username, name = new.name, mobile_number = new.mobile_number,
+			email = new.email, address = new.address
+			WHERE id = old.id;
+	END;
+	CREATE TRIGGER employees_fts_delete AFTER DELETE ON employees BEGIN
+		DELETE FROM employees_fts WHERE id = old.id;
+	END;`,
+
+	// 13: customer payments are exported by payment date
+	`CREATE INDEX idx_customer_payments_paid_at ON customer_payments (paid_at);`,
+
+	// 14: GST invoices and credit notes, stored as JSON snapshots, with the last
+	// number used in each series. Triggers keep issued documents immutable.
+	`CREATE TABLE tax_invoice_series (
+		series   TEXT PRIMARY KEY,
+		sequence INTEGER NOT NULL
+	);
+	CREATE TABLE tax_invoices (
+		id             TEXT PRIMARY KEY,
+		number         TEXT NOT NULL UNIQUE,
+		kind           TEXT NOT NULL,
+		financial_year TEXT NOT NULL,
+		sequence       INTEGER NOT NULL,
+		booking_id     TEXT NOT NULL,
+		issued_at      TEXT NOT NULL,
+		data           TEXT NOT NULL,
+		UNIQUE (booking_id, kind),
+		UNIQUE (financial_year, kind, sequence)
+	);
+	CREATE TRIGGER tax_invoices_no_update BEFORE UPDATE ON tax_invoices BEGIN
+		SELECT RAISE(ABORT, 'tax invoices cannot be changed');
+	END;
+	CREATE TRIGGER tax_invoices_no_delete BEFORE DELETE ON tax_invoices BEGIN
+		SELECT RAISE(ABORT, 'tax invoices cannot be deleted');
+	END;`,
+
+	// 15: notification outbox
+	`CREATE TABLE notifications (
+		id              TEXT PRIMARY KEY,
+		event           TEXT NOT NULL,
+		channel         TEXT NOT NULL,
+		recipient       TEXT NOT NULL,
+		subject         TEXT NOT NULL DEFAULT '',
+		body            TEXT NOT NULL,
+		booking_id      TEXT NOT NULL DEFAULT '',
+		status          TEXT NOT NULL,
+		attempts        INTEGER NOT NULL DEFAULT 0,
+		last_error      TEXT NOT NULL DEFAULT '',
+		next_attempt_at TEXT NOT NULL,
+		sent_at         TEXT,
+		created_at      TEXT NOT NULL
+	);
+	CREATE INDEX idx_notifications_due ON notifications (status, next_attempt_at);
+	CREATE INDEX idx_notifications_booking ON notifications (booking_id);`,
+
+	// 16: keys that stop a notification such as a reminder being queued twice;
+	// NULL keys never conflict
+	`ALTER TABLE notifications ADD COLUMN dedupe_key TEXT;
+	CREATE UNIQUE INDEX idx_notifications_key ON notifications (dedupe_key);`,
+
+	// 17: archived past bookings, and the background job schedules, locks and run history
+	`ALTER TABLE bookings ADD COLUMN archived_at TEXT;
+	CREATE INDEX idx_bookings_archived_at ON bookings (archived_at);
+	CREATE TABLE jobs (
+		name         TEXT PRIMARY KEY,
+		schedule     TEXT NOT NULL,
+		enabled      INTEGER NOT NULL,
+		next_run_at  TEXT NOT NULL,
+		locked_by    TEXT NOT NULL DEFAULT '',
+		locked_until TEXT,
+		last_run     TEXT,
+		updated_by   TEXT NOT NULL DEFAULT '',
+		updated_at   TEXT NOT NULL
+	);
+	CREATE TABLE job_runs (
+		id           TEXT PRIMARY KEY,
+		job          TEXT NOT NULL,
+		trigger      TEXT NOT NULL,
+		triggered_by TEXT NOT NULL DEFAULT '',
+		instance     TEXT NOT NULL,
+		status       TEXT NOT NULL,
+		result       TEXT NOT NULL DEFAULT '',
+		error        TEXT NOT NULL DEFAULT '',
+		started_at   TEXT NOT NULL,
+		finished_at  TEXT
+	);
+	CREATE INDEX idx_job_runs_job ON job_runs (job, started_at);`,
+
+	// 18: trash for deleted bookings, employees and employee payments
+	`ALTER TABLE bookings ADD COLUMN deleted_at TEXT;
+	ALTER TABLE bookings ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_bookings_deleted_at ON bookings (deleted_at);
+	ALTER TABLE employees ADD COLUMN deleted_at TEXT;
+	ALTER TABLE employees ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_employees_deleted_at ON employees (deleted_at);
+	ALTER TABLE payments ADD COLUMN deleted_at TEXT;
+	ALTER TABLE payments ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_payments_deleted_at ON payments (deleted_at);`,
+
+	// 19: crew assignments follow their booking into the trash
+	`ALTER TABLE crew_assignments ADD COLUMN deleted_at TEXT;`,
+
+	// 20: per-phone OTP limits
+	`CREATE TABLE otp_events (
+		id         TEXT PRIMARY KEY,
+		phone      TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		at         TEXT NOT NULL,
+		expires_at TEXT NOT NULL
+	);
+	CREATE INDEX idx_otp_events_phone_kind_at ON otp_events (phone, kind, at);
+	CREATE INDEX idx_otp_events_expires_at ON otp_events (expires_at);`,
+}
+
+// migrate applies any migrations that have not yet run
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("error reading schema version: %w", err)
+	}
+
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error applying migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, formatTime(time.Now())); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error recording migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		log.Printf("Applied SQLite migration %d", version)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// notificationColumns lists the notifications columns in the order scanNotification reads them
+const notificationColumns = `id, event, channel, recipient, subject, body, booking_id, dedupe_key, status,
+	attempts, last_error, next_attempt_at, sent_at, created_at`
+
+// notificationRepository implements storage.NotificationRepository on SQLite
+type notificationRepository struct {
+	db *sql.DB
+}
+
+// scanNotification reads a row selected with notificationColumns
+func scanNotification(row scanner) (*models.Notification, error) {
+	var n models.Notification
+	var id, nextAttemptAt, createdAt string
+	var key, sentAt sql.NullString
+	err := row.Scan(&id, &n.Event, &n.Channel, &n.To, &n.Subject, &n.Body, &n.BookingID, &key, &n.Status,
+		&n.Attempts, &n.LastError, &nextAttemptAt, &sentAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if n.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	n.Key = key.String
+	if n.NextAttemptAt, err = parseTime(nextAttemptAt); err != nil {
+		return nil, err
+	}
+	if n.SentAt, err = parseNullTime(sentAt); err != nil {
+		return nil, err
+	}
+	if n.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (r *notificationRepository) Enqueue(ctx context.Context, notifications []models.Notification) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ids := make([]primitive.ObjectID, len(notifications))
+	for i, n := range notifications {
+		key := sql.NullString{String: n.Key, Valid: n.Key != ""}
+		id := primitive.NewObjectID()
+		result, err := tx.ExecContext(ctx, `INSERT INTO notifications (`+notificationColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (dedupe_key) DO NOTHING`,
+			id.Hex(), n.Event, n.Channel, n.To, n.Subject, n.Body, n.BookingID, key, n.Status,
+			n.Attempts, n.LastError, formatTime(n.NextAttemptAt), formatNullTime(n.SentAt), formatTime(n.CreatedAt))
+		if err != nil {
+			return err
+		}
+		if inserted, _ := result.RowsAffected(); inserted > 0 {
+			ids[i] = id
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for i := range notifications {
+		notifications[i].ID = ids[i]
+	}
+	return nil
+}
+
+func (r *notificationRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Notification, error) {
+	claimed, err := readPage(ctx, r.db, `UPDATE notifications SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE id IN (SELECT id FROM notifications WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at LIMIT ?)
+		RETURNING `+notificationColumns,
+		[]interface{}{formatTime(leaseUntil), models.NotificationPending, formatTime(now), limit}, scanNotification)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING yields rows in no particular order
+	sort.SliceStable(claimed, func(i, j int) bool {
+		return claimed[i].CreatedAt.Before(claimed[j].CreatedAt)
+	})
+	return claimed, nil
+}
+
+func (r *notificationRepository) MarkSent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	return r.update(ctx, `UPDATE notifications SET status = ?, sent_at = ?, last_error = '' WHERE id = ?`,
+		models.NotificationSent, formatTime(at), id.Hex())
+}
+
+func (r *notificationRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, lastError string, retryAt time.Time) error {
+	if retryAt.IsZero() {
+		return r.update(ctx, `UPDATE notifications SET status = ?, last_error = ? WHERE id = ?`,
+			models.NotificationFailed, lastError, id.Hex())
+	}
+	return r.update(ctx, `UPDATE notifications SET next_attempt_at = ?, last_error = ? WHERE id = ?`,
+		formatTime(retryAt), lastError, id.Hex())
+}
+
+func (r *notificationRepository) Retry(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	err := r.update(ctx, `UPDATE notifications SET status = ?, attempts = 0, next_attempt_at = ? WHERE id = ? AND status = ?`,
+		models.NotificationPending, formatTime(at), id.Hex(), models.NotificationFailed)
+	if err != storage.ErrNotFound {
+		return err
+	}
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM notifications WHERE id = ?)`, id.Hex()).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return storage.ErrConflict
+	}
+	return storage.ErrNotFound
+}
+
+// update runs a statement changing one notification, returning ErrNotFound if none matched
+func (r *notificationRepository) update(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) List(ctx context.Context, filter storage.NotificationFilter, limit int) ([]models.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE 1 = 1`
+	var args []interface{}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	if filter.BookingID != "" {
+		query += ` AND booking_id = ?`
+		args = append(args, filter.BookingID)
+	}
+	return readPage(ctx, r.db, query+` ORDER BY created_at DESC, id DESC LIMIT ?`, append(args, limit), scanNotification)
+}
@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// otpColumns lists the otps columns in the order scanOTP reads them
+const otpColumns = `id, phone, code_hash, code_expires_at, attempts, last_sent_at, verified_at, expires_at, created_at`
+
+// otpRepository implements storage.OTPRepository on SQLite. Rows past expires_at
+// are treated as absent and removed when a new code is issued.
+type otpRepository struct {
+	db *sql.DB
+}
+
+// scanOTP reads a row selected with otpColumns
+func scanOTP(row scanner) (*models.OTPVerification, error) {
+	var o models.OTPVerification
+	var id, codeExpiresAt, lastSentAt, expiresAt, createdAt string
+	var verifiedAt sql.NullString
+	err := row.Scan(&id, &o.Phone, &o.CodeHash, &codeExpiresAt, &o.Attempts, &lastSentAt, &verifiedAt, &expiresAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if o.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if o.CodeExpiresAt, err = parseTime(codeExpiresAt); err != nil {
+		return nil, err
+	}
+	if o.LastSentAt, err = parseTime(lastSentAt); err != nil {
+		return nil, err
+	}
+	if o.ExpiresAt, err = parseTime(expiresAt); err != nil {
+		return nil, err
+	}
+	if o.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if o.VerifiedAt, err = parseNullTime(verifiedAt); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *otpRepository) FindByPhone(ctx context.Context, phone string) (*models.OTPVerification, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+otpColumns+` FROM otps WHERE phone = ? AND expires_at > ?`,
+		phone, formatTime(time.Now()))
+	record, err := scanOTP(row)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return record, err
+}
+
+func (r *otpRepository) Issue(ctx context.Context, phone, codeHash string, now, codeExpiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Drop expired records so they do not linger without a TTL index
+	if _, err := tx.ExecContext(ctx, `DELETE FROM otps WHERE expires_at <= ?`, formatTime(now)); err != nil {
+		return err
+	}
+
+	// Replace any previous code; a new code also clears an earlier verification
+	_, err = tx.ExecContext(ctx, `INSERT INTO otps (`+otpColumns+`) VALUES (?, ?, ?, ?, 0, ?, NULL, ?, ?)
+		ON CONFLICT (phone) DO UPDATE SET
+			code_hash = excluded.code_hash,
+			code_expires_at = excluded.code_expires_at,
+			attempts = 0,
+			last_sent_at = excluded.last_sent_at,
+			verified_at = NULL,
+			expires_at = excluded.expires_at`,
+		primitive.NewObjectID().Hex(), phone, codeHash, formatTime(codeExpiresAt), formatTime(now),
+		formatTime(codeExpiresAt), formatTime(now))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *otpRepository) RecordAttempt(ctx context.Context, phone string, now time.Time, maxAttempts int) (*models.OTPVerification, error) {
+	row := r.db.QueryRowContext(ctx, `UPDATE otps SET attempts = attempts + 1
+		WHERE phone = ? AND code_hash <> '' AND code_expires_at > ? AND attempts < ?
+		RETURNING `+otpColumns,
+		phone, formatTime(now), maxAttempts)
+	record, err := scanOTP(row)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return record, err
+}
+
+func (r *otpRepository) MarkVerified(ctx context.Context, phone string, verifiedAt, expiresAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE otps SET code_hash = '', verified_at = ?, expires_at = ? WHERE phone = ?`,
+		formatTime(verifiedAt), formatTime(expiresAt), phone)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *otpRepository) LogEvent(ctx context.Context, event *models.OTPEvent, since time.Time, limit int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if limit > 0 {
+		var count int
+		err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM otp_events WHERE phone = ? AND kind = ? AND at > ?`,
+			event.Phone, event.Kind, formatTime(since)).Scan(&count)
+		if err != nil {
+			return false, err
+		}
+		if count >= limit {
+			return false, nil
+		}
+	}
+
+	id := primitive.NewObjectID()
+	_, err = tx.ExecContext(ctx, `INSERT INTO otp_events (id, phone, kind, at, expires_at) VALUES (?, ?, ?, ?, ?)`,
+		id.Hex(), event.Phone, event.Kind, formatTime(event.At), formatTime(event.ExpiresAt))
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	event.ID = id
+	return true, nil
+}
+
+func (r *otpRepository) EventsSince(ctx context.Context, phone, kind string, since time.Time) ([]time.Time, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT at FROM otp_events WHERE phone = ? AND kind = ? AND at > ? ORDER BY at`,
+		phone, kind, formatTime(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	times := []time.Time{}
+	for rows.Next() {
+		var at string
+		if err := rows.Scan(&at); err != nil {
+			return nil, err
+		}
+		t, err := parseTime(at)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, rows.Err()
+}
+
+func (r *otpRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM otp_events WHERE expires_at <= ?`, formatTime(now)); err != nil {
+		return 0, err
+	}
+	result, err := r.db.ExecContext(ctx, `DELETE FROM otps WHERE expires_at <= ?`, formatTime(now))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// packageColumns lists the packages columns in the order scanPackage reads them
+const packageColumns = `id, code, name, description, musicians, lights, dhols, hours, price, active_from, active_to, created_at, updated_at`
+
+// packageRepository implements storage.PackageRepository on SQLite
+type packageRepository struct {
+	db *sql.DB
+}
+
+// scanPackage reads a row selected with packageColumns
+func scanPackage(row scanner) (*models.Package, error) {
+	var p models.Package
+	var id, createdAt, updatedAt string
+	var activeFrom, activeTo sql.NullString
+	err := row.Scan(&id, &p.Code, &p.Name, &p.Description, &p.Musicians, &p.Lights, &p.Dhols, &p.Hours, &p.Price,
+		&activeFrom, &activeTo, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if p.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if p.ActiveFrom, err = parseNullTime(activeFrom); err != nil {
+		return nil, err
+	}
+	if p.ActiveTo, err = parseNullTime(activeTo); err != nil {
+		return nil, err
+	}
+	if p.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if p.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *packageRepository) Create(ctx context.Context, p *models.Package) error {
+	id := primitive.NewObjectID()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO packages (`+packageColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(), p.Code, p.Name, p.Description, p.Musicians, p.Lights, p.Dhols, p.Hours, p.Price,
+		formatNullTime(p.ActiveFrom), formatNullTime(p.ActiveTo), formatTime(p.CreatedAt), formatTime(p.UpdatedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrDuplicate
+		}
+		return err
+	}
+	p.ID = id
+	return nil
+}
+
+func (r *packageRepository) FindByCode(ctx context.Context, code string) (*models.Package, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+packageColumns+` FROM packages WHERE code = ?`, code)
+	pkg, err := scanPackage(row)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return pkg, err
+}
+
+func (r *packageRepository) List(ctx context.Context) ([]models.Package, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+packageColumns+` FROM packages ORDER BY price, code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	packages := []models.Package{}
+	for rows.Next() {
+		pkg, err := scanPackage(rows)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, *pkg)
+	}
+	return packages, rows.Err()
+}
+
+func (r *packageRepository) Update(ctx context.Context, p *models.Package) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE packages
+		SET name = ?, description = ?, musicians = ?, lights = ?, dhols = ?, hours = ?, price = ?,
+			active_from = ?, active_to = ?, updated_at = ?
+		WHERE code = ?`,
+		p.Name, p.Description, p.Musicians, p.Lights, p.Dhols, p.Hours, p.Price,
+		formatNullTime(p.ActiveFrom), formatNullTime(p.ActiveTo), formatTime(p.UpdatedAt), p.Code)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *packageRepository) Delete(ctx context.Context, code string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM packages WHERE code = ?`, code)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
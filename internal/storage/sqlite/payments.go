@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// paymentRepository implements storage.PaymentRepository on SQLite
+type paymentRepository struct {
+	db *sql.DB
+}
+
+// paymentColumns lists the payments columns in the order scanPayment reads them
+const paymentColumns = `id, amount_paid, date, employee_id, created_at, deleted_at, deleted_by`
+
+// scanPayment reads a row selected with paymentColumns
+func scanPayment(row scanner) (*models.Payment, error) {
+	var p models.Payment
+	var id, date, empID, createdAt string
+	var deletedAt sql.NullString
+	if err := row.Scan(&id, &p.AmountPaid, &date, &empID, &createdAt, &deletedAt, &p.DeletedBy); err != nil {
+		return nil, err
+	}
+	var err error
+	if p.ID, err = parseObjectID(id); err != nil {
+		return nil, err
+	}
+	if p.EmployeeID, err = parseObjectID(empID); err != nil {
+		return nil, err
+	}
+	if p.Date, err = parseTime(date); err != nil {
+		return nil, err
+	}
+	if p.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if p.DeletedAt, err = parseNullTime(deletedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// paymentWhere returns the WHERE clause and arguments selecting the payments that match filter
+func paymentWhere(filter storage.PaymentFilter) (string, []interface{}) {
+	where := ` WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	if !filter.EmployeeID.IsZero() {
+		where += ` AND employee_id = ?`
+		args = append(args, filter.EmployeeID.Hex())
+	}
+	if !filter.From.IsZero() {
+		where += ` AND date >= ?`
+		args = append(args, formatTime(filter.From))
+	}
+	if !filter.Before.IsZero() {
+		where += ` AND date < ?`
+		args = append(args, formatTime(filter.Before))
+	}
+	return where, args
+}
+
+func (r *paymentRepository) Create(ctx context.Context, p *models.Payment) error {
+	id := primitive.NewObjectID()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO payments (`+paymentColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(), p.AmountPaid, formatTime(p.Date), p.EmployeeID.Hex(), formatTime(p.CreatedAt),
+		formatNullTime(p.DeletedAt), p.DeletedBy)
+	if err != nil {
+		return err
+	}
+	p.ID = id
+	return nil
+}
+
+func (r *paymentRepository) ListByEmployee(ctx context.Context, employeeID primitive.ObjectID) ([]models.Payment, error) {
+	return readPage(ctx, r.db, `SELECT `+paymentColumns+` FROM payments WHERE employee_id = ? AND deleted_at IS NULL ORDER BY date DESC`,
+		[]interface{}{employeeID.Hex()}, scanPayment)
+}
+
+func (r *paymentRepository) Delete(ctx context.Context, employeeID, paymentID primitive.ObjectID, by string, at time.Time) error {
+	return r.update(ctx, `UPDATE payments SET deleted_at = ?, deleted_by = ?
+		WHERE id = ? AND employee_id = ? AND deleted_at IS NULL`,
+		formatTime(at), by, paymentID.Hex(), employeeID.Hex())
+}
+
+func (r *paymentRepository) Restore(ctx context.Context, employeeID, paymentID primitive.ObjectID) error {
+	return r.update(ctx, `UPDATE payments SET deleted_at = NULL, deleted_by = ''
+		WHERE id = ? AND employee_id = ? AND deleted_at IS NOT NULL`,
+		paymentID.Hex(), employeeID.Hex())
+}
+
+// update runs a statement changing one payment, returning ErrNotFound if none matched
+func (r *paymentRepository) update(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *paymentRepository) ListDeleted(ctx context.Context, limit int) ([]models.Payment, error) {
+	return readPage(ctx, r.db, `SELECT `+paymentColumns+` FROM payments WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id LIMIT ?`, []interface{}{sqlLimit(limit)}, scanPayment)
+}
+
+func (r *paymentRepository) PurgeDeleted(ctx context.Context, t time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM payments WHERE deleted_at < ?`, formatTime(t))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *paymentRepository) TotalsByEmployee(ctx context.Context, filter storage.PaymentFilter) (map[primitive.ObjectID]models.PaymentTotal, error) {
+	where, args := paymentWhere(filter)
+	rows, err := r.db.QueryContext(ctx, `SELECT employee_id, SUM(amount_paid), COUNT(*) FROM payments`+where+` GROUP BY employee_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := map[primitive.ObjectID]models.PaymentTotal{}
+	for rows.Next() {
+		var empID string
+		var total models.PaymentTotal
+		if err := rows.Scan(&empID, &total.Amount, &total.Count); err != nil {
+			return nil, err
+		}
+		id, err := parseObjectID(empID)
+		if err != nil {
+			return nil, err
+		}
+		totals[id] = total
+	}
+	return totals, rows.Err()
+}
+
+func (r *paymentRepository) Batches(ctx context.Context, filter storage.PaymentFilter, size int, fn func([]models.Payment) error) error {
+	where, args := paymentWhere(filter)
+	return batches(ctx, r.db, `SELECT `+paymentColumns+` FROM payments`+where+` ORDER BY date, created_at, id LIMIT ? OFFSET ?`,
+		args, 0, 0, size, scanPayment, fn)
+}
@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// rateCardRepository implements storage.RateCardRepository on SQLite. The rate
+// card document is stored as JSON since it is only ever read whole.
+type rateCardRepository struct {
+	db *sql.DB
+}
+
+func (r *rateCardRepository) Current(ctx context.Context) (*models.RateCard, error) {
+	var data string
+	err := r.db.QueryRowContext(ctx, `SELECT data FROM rate_cards ORDER BY created_at DESC LIMIT 1`).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var card models.RateCard
+	if err := json.Unmarshal([]byte(data), &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (r *rateCardRepository) Create(ctx context.Context, card *models.RateCard) error {
+	card.ID = primitive.NewObjectID()
+	data, err := json.Marshal(card)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO rate_cards (id, data, created_by, created_at) VALUES (?, ?, ?, ?)`,
+		card.ID.Hex(), string(data), card.CreatedBy, formatTime(card.CreatedAt))
+	return err
+}
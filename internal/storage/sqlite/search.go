@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ftsTable describes a full-text index created by migration 12
+type ftsTable struct {
+	name    string
+	columns []string  // Indexed columns, in table order after id
+	weights []float64 // bm25 weight of each column
+}
+
+var (
+	bookingsFTS = ftsTable{
+		name:    "bookings_fts",
+		columns: []string{"booking_id", "name", "phone", "additional_phone", "email", "venue", "city"},
+		weights: []float64{10, 5, 5, 5, 3, 2, 1},
+	}
+	employeesFTS = ftsTable{
+		name:    "employees_fts",
+		columns: []string{"username", "name", "mobile_number", "email", "address"},
+		weights: []float64{10, 5, 5, 3, 1},
+	}
+)
+
+// ftsMinTerm is the shortest term the trigram tokenizer can match
+const ftsMinTerm = 3
+
+// matches returns a subquery selecting fts_id and fts_rank for the rows whose
+// indexed columns contain every term, with lower ranks for better matches.
+// Terms too short for the trigram index are matched with LIKE and not ranked.
+func (t ftsTable) matches(terms []string) (string, []interface{}) {
+	var phrases, conditions []string
+	var args []interface{}
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) >= ftsMinTerm {
+			phrases = append(phrases, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+			continue
+		}
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term) + "%"
+		var columns []string
+		for _, column := range t.columns {
+			columns = append(columns, column+` LIKE ? ESCAPE '\'`)
+			args = append(args, pattern)
+		}
+		conditions = append(conditions, "("+strings.Join(columns, " OR ")+")")
+	}
+
+	rank := "0"
+	if len(phrases) > 0 {
+		weights := []string{"0"} // id
+		for _, w := range t.weights {
+			weights = append(weights, fmt.Sprint(w))
+		}
+		rank = "bm25(" + t.name + ", " + strings.Join(weights, ", ") + ")"
+		conditions = append([]string{t.name + " MATCH ?"}, conditions...)
+		args = append([]interface{}{strings.Join(phrases, " ")}, args...)
+	}
+	return `SELECT id AS fts_id, ` + rank + ` AS fts_rank FROM ` + t.name +
+		` WHERE ` + strings.Join(conditions, " AND "), args
+}
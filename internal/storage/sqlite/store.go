@@ -0,0 +1,135 @@
+// Package sqlite provides a single-file SQLite storage backend for deployments
+// that run without MongoDB.
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// timeLayout stores times as fixed-width UTC text so they sort correctly
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// Open opens (creating if needed) the SQLite database at path and applies pending migrations
+func Open(path string) (*sql.DB, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating database directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("error opening SQLite database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection keeps transactions serialized
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Printf("Connected to SQLite database: %s", path)
+	return db, nil
+}
+
+// NewStore returns repositories backed by the given SQLite database
+func NewStore(db *sql.DB) *storage.Store {
+	return &storage.Store{
+		Bookings:         &bookingRepository{db},
+		Employees:        &employeeRepository{db},
+		Payments:         &paymentRepository{db},
+		CustomerPayments: &customerPaymentRepository{db},
+		Assignments:      &assignmentRepository{db},
+		Earnings:         &earningRepository{db},
+		Admins:           &adminRepository{db},
+		OTPs:             &otpRepository{db},
+		RateCards:        &rateCardRepository{db},
+		Packages:         &packageRepository{db},
+		Settings:         &settingsRepository{db},
+		Inventory:        &inventoryRepository{db},
+		TaxInvoices:      &taxInvoiceRepository{db},
+		Notifications:    &notificationRepository{db},
+		Jobs:             &jobRepository{db},
+	}
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// formatTime converts a time to its stored text form
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// parseTime converts stored text back to a time
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(timeLayout, s)
+}
+
+// formatNullTime converts an optional time to its stored text form
+func formatNullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(*t), Valid: true}
+}
+
+// parseNullTime converts optional stored text back to a time
+func parseNullTime(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	t, err := parseTime(s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseObjectID converts a stored hex ID back to an ObjectID
+func parseObjectID(s string) (primitive.ObjectID, error) {
+	return primitive.ObjectIDFromHex(s)
+}
+
+// marshalJSON encodes a nested document for a TEXT column, storing NULL for nil values
+func marshalJSON(v interface{}) (sql.NullString, error) {
+	if rv := reflect.ValueOf(v); !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unmarshalJSON decodes a nested document from a TEXT column, leaving v untouched for NULL
+func unmarshalJSON(s sql.NullString, v interface{}) error {
+	if !s.Valid {
+		return nil
+	}
+	return json.Unmarshal([]byte(s.String), v)
+}
+
+// isUniqueViolation reports whether err is a UNIQUE constraint failure
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
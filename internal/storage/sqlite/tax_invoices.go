@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// taxInvoiceRepository implements storage.TaxInvoiceRepository on SQLite. Each
+// document is stored as JSON alongside the columns it is looked up by.
+type taxInvoiceRepository struct {
+	db *sql.DB
+}
+
+// scanTaxInvoice reads the data column of a tax_invoices row
+func scanTaxInvoice(row scanner) (*models.TaxInvoice, error) {
+	var data string
+	if err := row.Scan(&data); err != nil {
+		return nil, err
+	}
+	var doc models.TaxInvoice
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *taxInvoiceRepository) Issue(ctx context.Context, doc *models.TaxInvoice) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var sequence int
+	err = tx.QueryRowContext(ctx, `INSERT INTO tax_invoice_series (series, sequence) VALUES (?, 1)
+		ON CONFLICT (series) DO UPDATE SET sequence = sequence + 1
+		RETURNING sequence`, doc.Series()).Scan(&sequence)
+	if err != nil {
+		return err
+	}
+
+	issued := *doc
+	issued.ID = primitive.NewObjectID()
+	issued.SetSequence(sequence)
+	data, err := json.Marshal(&issued)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO tax_invoices
+		(id, number, kind, financial_year, sequence, booking_id, issued_at, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		issued.ID.Hex(), issued.Number, issued.Kind, issued.FinancialYear, issued.Sequence,
+		issued.BookingID, formatTime(issued.IssuedAt), string(data))
+	if isUniqueViolation(err) {
+		return storage.ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*doc = issued
+	return nil
+}
+
+func (r *taxInvoiceRepository) FindByNumber(ctx context.Context, number string) (*models.TaxInvoice, error) {
+	doc, err := scanTaxInvoice(r.db.QueryRowContext(ctx, `SELECT data FROM tax_invoices WHERE number = ?`, number))
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	return doc, err
+}
+
+func (r *taxInvoiceRepository) ListByBooking(ctx context.Context, bookingID string) ([]models.TaxInvoice, error) {
+	return readPage(ctx, r.db, `SELECT data FROM tax_invoices WHERE booking_id = ? ORDER BY issued_at`,
+		[]interface{}{bookingID}, scanTaxInvoice)
+}
+
+func (r *taxInvoiceRepository) List(ctx context.Context, financialYear, kind string) ([]models.TaxInvoice, error) {
+	query := `SELECT data FROM tax_invoices WHERE financial_year = ?`
+	args := []interface{}{financialYear}
+	if kind != "" {
+		query += ` AND kind = ?`
+		args = append(args, kind)
+	}
+	return readPage(ctx, r.db, query+` ORDER BY issued_at`, args, scanTaxInvoice)
+}
@@ -0,0 +1,493 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrNotFound is returned when the requested record does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate is returned when a record violates a uniqueness constraint
+	ErrDuplicate = errors.New("duplicate record")
+	// ErrCapacityExceeded is returned when a booking would exceed the capacity of its date or slot
+	ErrCapacityExceeded = errors.New("capacity exceeded")
+	// ErrConflict is returned when a record changed since it was read
+	ErrConflict = errors.New("record was modified concurrently")
+	// ErrInsufficientStock is returned when a booking requests more equipment than is left for its date
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrLastOwner is returned when a change would leave no admin user with the owner role
+	ErrLastOwner = errors.New("no owner would remain")
+	// ErrNotVerified is returned when a booking's phone has no OTP verification to use up
+	ErrNotVerified = errors.New("phone not verified")
+)
+
+// CapacityLimits caps the bookings accepted for a date and the equipment they may
+// reserve. Zero means unlimited.
+type CapacityLimits struct {
+	PerDay  int
+	PerSlot int
+	Stock   map[string]int // Units available per inventory item; absent items are unlimited
+}
+
+// StockExceeded reports whether a booking requesting equipment would take more
+// of any item than remains after the quantities already reserved on its day
+func (l CapacityLimits) StockExceeded(reserved, equipment map[string]int) bool {
+	for item, quantity := range equipment {
+		if available, ok := l.Stock[item]; ok && reserved[item]+quantity > available {
+			return true
+		}
+	}
+	return false
+}
+
+// Archive states a booking listing can select
+const (
+	ArchivedExclude = ""        // Bookings that are not archived
+	ArchivedOnly    = "only"    // Archived bookings
+	ArchivedInclude = "include" // Both
+)
+
+// BookingFilter narrows a booking listing. Empty fields match every booking,
+// except that archived bookings are left out unless Archived asks for them.
+type BookingFilter struct {
+	Status      string
+	From        time.Time // Event date, inclusive
+	Before      time.Time // Event date, exclusive
+	City        string    // Matched case-insensitively
+	PackageType string
+	Phone       string
+	Archived    string // One of the Archived constants
+}
+
+// Fields a booking listing can be sorted by
+const (
+	SortCreatedAt = "createdAt"
+	SortEventDate = "date"
+	SortAmount    = "amount"
+	SortName      = "name"
+)
+
+// BookingPage selects a sorted slice of a booking listing. Bookings that sort
+// equal are ordered by booking ID so pages do not overlap.
+type BookingPage struct {
+	Sort   string // One of the Sort constants; SortCreatedAt if empty
+	Desc   bool
+	Offset int
+	Limit  int // Zero returns every booking from Offset
+}
+
+// PaymentFilter narrows employee payments. Zero fields match every payment.
+type PaymentFilter struct {
+	EmployeeID primitive.ObjectID
+	From       time.Time // Inclusive
+	Before     time.Time // Exclusive
+}
+
+// CustomerPaymentFilter narrows customer payments by payment date. Zero fields match every payment.
+type CustomerPaymentFilter struct {
+	From   time.Time // Inclusive
+	Before time.Time // Exclusive
+}
+
+// EarningFilter narrows employee earnings by event date. Zero fields match every earning.
+type EarningFilter struct {
+	EmployeeID primitive.ObjectID
+	From       time.Time // Inclusive
+	Before     time.Time // Exclusive
+}
+
+// SearchTerms splits a search query into lower-case terms. Records match a query
+// when every term is found in at least one of their searchable fields.
+func SearchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// Store groups the repositories used by the handlers
+type Store struct {
+	Bookings         BookingRepository
+	Employees        EmployeeRepository
+	Payments         PaymentRepository
+	CustomerPayments CustomerPaymentRepository
+	Assignments      AssignmentRepository
+	Earnings         EarningRepository
+	Admins           AdminRepository
+	OTPs             OTPRepository
+	RateCards        RateCardRepository
+	Packages         PackageRepository
+	Settings         SettingsRepository
+	Inventory        InventoryRepository
+	TaxInvoices      TaxInvoiceRepository
+	Notifications    NotificationRepository
+	Jobs             JobRepository
+}
+
+// AttachBalances fills in the amount paid and balance due of bookings from the
+// payment ledger. Cancelled bookings have nothing left to pay.
+func (s *Store) AttachBalances(ctx context.Context, bookings ...*models.Booking) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(bookings))
+	for i, b := range bookings {
+		ids[i] = b.BookingID
+	}
+	totals, err := s.CustomerPayments.TotalsByBooking(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bookings {
+		b.AmountPaid = b.AdvancePayment + totals[b.BookingID]
+		b.BalanceDue = b.Amount - b.AmountPaid
+		if b.CurrentStatus() == models.BookingStatusCancelled {
+			b.BalanceDue = 0
+		}
+	}
+	return nil
+}
+
+// BookingRepository persists customer bookings. Deleted bookings stay in the
+// trash until they are purged; every method but Exists, FindDeleted, ListDeleted,
+// Restore and PurgeDeleted ignores them.
+type BookingRepository interface {
+	// Create inserts a booking and sets its ID
+	Create(ctx context.Context, booking *models.Booking) error
+	// CreateMany inserts bookings in one operation and sets their IDs. It returns
+	// ErrDuplicate, inserting none of them, if a booking ID is already taken.
+	CreateMany(ctx context.Context, bookings []models.Booking) error
+	// CreateWithinCapacity atomically checks the bookings already on the booking's event
+	// day and time slot against limits, then inserts it. Cancelled bookings do not count. It returns ErrCapacityExceeded
+	// if either limit is reached and ErrInsufficientStock if the booking's equipment exceeds the stock left.
+	// The OTP verification of the booking's phone made after verifiedSince is used
+	// up with the insert; ErrNotVerified is returned if there is none.
+	CreateWithinCapacity(ctx context.Context, booking *models.Booking, limits CapacityLimits, verifiedSince time.Time) error
+	// CountBySlot returns the number of bookings that are not cancelled per event day
+	// and time slot for event days from from through to, inclusive
+	CountBySlot(ctx context.Context, from, to time.Time) ([]models.SlotCount, error)
+	// ReservedEquipment returns the equipment requested by bookings that are not
+	// cancelled per event day and item for event days from from through to, inclusive
+	ReservedEquipment(ctx context.Context, from, to time.Time) ([]models.EquipmentReservation, error)
+	// Exists reports whether a booking with the given booking ID exists
+	Exists(ctx context.Context, bookingID string) (bool, error)
+	// FindByBookingID returns ErrNotFound if no booking has the ID
+	FindByBookingID(ctx context.Context, bookingID string) (*models.Booking, error)
+	// FindByPhone returns the bookings for a phone number, newest first
+	FindByPhone(ctx context.Context, phone string) ([]models.Booking, error)
+	// Search returns up to limit bookings whose booking ID, name, email, phone
+	// numbers, venue or city contain every term of query, best match first
+	Search(ctx context.Context, query string, limit int) ([]models.Booking, error)
+	// List returns the page of the bookings matching filter and the number of
+	// bookings matching filter in total
+	List(ctx context.Context, filter BookingFilter, page BookingPage) ([]models.Booking, int64, error)
+	// Batches calls fn with the bookings List would return, at most size at a time,
+	// so that long listings are never held in memory at once. It stops at the first
+	// error returned by fn.
+	Batches(ctx context.Context, filter BookingFilter, page BookingPage, size int, fn func([]models.Booking) error) error
+	// UpdateStatus moves a booking from status from to change.To and appends change to
+	// its history. It returns ErrNotFound if the booking does not exist and ErrConflict
+	// if its status is no longer from. Completing a booking accrues an earning at
+	// change.At for each crew assignment in the same operation.
+	UpdateStatus(ctx context.Context, bookingID, from string, change models.StatusChange) error
+	// Update saves the edited fields of a booking whose stored revision is
+	// booking.Revision-1 and whose status is booking.Status, and appends revision
+	// to its history. It returns ErrConflict if the stored booking has changed since
+	// it was read. If limits is not nil the booking's day, time slot and equipment are
+	// checked against them, excluding the booking itself, returning ErrCapacityExceeded
+	// or ErrInsufficientStock.
+	Update(ctx context.Context, booking *models.Booking, revision *models.BookingRevision, limits *CapacityLimits) error
+	// ListRevisions returns a booking's revisions, oldest first
+	ListRevisions(ctx context.Context, bookingID string) ([]models.BookingRevision, error)
+	// Delete moves a booking to the trash as of at together with its crew
+	// assignments, returning ErrNotFound if absent
+	Delete(ctx context.Context, bookingID, by string, at time.Time) error
+	// FindDeleted returns a booking in the trash, returning ErrNotFound if it is not there
+	FindDeleted(ctx context.Context, bookingID string) (*models.Booking, error)
+	// Restore takes a booking out of the trash, returning ErrNotFound if it is not
+	// there. If limits is not nil the booking's day, time slot and equipment are
+	// first checked against them, as in Update. Its crew is restored too, except
+	// employees in the trash or since assigned elsewhere at the same time.
+	Restore(ctx context.Context, bookingID string, limits *CapacityLimits) error
+	// ListDeleted returns up to limit bookings in the trash, or all of them if limit
+	// is 0, most recently deleted first
+	ListDeleted(ctx context.Context, limit int) ([]models.Booking, error)
+	// PurgeDeleted permanently removes the bookings deleted before t, returning how
+	// many were removed. Their crew assignments go with them; their payments,
+	// invoices and revisions are kept.
+	PurgeDeleted(ctx context.Context, t time.Time) (int64, error)
+	// ArchiveBefore archives, as of at, the bookings that are not confirmed and whose
+	// event date is before t, returning how many were archived. Confirmed bookings
+	// are kept until they are completed or cancelled.
+	ArchiveBefore(ctx context.Context, t, at time.Time) (int64, error)
+}
+
+// EmployeeRepository persists employees. Deleted employees stay in the trash
+// until they are purged; every method but Exists (their usernames stay taken),
+// ListDeleted, Restore and PurgeDeleted ignores them.
+type EmployeeRepository interface {
+	// Create inserts an employee and sets its ID, returning ErrDuplicate if the username is taken
+	Create(ctx context.Context, employee *models.Employee) error
+	// CreateMany inserts employees in one operation and sets their IDs. It returns
+	// ErrDuplicate, inserting none of them, if a username is already taken.
+	CreateMany(ctx context.Context, employees []models.Employee) error
+	// Exists reports whether an employee with the username exists
+	Exists(ctx context.Context, username string) (bool, error)
+	// FindByUsername returns ErrNotFound if no employee has the username
+	FindByUsername(ctx context.Context, username string) (*models.Employee, error)
+	// List returns all employees, newest first
+	List(ctx context.Context) ([]models.Employee, error)
+	// Search returns up to limit employees whose name, username, email, mobile
+	// number or address contain every term of query, best match first
+	Search(ctx context.Context, query string, limit int) ([]models.Employee, error)
+	// Delete moves an employee to the trash as of at, keeping their payments, crew
+	// assignments and earnings, returning ErrNotFound if absent
+	Delete(ctx context.Context, id primitive.ObjectID, by string, at time.Time) error
+	// Restore takes the employee with the username out of the trash, returning
+	// ErrNotFound if they are not there
+	Restore(ctx context.Context, username string) error
+	// ListDeleted returns up to limit employees in the trash, or all of them if limit
+	// is 0, most recently deleted first
+	ListDeleted(ctx context.Context, limit int) ([]models.Employee, error)
+	// PurgeDeleted permanently removes the employees deleted before t together with
+	// all of their payments, crew assignments and earnings, returning how many
+	// employees were removed
+	PurgeDeleted(ctx context.Context, t time.Time) (int64, error)
+}
+
+// PaymentRepository persists payments made to employees. Deleted payments stay
+// in the trash until they are purged; every method but ListDeleted, Restore and
+// PurgeDeleted ignores them.
+type PaymentRepository interface {
+	// Create inserts a payment and sets its ID
+	Create(ctx context.Context, payment *models.Payment) error
+	// ListByEmployee returns an employee's payments, most recent payment date first
+	ListByEmployee(ctx context.Context, employeeID primitive.ObjectID) ([]models.Payment, error)
+	// Delete moves an employee's payment to the trash as of at, returning ErrNotFound if absent
+	Delete(ctx context.Context, employeeID, paymentID primitive.ObjectID, by string, at time.Time) error
+	// Restore takes an employee's payment out of the trash, returning ErrNotFound if it is not there
+	Restore(ctx context.Context, employeeID, paymentID primitive.ObjectID) error
+	// ListDeleted returns up to limit payments in the trash, or all of them if limit
+	// is 0, most recently deleted first
+	ListDeleted(ctx context.Context, limit int) ([]models.Payment, error)
+	// PurgeDeleted permanently removes the payments deleted before t, returning how many were removed
+	PurgeDeleted(ctx context.Context, t time.Time) (int64, error)
+	// TotalsByEmployee sums the payments matching filter per employee. Employees
+	// without matching payments are absent from the result.
+	TotalsByEmployee(ctx context.Context, filter PaymentFilter) (map[primitive.ObjectID]models.PaymentTotal, error)
+	// Batches calls fn with the payments matching filter, oldest payment date first,
+	// at most size at a time. It stops at the first error returned by fn.
+	Batches(ctx context.Context, filter PaymentFilter, size int, fn func([]models.Payment) error) error
+}
+
+// CustomerPaymentRepository persists payments received from customers for bookings
+type CustomerPaymentRepository interface {
+	// Create inserts a payment and sets its ID
+	Create(ctx context.Context, payment *models.CustomerPayment) error
+	// ListByBooking returns a booking's payments, including voided ones, oldest payment date first
+	ListByBooking(ctx context.Context, bookingID string) ([]models.CustomerPayment, error)
+	// Void marks a payment of a booking as voided. It returns ErrNotFound if the payment
+	// does not exist and ErrConflict if it is already voided.
+	Void(ctx context.Context, bookingID string, paymentID primitive.ObjectID, by, reason string, at time.Time) error
+	// TotalsByBooking returns the sum of the payments that are not voided for each of
+	// the bookings. Bookings without payments are absent from the result.
+	TotalsByBooking(ctx context.Context, bookingIDs []string) (map[string]int, error)
+	// Batches calls fn with the payments matching filter, including voided ones, oldest
+	// payment date first, at most size at a time. It stops at the first error returned by fn.
+	Batches(ctx context.Context, filter CustomerPaymentFilter, size int, fn func([]models.CustomerPayment) error) error
+}
+
+// AssignmentRepository persists crew assignments of employees to bookings
+type AssignmentRepository interface {
+	// Create atomically checks the employee's other assignments and inserts the
+	// assignment, setting its ID. It returns ErrDuplicate if the employee is already
+	// assigned to the booking and ErrConflict if they have an overlapping assignment.
+	Create(ctx context.Context, assignment *models.CrewAssignment) error
+	// ListByBooking returns a booking's crew in the order they were assigned
+	ListByBooking(ctx context.Context, bookingID string) ([]models.CrewAssignment, error)
+	// ListByEmployee returns an employee's assignments for events on or after from,
+	// soonest first. A zero from returns every assignment.
+	ListByEmployee(ctx context.Context, employeeID primitive.ObjectID, from time.Time) ([]models.CrewAssignment, error)
+	// Delete removes an assignment from a booking, returning ErrNotFound if absent
+	Delete(ctx context.Context, bookingID string, id primitive.ObjectID) error
+	// DeleteByBooking removes every assignment of a booking
+	DeleteByBooking(ctx context.Context, bookingID string) error
+}
+
+// EarningRepository persists the wages employees accrued for completed events.
+// Earnings are created by BookingRepository.UpdateStatus.
+type EarningRepository interface {
+	// List returns the earnings matching filter, oldest event first
+	List(ctx context.Context, filter EarningFilter) ([]models.Earning, error)
+	// TotalsByEmployee sums the earnings matching filter per employee. Employees
+	// without matching earnings are absent from the result.
+	TotalsByEmployee(ctx context.Context, filter EarningFilter) (map[primitive.ObjectID]models.PaymentTotal, error)
+}
+
+// AdminRepository persists admin users
+type AdminRepository interface {
+	// Create inserts an admin user, returning ErrDuplicate if the username is taken
+	Create(ctx context.Context, admin *models.AdminUser) error
+	// CreateFirst atomically inserts an admin user only if none exists yet,
+	// returning ErrConflict otherwise
+	CreateFirst(ctx context.Context, admin *models.AdminUser) error
+	// Count returns the number of admin users
+	Count(ctx context.Context) (int64, error)
+	// Exists reports whether an admin user with the username exists
+	Exists(ctx context.Context, username string) (bool, error)
+	// FindByUsername returns ErrNotFound if no admin user has the username
+	FindByUsername(ctx context.Context, username string) (*models.AdminUser, error)
+	// List returns all admin users, newest first
+	List(ctx context.Context) ([]models.AdminUser, error)
+	// Update saves the name, contact details, password, role and updated time of an
+	// admin user, returning ErrLastOwner if it would demote the only owner
+	Update(ctx context.Context, admin *models.AdminUser) error
+	// Delete removes an admin user, returning ErrNotFound if absent and ErrLastOwner
+	// if they are the only owner
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// OTPRepository persists one-time passwords and phone verifications
+type OTPRepository interface {
+	// FindByPhone returns ErrNotFound if no OTP record exists for the phone
+	FindByPhone(ctx context.Context, phone string) (*models.OTPVerification, error)
+	// Issue stores a new code for the phone, resetting attempts and any earlier verification
+	Issue(ctx context.Context, phone, codeHash string, now, codeExpiresAt time.Time) error
+	// RecordAttempt counts a verification attempt against a live code and returns the
+	// updated record. It returns ErrNotFound if there is no unexpired code with attempts
+	// remaining below maxAttempts.
+	RecordAttempt(ctx context.Context, phone string, now time.Time, maxAttempts int) (*models.OTPVerification, error)
+	// MarkVerified records a successful verification, discards the code and keeps
+	// the record until expiresAt
+	MarkVerified(ctx context.Context, phone string, verifiedAt, expiresAt time.Time) error
+	// LogEvent records event and sets its ID unless limit events of its kind were
+	// already logged for its phone after since, reporting whether it was recorded.
+	// A limit of 0 always records.
+	LogEvent(ctx context.Context, event *models.OTPEvent, since time.Time, limit int) (bool, error)
+	// EventsSince returns when the events of the kind were logged for the phone
+	// after since, oldest first
+	EventsSince(ctx context.Context, phone, kind string, since time.Time) ([]time.Time, error)
+	// DeleteExpired removes the records and events that expired at or before now,
+	// returning how many records were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// RateCardRepository persists pricing rate cards. Rate cards are never edited;
+// publishing a new one supersedes the previous one.
+type RateCardRepository interface {
+	// Current returns the most recently created rate card, or ErrNotFound if none exists
+	Current(ctx context.Context) (*models.RateCard, error)
+	// Create inserts a rate card and sets its ID
+	Create(ctx context.Context, card *models.RateCard) error
+}
+
+// PackageRepository persists the package catalog
+type PackageRepository interface {
+	// Create inserts a package and sets its ID, returning ErrDuplicate if the code is taken
+	Create(ctx context.Context, pkg *models.Package) error
+	// FindByCode returns ErrNotFound if no package has the code
+	FindByCode(ctx context.Context, code string) (*models.Package, error)
+	// List returns all packages, cheapest first
+	List(ctx context.Context) ([]models.Package, error)
+	// Update saves every field of a package except its ID, code and creation time
+	Update(ctx context.Context, pkg *models.Package) error
+	// Delete removes a package by code, returning ErrNotFound if absent
+	Delete(ctx context.Context, code string) error
+}
+
+// InventoryRepository persists the equipment the band owns
+type InventoryRepository interface {
+	// List returns every inventory item ordered by code
+	List(ctx context.Context) ([]models.InventoryItem, error)
+	// Put creates or replaces the item with item.Code and sets its ID
+	Put(ctx context.Context, item *models.InventoryItem) error
+	// Delete removes an item by code, returning ErrNotFound if absent
+	Delete(ctx context.Context, code string) error
+}
+
+// TaxInvoiceRepository persists GST invoices and credit notes. Issued documents are
+// never changed or deleted.
+type TaxInvoiceRepository interface {
+	// Issue numbers a document with the next sequence of its series, kind and financial
+	// year, and inserts it and sets its ID. Both happen atomically so a series has no
+	// gaps. It returns ErrDuplicate if the booking already has a document of the kind.
+	Issue(ctx context.Context, doc *models.TaxInvoice) error
+	// FindByNumber returns ErrNotFound if no document has the number
+	FindByNumber(ctx context.Context, number string) (*models.TaxInvoice, error)
+	// ListByBooking returns a booking's documents in the order they were issued
+	ListByBooking(ctx context.Context, bookingID string) ([]models.TaxInvoice, error)
+	// List returns the documents of a financial year, of one kind if kind is not
+	// empty, in the order they were issued
+	List(ctx context.Context, financialYear, kind string) ([]models.TaxInvoice, error)
+}
+
+// NotificationFilter narrows the outbox. Empty fields match every notification.
+type NotificationFilter struct {
+	Status    string
+	BookingID string
+}
+
+// NotificationRepository persists the notification outbox
+type NotificationRepository interface {
+	// Enqueue inserts pending notifications and sets their IDs. Notifications whose
+	// Key was already queued are skipped and keep a nil ID.
+	Enqueue(ctx context.Context, notifications []models.Notification) error
+	// Claim returns up to limit pending notifications due at now, oldest first, counting
+	// an attempt on each and postponing its next attempt to leaseUntil in the same
+	// operation, so that concurrent dispatchers never claim the same notification
+	Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Notification, error)
+	// MarkSent records the delivery of a notification
+	MarkSent(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// MarkFailed records a failed attempt. The notification is retried at retryAt,
+	// or marked failed if retryAt is zero.
+	MarkFailed(ctx context.Context, id primitive.ObjectID, lastError string, retryAt time.Time) error
+	// Retry makes a failed notification pending again, due at, with its attempts reset.
+	// It returns ErrNotFound if the notification does not exist and ErrConflict if it has not failed.
+	Retry(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// List returns up to limit notifications matching filter, newest first
+	List(ctx context.Context, filter NotificationFilter, limit int) ([]models.Notification, error)
+}
+
+// JobRepository persists the schedules, locks and run history of background jobs
+type JobRepository interface {
+	// Ensure inserts the state of a job unless it already has one
+	Ensure(ctx context.Context, state *models.JobState) error
+	// List returns the state of every job, ordered by name
+	List(ctx context.Context) ([]models.JobState, error)
+	// Find returns ErrNotFound if the job has no state
+	Find(ctx context.Context, name string) (*models.JobState, error)
+	// UpdateSchedule saves a job's schedule, enabled flag, next run time and who
+	// updated them, returning ErrNotFound if the job has no state
+	UpdateSchedule(ctx context.Context, state *models.JobState) error
+	// Lock locks a job for owner until lockedUntil unless a lock, owner's own
+	// included, is still held at now, reporting whether the lock was taken. A job
+	// therefore never runs twice at once, even on one instance.
+	Lock(ctx context.Context, name, owner string, now, lockedUntil time.Time) (bool, error)
+	// LockDue is Lock for scheduled runs: the job must also be enabled and due at
+	// now, and its next run moves to next in the same operation, so that exactly
+	// one instance takes each scheduled run
+	LockDue(ctx context.Context, name, owner string, now, lockedUntil, next time.Time) (bool, error)
+	// Unlock releases owner's lock on a job and records run as its last run
+	Unlock(ctx context.Context, name, owner string, run *models.JobRun) error
+	// CreateRun inserts a run and sets its ID
+	CreateRun(ctx context.Context, run *models.JobRun) error
+	// FinishRun saves the status, result, error and finish time of a run
+	FinishRun(ctx context.Context, run *models.JobRun) error
+	// ListRuns returns up to limit runs of a job, newest first
+	ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error)
+	// DeleteRunsBefore removes the runs started before t, returning how many were removed
+	DeleteRunsBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+// SettingsRepository persists small configuration documents by key
+type SettingsRepository interface {
+	// Get decodes the document stored under key into v, returning ErrNotFound if absent
+	Get(ctx context.Context, key string, v interface{}) error
+	// Put stores v under key, replacing any previous document
+	Put(ctx context.Context, key string, v interface{}) error
+}
@@ -0,0 +1,333 @@
+package storage_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+	"github.com/modernband/booking/internal/storage"
+	"github.com/modernband/booking/internal/storage/memory"
+	"github.com/modernband/booking/internal/storage/sqlite"
+)
+
+// eachBackend runs test against a fresh store of every backend that needs no server
+func eachBackend(t *testing.T, test func(t *testing.T, store *storage.Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, memory.NewStore())
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		db, err := sqlite.Open(filepath.Join(t.TempDir(), "booking.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		test(t, sqlite.NewStore(db))
+	})
+}
+
+// verify records a successful OTP verification of phone
+func verify(t *testing.T, store *storage.Store, phone string) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+	if err := store.OTPs.Issue(ctx, phone, "hash", now, now.Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.OTPs.MarkVerified(ctx, phone, now, now.Add(15*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newBooking returns an enquiry on day in slot
+func newBooking(id, phone string, day time.Time, slot string) *models.Booking {
+	return &models.Booking{
+		BookingID: id,
+		Name:      "Customer " + id,
+		Phone:     phone,
+		EventDate: day,
+		BandTime:  slot,
+		TimeSlot:  slot,
+		City:      "Pune",
+		Status:    models.BookingStatusEnquiry,
+		CreatedAt: time.Now(),
+	}
+}
+
+// book verifies the booking's phone and inserts it within limits
+func book(t *testing.T, store *storage.Store, booking *models.Booking, limits storage.CapacityLimits) error {
+	t.Helper()
+	verify(t, store, booking.Phone)
+	return store.Bookings.CreateWithinCapacity(context.Background(), booking, limits, time.Now().Add(-time.Minute))
+}
+
+var (
+	day      = time.Date(2027, 1, 10, 0, 0, 0, 0, time.UTC)
+	otherDay = time.Date(2027, 1, 11, 0, 0, 0, 0, time.UTC)
+)
+
+func TestCreateWithinCapacity(t *testing.T) {
+	eachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		limits := storage.CapacityLimits{PerDay: 2, PerSlot: 1}
+
+		if err := book(t, store, newBooking("A00001", "9000000001", day, models.BandTimeEvening), limits); err != nil {
+			t.Fatal(err)
+		}
+		if err := book(t, store, newBooking("A00002", "9000000002", day, models.BandTimeEvening), limits); err != storage.ErrCapacityExceeded {
+			t.Fatalf("second booking in a full slot: error = %v, want %v", err, storage.ErrCapacityExceeded)
+		}
+		// The rejected booking left its phone verified
+		err := store.Bookings.CreateWithinCapacity(ctx, newBooking("A00002", "9000000002", day, models.BandTimeMorning), limits, time.Now().Add(-time.Minute))
+		if err != nil {
+			t.Fatalf("booking another slot with the same verification: %v", err)
+		}
+		if err := book(t, store, newBooking("A00003", "9000000003", day, models.BandTimeNight), limits); err != storage.ErrCapacityExceeded {
+			t.Fatalf("third booking on a full day: error = %v, want %v", err, storage.ErrCapacityExceeded)
+		}
+
+		// Cancelled bookings free their place
+		change := models.StatusChange{From: models.BookingStatusEnquiry, To: models.BookingStatusCancelled, At: time.Now(), By: "owner"}
+		if err := store.Bookings.UpdateStatus(ctx, "A00001", models.BookingStatusEnquiry, change); err != nil {
+			t.Fatal(err)
+		}
+		if err := book(t, store, newBooking("A00003", "9000000003", day, models.BandTimeEvening), limits); err != nil {
+			t.Fatalf("booking the place of a cancelled booking: %v", err)
+		}
+	})
+}
+
+func TestCreateWithinStock(t *testing.T) {
+	eachBackend(t, func(t *testing.T, store *storage.Store) {
+		limits := storage.CapacityLimits{Stock: map[string]int{models.ItemDhols: 3}}
+
+		first := newBooking("S00001", "9000000001", day, models.BandTimeMorning)
+		first.NumberOfDhols = 2
+		if err := book(t, store, first, limits); err != nil {
+			t.Fatal(err)
+		}
+		second := newBooking("S00002", "9000000002", day, models.BandTimeEvening)
+		second.NumberOfDhols = 2
+		if err := book(t, store, second, limits); err != storage.ErrInsufficientStock {
+			t.Fatalf("error = %v, want %v", err, storage.ErrInsufficientStock)
+		}
+		second.EventDate = otherDay
+		if err := book(t, store, second, limits); err != nil {
+			t.Fatalf("the same equipment on another day: %v", err)
+		}
+	})
+}
+
+func TestCreateConsumesVerification(t *testing.T) {
+	eachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		since := time.Now().Add(-time.Minute)
+
+		if err := store.Bookings.CreateWithinCapacity(ctx, newBooking("V00001", "9000000001", day, ""), storage.CapacityLimits{}, since); err != storage.ErrNotVerified {
+			t.Fatalf("unverified phone: error = %v, want %v", err, storage.ErrNotVerified)
+		}
+
+		verify(t, store, "9000000001")
+		if err := store.Bookings.CreateWithinCapacity(ctx, newBooking("V00001", "9000000001", day, ""), storage.CapacityLimits{}, since); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Bookings.CreateWithinCapacity(ctx, newBooking("V00002", "9000000001", otherDay, ""), storage.CapacityLimits{}, since); err != storage.ErrNotVerified {
+			t.Fatalf("second booking on one verification: error = %v, want %v", err, storage.ErrNotVerified)
+		}
+
+		// A verification older than verifiedSince does not count
+		verify(t, store, "9000000002")
+		if err := store.Bookings.CreateWithinCapacity(ctx, newBooking("V00003", "9000000002", day, ""), storage.CapacityLimits{}, time.Now().Add(time.Minute)); err != storage.ErrNotVerified {
+			t.Fatalf("stale verification: error = %v, want %v", err, storage.ErrNotVerified)
+		}
+		if exists, err := store.Bookings.Exists(ctx, "V00002"); err != nil || exists {
+			t.Errorf("a rejected booking was stored: %v, %v", exists, err)
+		}
+	})
+}
+
+func TestDeleteRestore(t *testing.T) {
+	eachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		now := time.Now()
+
+		employees := make([]models.Employee, 3)
+		for i := range employees {
+			employees[i] = models.Employee{Name: "Crew", Username: string(rune('a'+i)) + "crew", IsEmployee: true, CreatedAt: now}
+			if err := store.Employees.Create(ctx, &employees[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		assign := func(bookingID string, employee models.Employee) error {
+			return store.Assignments.Create(ctx, &models.CrewAssignment{
+				BookingID:  bookingID,
+				EmployeeID: employee.ID,
+				Username:   employee.Username,
+				Role:       models.CrewRoleDholPlayer,
+				EventDate:  day,
+				TimeSlot:   models.BandTimeEvening,
+				AssignedAt: now,
+			})
+		}
+		crew := func(bookingID string) []string {
+			assignments, err := store.Assignments.ListByBooking(ctx, bookingID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			usernames := []string{}
+			for _, a := range assignments {
+				usernames = append(usernames, a.Username)
+			}
+			return usernames
+		}
+
+		if err := book(t, store, newBooking("D00001", "9000000001", day, models.BandTimeEvening), storage.CapacityLimits{}); err != nil {
+			t.Fatal(err)
+		}
+		for _, employee := range employees {
+			if err := assign("D00001", employee); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := store.Bookings.Delete(ctx, "D00001", "owner", now); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Bookings.FindByBookingID(ctx, "D00001"); err != storage.ErrNotFound {
+			t.Errorf("finding a trashed booking: error = %v, want %v", err, storage.ErrNotFound)
+		}
+		if err := store.Bookings.Delete(ctx, "D00001", "owner", now); err != storage.ErrNotFound {
+			t.Errorf("deleting a trashed booking: error = %v, want %v", err, storage.ErrNotFound)
+		}
+		trashed, err := store.Bookings.FindDeleted(ctx, "D00001")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if trashed.DeletedBy != "owner" || trashed.DeletedAt == nil {
+			t.Errorf("trashed booking = %+v, want it deleted by owner", trashed)
+		}
+		if got := crew("D00001"); len(got) != 0 {
+			t.Errorf("crew of a trashed booking = %v, want none", got)
+		}
+
+		// A restore past the day's capacity leaves the booking in the trash
+		if err := book(t, store, newBooking("D00002", "9000000002", day, models.BandTimeEvening), storage.CapacityLimits{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Bookings.Restore(ctx, "D00001", &storage.CapacityLimits{PerSlot: 1}); err != storage.ErrCapacityExceeded {
+			t.Fatalf("restoring into a full slot: error = %v, want %v", err, storage.ErrCapacityExceeded)
+		}
+		if _, err := store.Bookings.FindDeleted(ctx, "D00001"); err != nil {
+			t.Fatalf("a rejected restore took the booking out of the trash: %v", err)
+		}
+
+		// Crew since assigned elsewhere at the same time or trashed are dropped
+		if err := assign("D00002", employees[0]); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Employees.Delete(ctx, employees[1].ID, "owner", now); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Bookings.Restore(ctx, "D00001", nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Bookings.FindByBookingID(ctx, "D00001"); err != nil {
+			t.Errorf("finding a restored booking: %v", err)
+		}
+		if got := crew("D00001"); len(got) != 1 || got[0] != employees[2].Username {
+			t.Errorf("restored crew = %v, want [%s]", got, employees[2].Username)
+		}
+		if err := store.Bookings.Restore(ctx, "D00001", nil); err != storage.ErrNotFound {
+			t.Errorf("restoring a live booking: error = %v, want %v", err, storage.ErrNotFound)
+		}
+
+		// Restoring the employee does not bring back the dropped assignment
+		if err := store.Employees.Restore(ctx, employees[1].Username); err != nil {
+			t.Fatal(err)
+		}
+		if got := crew("D00001"); len(got) != 1 {
+			t.Errorf("crew after restoring the employee = %v, want one", got)
+		}
+	})
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	eachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		deletedAt := time.Now().Add(-48 * time.Hour)
+
+		for _, id := range []string{"P00001", "P00002"} {
+			if err := book(t, store, newBooking(id, "9"+id[1:]+"000", day, ""), storage.CapacityLimits{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := store.Bookings.Delete(ctx, "P00001", "owner", deletedAt); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Bookings.Delete(ctx, "P00002", "owner", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+
+		purged, err := store.Bookings.PurgeDeleted(ctx, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if purged != 1 {
+			t.Errorf("purged %d bookings, want 1", purged)
+		}
+		if _, err := store.Bookings.FindDeleted(ctx, "P00001"); err != storage.ErrNotFound {
+			t.Errorf("finding a purged booking: error = %v, want %v", err, storage.ErrNotFound)
+		}
+		if _, err := store.Bookings.FindDeleted(ctx, "P00002"); err != nil {
+			t.Errorf("a recently trashed booking was purged: %v", err)
+		}
+	})
+}
+
+func TestLastOwner(t *testing.T) {
+	eachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		admin := func(username, role string) *models.AdminUser {
+			return &models.AdminUser{Name: username, Username: username, Password: "hash", IsAdminUser: true, Role: role, CreatedAt: time.Now()}
+		}
+
+		owner := admin("owner", "owner")
+		if err := store.Admins.CreateFirst(ctx, owner); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Admins.CreateFirst(ctx, admin("second", "owner")); err != storage.ErrConflict {
+			t.Fatalf("a second first admin: error = %v, want %v", err, storage.ErrConflict)
+		}
+
+		owner.Role = "manager"
+		if err := store.Admins.Update(ctx, owner); err != storage.ErrLastOwner {
+			t.Errorf("demoting the only owner: error = %v, want %v", err, storage.ErrLastOwner)
+		}
+		if err := store.Admins.Delete(ctx, owner.ID); err != storage.ErrLastOwner {
+			t.Errorf("deleting the only owner: error = %v, want %v", err, storage.ErrLastOwner)
+		}
+		stored, err := store.Admins.FindByUsername(ctx, "owner")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !stored.IsOwner() {
+			t.Errorf("the only owner was demoted to %q", stored.Role)
+		}
+
+		// An admin stored without a role predates roles and is an owner too
+		legacy := admin("legacy", "")
+		if err := store.Admins.Create(ctx, legacy); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Admins.Update(ctx, owner); err != nil {
+			t.Fatalf("demoting an owner with another owner left: %v", err)
+		}
+		if err := store.Admins.Delete(ctx, legacy.ID); err != storage.ErrLastOwner {
+			t.Errorf("deleting the last, legacy owner: error = %v, want %v", err, storage.ErrLastOwner)
+		}
+		if err := store.Admins.Delete(ctx, owner.ID); err != nil {
+			t.Errorf("deleting a manager: %v", err)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+// Package tax prepares GST invoices and credit notes for bookings. Booking amounts
+// include GST, which is split out of each charge at the configured rate: as CGST
+// and SGST when the event is in the supplier's state, or as IGST otherwise.
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/modernband/booking/internal/clock"
+	"github.com/modernband/booking/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrNotConfigured is returned when the tax settings lack the supplier's GST registration
+	ErrNotConfigured = errors.New("tax settings are not configured")
+	// ErrUnknownCity is returned when the state of a booking's city is not configured
+	ErrUnknownCity = errors.New("no state is configured for the city")
+)
+
+// FinancialYear returns the Indian financial year, April to March, containing t,
+// e.g. 2026-27
+func FinancialYear(t time.Time) string {
+	t = t.In(clock.IndianTime)
+	start := t.Year()
+	if t.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+// CityState returns the state of an event city
+func CityState(settings *models.TaxSettings, city string) (string, error) {
+	state, ok := settings.CityStates[strings.ToLower(strings.TrimSpace(city))]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCity, city)
+	}
+	return state, nil
+}
+
+// roundPaise rounds an amount in rupees to the nearest paisa
+func roundPaise(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// NewInvoice prepares an unnumbered tax invoice for a booking as it stands,
+// itemized by its quote. supplier carries the contact details printed with the
+// supplier's registration.
+func NewInvoice(settings *models.TaxSettings, supplier models.TaxParty, booking *models.Booking, by string, at time.Time) (*models.TaxInvoice, error) {
+	if settings.GSTIN == "" || settings.State == "" {
+		return nil, ErrNotConfigured
+	}
+	placeOfSupply, err := CityState(settings, booking.City)
+	if err != nil {
+		return nil, err
+	}
+
+	supplier.Name = settings.LegalName
+	supplier.GSTIN = settings.GSTIN
+	supplier.Address = settings.Address
+	supplier.State = settings.State
+
+	address := booking.City
+	if booking.Venue != "" {
+		address = booking.Venue + ", " + booking.City
+	}
+	invoice := &models.TaxInvoice{
+		Kind:          models.TaxDocumentInvoice,
+		FinancialYear: FinancialYear(at),
+		BookingID:     booking.BookingID,
+		Supplier:      supplier,
+		Recipient: models.TaxParty{
+			Name:    booking.Name,
+			Address: address,
+			State:   placeOfSupply,
+			Phone:   booking.Phone,
+			Email:   booking.Email,
+		},
+		PlaceOfSupply: placeOfSupply,
+		SAC:           settings.SAC,
+		EventDate:     booking.EventDate,
+		Total:         booking.Amount,
+		IssuedBy:      by,
+		IssuedAt:      at,
+	}
+
+	// Each charge is split at the full rate; any difference between the quote and
+	// the amount charged is invoiced as an adjustment
+	addLine := func(description string, quantity, amount int) {
+		invoice.Lines = append(invoice.Lines, models.TaxInvoiceLine{
+			Description:  description,
+			Quantity:     quantity,
+			TaxableValue: roundPaise(float64(amount) * 100 / (100 + settings.Rate)),
+			Amount:       amount,
+		})
+	}
+	quoted := 0
+	if booking.Quote != nil {
+		for _, item := range booking.Quote.Items {
+			addLine(item.Description, item.Quantity, item.Amount)
+			quoted += item.Amount
+		}
+	}
+	if len(invoice.Lines) == 0 {
+		addLine("Package: "+booking.PackageType, 1, booking.Amount)
+	} else if adjustment := booking.Amount - quoted; adjustment != 0 {
+		addLine("Adjustment", 1, adjustment)
+	}
+
+	for _, line := range invoice.Lines {
+		invoice.TaxableValue += line.TaxableValue
+	}
+	invoice.TaxableValue = roundPaise(invoice.TaxableValue)
+	tax := roundPaise(float64(invoice.Total) - invoice.TaxableValue)
+	if strings.EqualFold(placeOfSupply, settings.State) {
+		invoice.CGSTRate = settings.Rate / 2
+		invoice.SGSTRate = settings.Rate / 2
+		invoice.CGST = roundPaise(tax / 2)
+		invoice.SGST = roundPaise(tax - invoice.CGST)
+	} else {
+		invoice.IGSTRate = settings.Rate
+		invoice.IGST = tax
+	}
+	return invoice, nil
+}
+
+// NewCreditNote prepares an unnumbered credit note reversing the whole of an invoice
+func NewCreditNote(invoice *models.TaxInvoice, reason, by string, at time.Time) *models.TaxInvoice {
+	note := *invoice
+	note.ID = primitive.NilObjectID
+	note.Kind = models.TaxDocumentCreditNote
+	note.FinancialYear = FinancialYear(at)
+	note.Number = ""
+	note.Sequence = 0
+	note.OriginalNumber = invoice.Number
+	note.Reason = reason
+	note.Lines = append([]models.TaxInvoiceLine(nil), invoice.Lines...)
+	note.IssuedBy = by
+	note.IssuedAt = at
+	return &note
+}
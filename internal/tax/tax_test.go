@@ -0,0 +1,179 @@
+package tax
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/modernband/booking/internal/models"
+)
+
+func testSettings() *models.TaxSettings {
+	return &models.TaxSettings{
+		GSTIN:      "27AAAAA0000A1Z5",
+		LegalName:  "Modern Band",
+		State:      "Maharashtra",
+		Rate:       18,
+		CityStates: map[string]string{"pune": "Maharashtra", "panaji": "Goa"},
+	}
+}
+
+func TestFinancialYear(t *testing.T) {
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2026, 3, 31, 18, 29, 0, 0, time.UTC), "2025-26"},
+		{time.Date(2026, 3, 31, 18, 30, 0, 0, time.UTC), "2026-27"}, // Midnight, 1 April in India
+		{time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), "2026-27"},
+		{time.Date(2099, 6, 1, 0, 0, 0, 0, time.UTC), "2099-00"},
+	}
+	for _, tt := range tests {
+		if got := FinancialYear(tt.at); got != tt.want {
+			t.Errorf("FinancialYear(%v) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestNewInvoiceSplit(t *testing.T) {
+	tests := []struct {
+		name                         string
+		city                         string
+		taxable, cgst, sgst, igst    float64
+		cgstRate, sgstRate, igstRate float64
+	}{
+		{
+			name:    "intra-state",
+			city:    " Pune ",
+			taxable: 8474.58, cgst: 762.71, sgst: 762.71,
+			cgstRate: 9, sgstRate: 9,
+		},
+		{
+			name:    "inter-state",
+			city:    "Panaji",
+			taxable: 8474.58, igst: 1525.42,
+			igstRate: 18,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := &models.Booking{BookingID: "B1", City: tt.city, PackageType: "basic", Amount: 10000}
+			invoice, err := NewInvoice(testSettings(), models.TaxParty{}, booking, "admin", time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if invoice.TaxableValue != tt.taxable || invoice.CGST != tt.cgst || invoice.SGST != tt.sgst || invoice.IGST != tt.igst {
+				t.Errorf("taxable, CGST, SGST, IGST = %v, %v, %v, %v, want %v, %v, %v, %v",
+					invoice.TaxableValue, invoice.CGST, invoice.SGST, invoice.IGST, tt.taxable, tt.cgst, tt.sgst, tt.igst)
+			}
+			if invoice.CGSTRate != tt.cgstRate || invoice.SGSTRate != tt.sgstRate || invoice.IGSTRate != tt.igstRate {
+				t.Errorf("rates = %v, %v, %v, want %v, %v, %v",
+					invoice.CGSTRate, invoice.SGSTRate, invoice.IGSTRate, tt.cgstRate, tt.sgstRate, tt.igstRate)
+			}
+		})
+	}
+}
+
+func TestNewInvoiceRounding(t *testing.T) {
+	paise := func(amount float64) int64 { return int64(math.Round(amount * 100)) }
+
+	for _, city := range []string{"Pune", "Panaji"} {
+		for amount := 1; amount <= 2000; amount++ {
+			booking := &models.Booking{City: city, PackageType: "basic", Amount: amount}
+			invoice, err := NewInvoice(testSettings(), models.TaxParty{}, booking, "admin", time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Every figure is in whole paise and the tax makes up the rest of the amount
+			for _, value := range []float64{invoice.TaxableValue, invoice.CGST, invoice.SGST, invoice.IGST} {
+				if math.Abs(value*100-float64(paise(value))) > 1e-6 {
+					t.Fatalf("%s %d: %v is not in whole paise", city, amount, value)
+				}
+			}
+			tax := paise(invoice.CGST) + paise(invoice.SGST) + paise(invoice.IGST)
+			if paise(invoice.TaxableValue)+tax != int64(amount)*100 {
+				t.Fatalf("%s %d: taxable %v and tax %d paise do not add up to the amount", city, amount, invoice.TaxableValue, tax)
+			}
+			if diff := paise(invoice.CGST) - paise(invoice.SGST); diff < -1 || diff > 1 {
+				t.Fatalf("%s %d: CGST %v and SGST %v differ by more than a paisa", city, amount, invoice.CGST, invoice.SGST)
+			}
+		}
+	}
+}
+
+func TestNewInvoiceLines(t *testing.T) {
+	booking := &models.Booking{
+		City:   "Pune",
+		Amount: 10000, // A discount of 500 on the quote
+		Quote: &models.Quote{Items: []models.QuoteItem{
+			{Description: "Package: Basic", Quantity: 1, Amount: 10000},
+			{Description: "Extra dhols", Quantity: 1, Amount: 500},
+		}},
+	}
+	invoice, err := NewInvoice(testSettings(), models.TaxParty{}, booking, "admin", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.TaxInvoiceLine{
+		{Description: "Package: Basic", Quantity: 1, TaxableValue: 8474.58, Amount: 10000},
+		{Description: "Extra dhols", Quantity: 1, TaxableValue: 423.73, Amount: 500},
+		{Description: "Adjustment", Quantity: 1, TaxableValue: -423.73, Amount: -500},
+	}
+	if len(invoice.Lines) != len(want) {
+		t.Fatalf("lines = %+v, want %+v", invoice.Lines, want)
+	}
+	for i, line := range invoice.Lines {
+		if line != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, line, want[i])
+		}
+	}
+	if invoice.TaxableValue != 8474.58 || invoice.Total != 10000 {
+		t.Errorf("taxable, total = %v, %d, want 8474.58, 10000", invoice.TaxableValue, invoice.Total)
+	}
+}
+
+func TestNewInvoiceErrors(t *testing.T) {
+	booking := &models.Booking{City: "Nagpur", Amount: 1000}
+	if _, err := NewInvoice(testSettings(), models.TaxParty{}, booking, "admin", time.Now()); !errors.Is(err, ErrUnknownCity) {
+		t.Errorf("error = %v, want %v", err, ErrUnknownCity)
+	}
+
+	settings := testSettings()
+	settings.GSTIN = ""
+	booking.City = "Pune"
+	if _, err := NewInvoice(settings, models.TaxParty{}, booking, "admin", time.Now()); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("error = %v, want %v", err, ErrNotConfigured)
+	}
+}
+
+func TestNumbering(t *testing.T) {
+	booking := &models.Booking{BookingID: "B1", City: "Pune", PackageType: "basic", Amount: 1000}
+	march := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	invoice, err := NewInvoice(testSettings(), models.TaxParty{}, booking, "admin", march)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invoice.Number != "" || invoice.Series() != "invoice:2025-26" {
+		t.Fatalf("number, series = %q, %q, want an unnumbered invoice in invoice:2025-26", invoice.Number, invoice.Series())
+	}
+	invoice.SetSequence(7)
+	if invoice.Number != "INV2526-00007" {
+		t.Errorf("number = %q, want INV2526-00007", invoice.Number)
+	}
+
+	// A credit note is numbered in its own series, in the year it is issued
+	note := NewCreditNote(invoice, "Cancelled", "admin", march.Add(24*time.Hour))
+	if note.Number != "" || note.Series() != "credit_note:2026-27" || note.OriginalNumber != "INV2526-00007" {
+		t.Fatalf("number, series, original = %q, %q, %q", note.Number, note.Series(), note.OriginalNumber)
+	}
+	note.SetSequence(1)
+	if note.Number != "CN2627-00001" {
+		t.Errorf("number = %q, want CN2627-00001", note.Number)
+	}
+	if invoice.Number != "INV2526-00007" || invoice.Kind != models.TaxDocumentInvoice {
+		t.Errorf("the credit note changed its invoice: %+v", invoice)
+	}
+}